@@ -3,16 +3,22 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/go-code-mentor/wp-task/internal/entities"
 	"github.com/go-code-mentor/wp-task/internal/handlers"
+	"github.com/go-code-mentor/wp-task/internal/middleware/jwtauth"
 	"github.com/go-code-mentor/wp-task/internal/middleware/simpletoken"
 	"github.com/go-code-mentor/wp-task/internal/service"
+	"github.com/go-code-mentor/wp-task/internal/service/oidc"
 	"github.com/go-code-mentor/wp-task/internal/service/tgclient"
 	userservice "github.com/go-code-mentor/wp-task/internal/service/users"
 	"github.com/go-code-mentor/wp-task/internal/storage"
@@ -25,11 +31,21 @@ func New(cfg Config) *App {
 	}
 }
 
+// trashPurgeInterval is how often tasks which outlived the trash retention are removed.
+const trashPurgeInterval = time.Hour
+
+// oidcRequestTimeout limits requests to the identity provider.
+const oidcRequestTimeout = 10 * time.Second
+
 type App struct {
 	cfg    Config
 	server *fiber.App
-	conn   *pgx.Conn
 	tgConn *grpc.ClientConn
+
+	// Requests served by fiber and the trash purge running in the background take connections of
+	// the pool, a pgx connection serves one statement at a time and is never shared between them.
+	pool       *pgxpool.Pool
+	appService *service.Service
 }
 
 func (a *App) Build() error {
@@ -44,37 +60,175 @@ func (a *App) Build() error {
 
 	a.server = fiber.New()
 
-	appStorage := storage.New(a.conn)
+	appStorage := storage.New(a.pool)
 
-	userService := userservice.New(appStorage)
+	userService := userservice.New(appStorage, a.cfg.token_hash_key)
+	count, err := userService.RehashTokens(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to rehash access tokens: %w", err)
+	}
+	if count > 0 {
+		log.Infof("rehashed %d plaintext access tokens", count)
+	}
 	authMiddleware := simpletoken.AuthMiddleware{Service: userService}
+	if len(a.cfg.jwt_keys) > 0 {
+		verifier, err := jwtauth.New(a.cfg.jwt_keys, a.cfg.jwt_audience, a.cfg.jwt_login_claim)
+		if err != nil {
+			return fmt.Errorf("failed to create jwt verifier: %w", err)
+		}
+		authMiddleware.Chain = append(authMiddleware.Chain, verifier)
+	}
+
+	// Login routes are public, they are registered before the auth middleware
+	if a.cfg.oidc_config.Issuer != "" {
+		client := &http.Client{Timeout: oidcRequestTimeout}
+		oidcService, err := oidc.New(context.Background(), a.cfg.oidc_config, appStorage, userService, client)
+		if err != nil {
+			return fmt.Errorf("failed to set up oidc login: %w", err)
+		}
+		oidcHandler := handlers.OIDCHandler{
+			Service:      oidcService,
+			SecureCookie: strings.HasPrefix(a.cfg.oidc_config.RedirectURL, "https://"),
+		}
+		a.server.Get("/api/v1/auth/oidc/login", oidcHandler.LoginHandler)
+		a.server.Get("/api/v1/auth/oidc/callback", oidcHandler.CallbackHandler)
+	}
+
 	a.server.Use(authMiddleware.Auth)
 
 	tgBot := tgapi.NewTgBotClient(a.tgConn)
 	tgService := tgclient.New(tgBot)
 	appService := service.New(appStorage, tgService)
+	if a.cfg.task_transitions != "" {
+		transitions, err := service.ParseTransitionGraph(a.cfg.task_transitions)
+		if err != nil {
+			return fmt.Errorf("failed to parse task transitions: %w", err)
+		}
+		appService.Transitions = transitions
+	}
+	a.appService = appService
 	tasksHandler := handlers.TasksHandler{Service: appService}
+	labelsHandler := handlers.LabelsHandler{Service: appService}
+	dependenciesHandler := handlers.DependenciesHandler{Service: appService}
+	seriesHandler := handlers.SeriesHandler{Service: appService}
+	commentsHandler := handlers.CommentsHandler{Service: appService}
+	historyHandler := handlers.HistoryHandler{Service: appService}
+	checklistHandler := handlers.ChecklistHandler{Service: appService}
+	timeHandler := handlers.TimeHandler{Service: appService}
+	effortHandler := handlers.EffortHandler{Service: appService}
+	projectsHandler := handlers.ProjectsHandler{Service: appService}
+	boardsHandler := handlers.BoardsHandler{Service: appService}
+	taskSharesHandler := handlers.SharesHandler{Service: appService}
+	projectSharesHandler := handlers.SharesHandler{Service: appService, Project: true}
+	orgsHandler := handlers.OrgsHandler{Service: appService}
+	trashHandler := handlers.TrashHandler{Service: appService}
+	tokensHandler := handlers.TokensHandler{Service: userService}
+
+	// Every route declares the scope the token must have
+	read := simpletoken.RequireScope(entities.ScopeRead)
+	tasksWrite := simpletoken.RequireScope(entities.ScopeTasksWrite)
+	admin := simpletoken.RequireScope(entities.ScopeAdmin)
 
 	api := a.server.Group("/api")
 	v1 := api.Group("/v1")
 
-	v1.Get("/tasks", tasksHandler.ListHandler)
-	v1.Get("/tasks/:id", tasksHandler.ItemHandler)
-	v1.Put("/tasks/:id", tasksHandler.UpdateHandler)
-	v1.Post("/tasks", tasksHandler.AddHandler)
-	v1.Delete("/tasks/:id", tasksHandler.RemoveHandler)
+	v1.Get("/tasks", read, tasksHandler.ListHandler)
+	v1.Get("/tasks/overdue", read, tasksHandler.OverdueHandler)
+	v1.Get("/tasks/upcoming", read, tasksHandler.UpcomingHandler)
+	v1.Get("/tasks/order", read, dependenciesHandler.OrderHandler)
+	v1.Get("/tasks/:id", read, tasksHandler.ItemHandler)
+	v1.Put("/tasks/:id", tasksWrite, tasksHandler.UpdateHandler)
+	v1.Post("/tasks", tasksWrite, tasksHandler.AddHandler)
+	v1.Post("/tasks/:id/transitions", tasksWrite, tasksHandler.TransitionHandler)
+	v1.Get("/tasks/:id/children", read, tasksHandler.ChildrenHandler)
+	v1.Get("/tasks/:id/tree", read, tasksHandler.TreeHandler)
+	v1.Get("/tasks/:id/effort", read, effortHandler.TaskHandler)
+	v1.Get("/tasks/:id/occurrences", read, seriesHandler.OccurrencesHandler)
+	v1.Put("/tasks/:id/series", tasksWrite, seriesHandler.UpdateHandler)
+	v1.Get("/tasks/:id/history", read, historyHandler.ListHandler)
+	v1.Post("/tasks/:id/history/:eventId/revert", tasksWrite, historyHandler.RevertHandler)
+	v1.Get("/tasks/:id/comments", read, commentsHandler.ListHandler)
+	v1.Post("/tasks/:id/comments", tasksWrite, commentsHandler.AddHandler)
+	v1.Put("/tasks/:id/comments/:cid", tasksWrite, commentsHandler.UpdateHandler)
+	v1.Delete("/tasks/:id/comments/:cid", tasksWrite, commentsHandler.RemoveHandler)
+	v1.Get("/tasks/:id/checklist", read, checklistHandler.ListHandler)
+	v1.Post("/tasks/:id/checklist", tasksWrite, checklistHandler.AddHandler)
+	v1.Put("/tasks/:id/checklist/order", tasksWrite, checklistHandler.ReorderHandler)
+	v1.Post("/tasks/:id/checklist/:itemId/toggle", tasksWrite, checklistHandler.ToggleHandler)
+	v1.Delete("/tasks/:id/checklist/:itemId", tasksWrite, checklistHandler.RemoveHandler)
+	v1.Post("/tasks/:id/timer/start", tasksWrite, timeHandler.StartHandler)
+	v1.Post("/tasks/:id/timer/stop", tasksWrite, timeHandler.StopHandler)
+	v1.Get("/tasks/:id/time-entries", read, timeHandler.ListHandler)
+	v1.Post("/tasks/:id/time-entries", tasksWrite, timeHandler.AddHandler)
+	v1.Put("/tasks/:id/time-entries/:entryId", tasksWrite, timeHandler.UpdateHandler)
+	v1.Delete("/tasks/:id/time-entries/:entryId", tasksWrite, timeHandler.RemoveHandler)
+	v1.Put("/tasks/:id/labels/:labelId", tasksWrite, labelsHandler.AttachHandler)
+	v1.Delete("/tasks/:id/labels/:labelId", tasksWrite, labelsHandler.DetachHandler)
+	v1.Post("/tasks/:id/move", tasksWrite, boardsHandler.MoveHandler)
+	v1.Put("/tasks/:id/blockers/:blockerId", tasksWrite, dependenciesHandler.AddHandler)
+	v1.Delete("/tasks/:id/blockers/:blockerId", tasksWrite, dependenciesHandler.RemoveHandler)
+	v1.Get("/tasks/:id/shares", read, taskSharesHandler.ListHandler)
+	v1.Put("/tasks/:id/shares/:login", admin, taskSharesHandler.SetHandler)
+	v1.Delete("/tasks/:id/shares/:login", admin, taskSharesHandler.RemoveHandler)
+
+	v1.Get("/projects", read, projectsHandler.ListHandler)
+	v1.Get("/projects/:id", read, projectsHandler.ItemHandler)
+	v1.Post("/projects", tasksWrite, projectsHandler.AddHandler)
+	v1.Put("/projects/:id", tasksWrite, projectsHandler.UpdateHandler)
+	v1.Delete("/projects/:id", tasksWrite, projectsHandler.RemoveHandler)
+	v1.Get("/projects/:id/tasks", read, projectsHandler.TasksHandler)
+	v1.Get("/projects/:id/shares", read, projectSharesHandler.ListHandler)
+	v1.Put("/projects/:id/shares/:login", admin, projectSharesHandler.SetHandler)
+	v1.Delete("/projects/:id/shares/:login", admin, projectSharesHandler.RemoveHandler)
+
+	v1.Get("/orgs", read, orgsHandler.ListHandler)
+	v1.Post("/orgs", admin, orgsHandler.AddHandler)
+	v1.Get("/orgs/:id/members", read, orgsHandler.MembersHandler)
+	v1.Put("/orgs/:id/members/:login", admin, orgsHandler.MemberSetHandler)
+	v1.Delete("/orgs/:id/members/:login", admin, orgsHandler.MemberRemoveHandler)
+
+	v1.Get("/trash", read, trashHandler.ListHandler)
+	v1.Post("/trash/:id/restore", tasksWrite, trashHandler.RestoreHandler)
+	v1.Delete("/trash/:id", tasksWrite, trashHandler.RemoveHandler)
+
+	v1.Get("/tokens", read, tokensHandler.ListHandler)
+	v1.Post("/tokens", admin, tokensHandler.AddHandler)
+	v1.Delete("/tokens/:id", admin, tokensHandler.RemoveHandler)
+
+	v1.Get("/boards", read, boardsHandler.ListHandler)
+	v1.Get("/boards/:id", read, boardsHandler.ItemHandler)
+	v1.Post("/boards", admin, boardsHandler.AddHandler)
+	v1.Delete("/boards/:id", admin, boardsHandler.RemoveHandler)
+	v1.Put("/boards/:id/columns/:columnId", admin, boardsHandler.ColumnUpdateHandler)
+
+	v1.Get("/reports/time", read, timeHandler.ReportHandler)
+	v1.Get("/reports/estimates", read, effortHandler.AccuracyHandler)
+
+	v1.Get("/labels", read, labelsHandler.ListHandler)
+	v1.Get("/labels/:id", read, labelsHandler.ItemHandler)
+	v1.Put("/labels/:id", tasksWrite, labelsHandler.UpdateHandler)
+	v1.Post("/labels", tasksWrite, labelsHandler.AddHandler)
+	v1.Delete("/labels/:id", tasksWrite, labelsHandler.RemoveHandler)
+	v1.Delete("/tasks/:id", tasksWrite, tasksHandler.RemoveHandler)
 
 	return nil
 }
 
 func (a *App) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	purged := make(chan struct{})
+	go func() {
+		defer close(purged)
+		a.purgeTrash(ctx)
+	}()
+
 	defer func() {
-		err := a.conn.Close(context.Background())
-		if err != nil {
-			log.Errorf("failed to close db connection: %s", err)
-		}
+		cancel()
+		<-purged
 
-		err = a.tgConn.Close()
+		a.pool.Close()
+
+		err := a.tgConn.Close()
 		if err != nil {
 			log.Errorf("failed to close tg bot connection: %s", err)
 		}
@@ -82,18 +236,40 @@ func (a *App) Run() error {
 	return a.server.Listen(":3000")
 }
 
+// purgeTrash removes tasks which stay in trash longer than the retention period until the context is done.
+func (a *App) purgeTrash(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := a.appService.TrashPurge(ctx, a.cfg.trash_retention)
+		if err != nil {
+			log.Errorf("failed to purge trash: %s", err)
+		} else if count > 0 {
+			log.Infof("purged %d tasks from trash", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (a *App) connectDb() error {
 
-	conn, err := pgx.Connect(context.Background(), a.cfg.pg_uri)
+	pool, err := pgxpool.New(context.Background(), a.cfg.pg_uri)
 	if err != nil {
 		return fmt.Errorf("could not connect db: %w", err)
 	}
 
-	if err := conn.Ping(context.Background()); err != nil {
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
 		return fmt.Errorf("could not ping db: %w", err)
 	}
 
-	a.conn = conn
+	a.pool = pool
 
 	return nil
 }
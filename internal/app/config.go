@@ -2,8 +2,13 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/middleware/jwtauth"
+	"github.com/go-code-mentor/wp-task/internal/service/oidc"
 )
 
 func ParseConfig() (Config, error) {
@@ -17,12 +22,44 @@ func ParseConfig() (Config, error) {
 		return cfg, err
 	}
 
+	if err := cfg.parseTasks(); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.parseTrash(); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.parseTokens(); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.parseJWT(); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.parseOIDC(); err != nil {
+		return cfg, err
+	}
+
 	return cfg, nil
 }
 
 type Config struct {
 	pg_uri string
 	tg_uri string
+
+	task_transitions string
+
+	trash_retention time.Duration
+
+	token_hash_key []byte
+
+	jwt_keys        []jwtauth.Key
+	jwt_audience    string
+	jwt_login_claim string
+
+	oidc_config oidc.Config
 }
 
 func (c *Config) ConnString() string {
@@ -67,3 +104,149 @@ func (c *Config) parseTg() error {
 
 	return nil
 }
+
+type ConfigTasks struct {
+	Transitions string `yaml:"task_transitions" env:"TASK_TRANSITIONS"`
+}
+
+func (c *Config) parseTasks() error {
+
+	var cfg ConfigTasks
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return err
+	}
+
+	c.task_transitions = cfg.Transitions
+
+	return nil
+}
+
+type ConfigTrash struct {
+	Retention time.Duration `yaml:"trash_retention" env:"TRASH_RETENTION" env-default:"720h"`
+}
+
+func (c *Config) parseTrash() error {
+
+	var cfg ConfigTrash
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return err
+	}
+
+	if cfg.Retention <= 0 {
+		return fmt.Errorf("trash retention must be positive: %s", cfg.Retention)
+	}
+	c.trash_retention = cfg.Retention
+
+	return nil
+}
+
+// minTokenHashKeyLength keeps the key of access token hashes as strong as the hash itself.
+const minTokenHashKeyLength = 32
+
+type ConfigTokens struct {
+	HashKey string `yaml:"token_hash_key" env:"TOKEN_HASH_KEY"`
+}
+
+func (c *Config) parseTokens() error {
+
+	var cfg ConfigTokens
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return err
+	}
+
+	if len(cfg.HashKey) < minTokenHashKeyLength {
+		return fmt.Errorf("token hash key must be at least %d bytes long", minTokenHashKeyLength)
+	}
+	c.token_hash_key = []byte(cfg.HashKey)
+
+	return nil
+}
+
+// ConfigJWT enables bearer JWT authentication when any key is given, keys of all sources are used together.
+// Tokens naming the organization in the org_id claim work in it without a database lookup on every request,
+// tokens without the claim work in the organization of the X-Org-ID header or in the personal one.
+type ConfigJWT struct {
+	Secret        string `yaml:"jwt_secret" env:"JWT_SECRET"`
+	PublicKeyFile string `yaml:"jwt_public_key_file" env:"JWT_PUBLIC_KEY_FILE"`
+	JWKSFile      string `yaml:"jwt_jwks_file" env:"JWT_JWKS_FILE"`
+	Audience      string `yaml:"jwt_audience" env:"JWT_AUDIENCE"`
+	LoginClaim    string `yaml:"jwt_login_claim" env:"JWT_LOGIN_CLAIM" env-default:"sub"`
+}
+
+func (c *Config) parseJWT() error {
+
+	var cfg ConfigJWT
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return err
+	}
+
+	var keys []jwtauth.Key
+	if cfg.Secret != "" {
+		if len(cfg.Secret) < minTokenHashKeyLength {
+			return fmt.Errorf("jwt secret must be at least %d bytes long", minTokenHashKeyLength)
+		}
+		keys = append(keys, jwtauth.Key{Key: []byte(cfg.Secret)})
+	}
+	if cfg.PublicKeyFile != "" {
+		key, err := jwtauth.LoadPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	if cfg.JWKSFile != "" {
+		jwks, err := jwtauth.LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, jwks...)
+	}
+
+	if len(keys) > 0 && cfg.Audience == "" {
+		return fmt.Errorf("jwt audience must be set when jwt keys are given")
+	}
+	c.jwt_keys = keys
+	c.jwt_audience = cfg.Audience
+	c.jwt_login_claim = cfg.LoginClaim
+
+	return nil
+}
+
+// ConfigOIDC enables the identity provider login when the issuer is given.
+type ConfigOIDC struct {
+	Issuer          string        `yaml:"oidc_issuer" env:"OIDC_ISSUER"`
+	ClientID        string        `yaml:"oidc_client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret    string        `yaml:"oidc_client_secret" env:"OIDC_CLIENT_SECRET"`
+	RedirectURL     string        `yaml:"oidc_redirect_url" env:"OIDC_REDIRECT_URL"`
+	LoginClaim      string        `yaml:"oidc_login_claim" env:"OIDC_LOGIN_CLAIM" env-default:"preferred_username"`
+	SessionLifetime time.Duration `yaml:"oidc_session_lifetime" env:"OIDC_SESSION_LIFETIME" env-default:"24h"`
+}
+
+func (c *Config) parseOIDC() error {
+
+	var cfg ConfigOIDC
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return err
+	}
+
+	if cfg.Issuer == "" {
+		return nil
+	}
+	if cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return fmt.Errorf("oidc client id and redirect url must be set when oidc issuer is given")
+	}
+	if cfg.SessionLifetime <= 0 || cfg.SessionLifetime > entities.MaxTokenLifetime {
+		return fmt.Errorf("oidc session lifetime must be positive and at most %s: %s", entities.MaxTokenLifetime, cfg.SessionLifetime)
+	}
+
+	c.oidc_config = oidc.Config{
+		Issuer:          cfg.Issuer,
+		ClientID:        cfg.ClientID,
+		ClientSecret:    cfg.ClientSecret,
+		RedirectURL:     cfg.RedirectURL,
+		LoginClaim:      cfg.LoginClaim,
+		SessionLifetime: cfg.SessionLifetime,
+	}
+
+	return nil
+}
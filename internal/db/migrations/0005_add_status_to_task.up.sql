@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN status varchar(32) not null default 'todo'
+    CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled'));
+
+CREATE INDEX IF NOT EXISTS tasks_owner_status_idx ON tasks (owner, status);
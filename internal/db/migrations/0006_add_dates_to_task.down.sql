@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_owner_due_at_idx;
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_due_after_start;
+ALTER TABLE tasks DROP COLUMN due_at;
+ALTER TABLE tasks DROP COLUMN start_at;
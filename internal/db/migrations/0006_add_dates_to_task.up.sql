@@ -0,0 +1,6 @@
+ALTER TABLE tasks ADD COLUMN start_at timestamptz;
+ALTER TABLE tasks ADD COLUMN due_at timestamptz;
+ALTER TABLE tasks ADD CONSTRAINT tasks_due_after_start CHECK (due_at IS NULL OR start_at IS NULL OR due_at >= start_at);
+
+CREATE INDEX IF NOT EXISTS tasks_owner_due_at_idx ON tasks (owner, due_at)
+    WHERE due_at IS NOT NULL AND status NOT IN ('done', 'cancelled');
@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN priority smallint not null default 2 CHECK (priority BETWEEN 0 AND 3);
+
+CREATE INDEX IF NOT EXISTS tasks_owner_priority_idx ON tasks (owner, priority, id);
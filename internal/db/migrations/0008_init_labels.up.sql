@@ -0,0 +1,17 @@
+create table if not exists labels
+(
+    id BIGSERIAL primary key,
+    owner varchar(64) not null,
+    name varchar(64) not null,
+    color varchar(16) not null default '',
+    unique (owner, name)
+);
+
+create table if not exists task_labels
+(
+    task_id bigint references tasks(id) on delete cascade not null,
+    label_id bigint references labels(id) on delete cascade not null,
+    primary key (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_labels_label_id_idx ON task_labels (label_id);
@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN parent_id bigint references tasks(id);
+ALTER TABLE tasks ADD CONSTRAINT tasks_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS tasks_parent_id_idx ON tasks (parent_id) WHERE parent_id IS NOT NULL;
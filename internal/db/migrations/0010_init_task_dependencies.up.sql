@@ -0,0 +1,9 @@
+create table if not exists task_dependencies
+(
+    task_id bigint references tasks(id) on delete cascade not null,
+    blocker_id bigint references tasks(id) on delete cascade not null,
+    primary key (task_id, blocker_id),
+    check (task_id <> blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_dependencies_blocker_id_idx ON task_dependencies (blocker_id);
@@ -0,0 +1,11 @@
+create table if not exists task_series
+(
+    id BIGSERIAL primary key,
+    owner varchar(64) not null,
+    rule varchar(256) not null,
+    start_at timestamptz not null
+);
+
+ALTER TABLE tasks ADD COLUMN series_id bigint references task_series(id) on delete set null;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_series_id_due_at_idx ON tasks (series_id, due_at) WHERE series_id IS NOT NULL;
@@ -0,0 +1,11 @@
+create table if not exists comments
+(
+    id BIGSERIAL primary key,
+    task_id bigint references tasks(id) on delete cascade not null,
+    author varchar(64) not null,
+    body text not null,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS comments_task_id_idx ON comments (task_id, id);
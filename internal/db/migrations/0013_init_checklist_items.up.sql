@@ -0,0 +1,10 @@
+create table if not exists checklist_items
+(
+    id BIGSERIAL primary key,
+    task_id bigint references tasks(id) on delete cascade not null,
+    title varchar(256) not null,
+    done boolean not null default false,
+    position integer not null
+);
+
+CREATE INDEX IF NOT EXISTS checklist_items_task_id_idx ON checklist_items (task_id, position);
@@ -0,0 +1,15 @@
+create table if not exists time_entries
+(
+    id BIGSERIAL primary key,
+    task_id bigint references tasks(id) on delete cascade not null,
+    login varchar(64) not null,
+    started_at timestamptz not null,
+    stopped_at timestamptz,
+    note text not null default '',
+    check (stopped_at IS NULL OR stopped_at >= started_at)
+);
+
+-- At most one running timer per user
+CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_idx ON time_entries (login) WHERE stopped_at IS NULL;
+CREATE INDEX IF NOT EXISTS time_entries_login_started_at_idx ON time_entries (login, started_at);
+CREATE INDEX IF NOT EXISTS time_entries_task_id_idx ON time_entries (task_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS tasks_owner_completed_at_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS remaining_estimate;
+ALTER TABLE tasks DROP COLUMN IF EXISTS original_estimate;
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimate_unit;
@@ -0,0 +1,6 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_unit varchar(16) CHECK (estimate_unit IN ('minutes', 'points'));
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS original_estimate integer CHECK (original_estimate >= 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS remaining_estimate integer CHECK (remaining_estimate >= 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS tasks_owner_completed_at_idx ON tasks (owner, completed_at) WHERE completed_at IS NOT NULL;
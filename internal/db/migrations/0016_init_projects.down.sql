@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tasks_project_number_idx;
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_project_number;
+ALTER TABLE tasks DROP COLUMN IF EXISTS project_number;
+ALTER TABLE tasks DROP COLUMN IF EXISTS project_id;
+
+drop table if exists projects;
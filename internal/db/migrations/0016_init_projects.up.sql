@@ -0,0 +1,17 @@
+create table if not exists projects
+(
+    id BIGSERIAL primary key,
+    owner varchar(64) not null,
+    key varchar(10) not null,
+    name varchar(128) not null,
+    description text not null default '',
+    archived boolean not null default false,
+    next_number integer not null default 1,
+    unique (owner, key)
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id bigint references projects(id);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_number integer;
+ALTER TABLE tasks ADD CONSTRAINT tasks_project_number CHECK ((project_id IS NULL) = (project_number IS NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_project_number_idx ON tasks (project_id, project_number) WHERE project_id IS NOT NULL;
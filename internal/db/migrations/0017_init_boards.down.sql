@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tasks_owner_status_rank_idx;
+DROP INDEX IF EXISTS boards_owner_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS rank;
+
+drop table if exists board_columns;
+drop table if exists boards;
@@ -0,0 +1,25 @@
+create table if not exists boards
+(
+    id BIGSERIAL primary key,
+    owner varchar(64) not null,
+    name varchar(128) not null,
+    project_id bigint references projects(id) on delete cascade
+);
+
+create table if not exists board_columns
+(
+    id BIGSERIAL primary key,
+    board_id bigint references boards(id) on delete cascade not null,
+    name varchar(64) not null,
+    status varchar(32) not null CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled')),
+    position integer not null,
+    wip_limit integer CHECK (wip_limit > 0),
+    unique (board_id, status),
+    unique (board_id, position)
+);
+
+-- Rank orders tasks inside board columns, keys are compared byte by byte
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank text COLLATE "C";
+
+CREATE INDEX IF NOT EXISTS boards_owner_idx ON boards (owner);
+CREATE INDEX IF NOT EXISTS tasks_owner_status_rank_idx ON tasks (owner, status, rank);
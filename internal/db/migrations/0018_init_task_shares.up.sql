@@ -0,0 +1,13 @@
+create table if not exists task_shares
+(
+    id BIGSERIAL primary key,
+    login varchar(64) not null,
+    task_id bigint references tasks(id) on delete cascade,
+    project_id bigint references projects(id) on delete cascade,
+    role varchar(16) not null check (role in ('viewer', 'editor')),
+    check ((task_id IS NULL) <> (project_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS task_shares_task_idx ON task_shares (task_id, login) WHERE task_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS task_shares_project_idx ON task_shares (project_id, login) WHERE project_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS task_shares_login_idx ON task_shares (login);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tasks_assignee_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS reporter;
+ALTER TABLE tasks DROP COLUMN IF EXISTS assignee;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee varchar(64);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS reporter varchar(64);
+
+CREATE INDEX IF NOT EXISTS tasks_assignee_idx ON tasks (assignee) WHERE assignee IS NOT NULL;
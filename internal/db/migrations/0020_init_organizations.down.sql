@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS boards_org_id_idx;
+DROP INDEX IF EXISTS tasks_org_id_idx;
+
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_org_id_owner_key_key;
+ALTER TABLE projects ADD CONSTRAINT projects_owner_key_key UNIQUE (owner, key);
+ALTER TABLE labels DROP CONSTRAINT IF EXISTS labels_org_id_owner_name_key;
+ALTER TABLE labels ADD CONSTRAINT labels_owner_name_key UNIQUE (owner, name);
+
+ALTER TABLE boards DROP COLUMN IF EXISTS org_id;
+ALTER TABLE labels DROP COLUMN IF EXISTS org_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS org_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS org_id;
+
+drop table if exists memberships;
+drop table if exists organizations;
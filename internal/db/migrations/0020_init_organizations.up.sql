@@ -0,0 +1,52 @@
+create table if not exists organizations
+(
+    id BIGSERIAL primary key,
+    name varchar(128) not null,
+    personal_login varchar(64) unique
+);
+
+create table if not exists memberships
+(
+    org_id bigint references organizations(id) on delete cascade not null,
+    login varchar(64) not null,
+    role varchar(16) not null check (role in ('owner', 'admin', 'member')),
+    primary key (org_id, login)
+);
+
+CREATE INDEX IF NOT EXISTS memberships_login_idx ON memberships (login);
+
+-- Every known login gets a personal organization which takes over its existing data
+INSERT INTO organizations (name, personal_login)
+SELECT login, login FROM (
+    SELECT login FROM users
+    UNION SELECT owner FROM tasks
+    UNION SELECT owner FROM projects
+    UNION SELECT owner FROM labels
+    UNION SELECT owner FROM boards
+) AS logins;
+
+INSERT INTO memberships (org_id, login, role) SELECT id, personal_login, 'owner' FROM organizations;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS org_id bigint references organizations(id) on delete cascade;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS org_id bigint references organizations(id) on delete cascade;
+ALTER TABLE labels ADD COLUMN IF NOT EXISTS org_id bigint references organizations(id) on delete cascade;
+ALTER TABLE boards ADD COLUMN IF NOT EXISTS org_id bigint references organizations(id) on delete cascade;
+
+UPDATE tasks SET org_id = o.id FROM organizations o WHERE o.personal_login = tasks.owner;
+UPDATE projects SET org_id = o.id FROM organizations o WHERE o.personal_login = projects.owner;
+UPDATE labels SET org_id = o.id FROM organizations o WHERE o.personal_login = labels.owner;
+UPDATE boards SET org_id = o.id FROM organizations o WHERE o.personal_login = boards.owner;
+
+ALTER TABLE tasks ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE projects ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE labels ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE boards ALTER COLUMN org_id SET NOT NULL;
+
+-- Names of labels and keys of projects are unique within the organization
+ALTER TABLE labels DROP CONSTRAINT IF EXISTS labels_owner_name_key;
+ALTER TABLE labels ADD CONSTRAINT labels_org_id_owner_name_key UNIQUE (org_id, owner, name);
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_owner_key_key;
+ALTER TABLE projects ADD CONSTRAINT projects_org_id_owner_key_key UNIQUE (org_id, owner, key);
+
+CREATE INDEX IF NOT EXISTS tasks_org_id_idx ON tasks (org_id);
+CREATE INDEX IF NOT EXISTS boards_org_id_idx ON boards (org_id);
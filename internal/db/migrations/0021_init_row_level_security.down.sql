@@ -0,0 +1,14 @@
+DROP POLICY IF EXISTS boards_tenant ON boards;
+DROP POLICY IF EXISTS labels_tenant ON labels;
+DROP POLICY IF EXISTS projects_tenant ON projects;
+DROP POLICY IF EXISTS tasks_tenant ON tasks;
+
+ALTER TABLE boards DISABLE ROW LEVEL SECURITY;
+ALTER TABLE labels DISABLE ROW LEVEL SECURITY;
+ALTER TABLE projects DISABLE ROW LEVEL SECURITY;
+ALTER TABLE tasks DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS tenant_visible(bigint);
+
+DROP OWNED BY wp_task_tenant;
+DROP ROLE IF EXISTS wp_task_tenant;
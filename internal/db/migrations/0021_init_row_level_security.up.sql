@@ -0,0 +1,38 @@
+-- Storage switches to the tenant role in every transaction, row level security applies to it even when
+-- the application connects as a superuser or as the owner of the tables. The connecting user must be
+-- granted the role, the user running migrations is granted it here.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'wp_task_tenant') THEN
+        CREATE ROLE wp_task_tenant NOLOGIN;
+    END IF;
+END
+$$;
+
+GRANT wp_task_tenant TO CURRENT_USER;
+
+GRANT USAGE ON SCHEMA public TO wp_task_tenant;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO wp_task_tenant;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO wp_task_tenant;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO wp_task_tenant;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO wp_task_tenant;
+
+-- Rows of the organization are visible when it is the active organization of the request and the
+-- request login is its member, storage sets app.org_id and app.login in every transaction.
+-- Policies of organization scoped tables are built on it.
+CREATE OR REPLACE FUNCTION tenant_visible(org bigint) RETURNS boolean
+    LANGUAGE sql STABLE
+AS $$
+    SELECT org = NULLIF(current_setting('app.org_id', true), '')::bigint
+        AND EXISTS (SELECT 1 FROM memberships m WHERE m.org_id = org AND m.login = current_setting('app.login', true))
+$$;
+
+ALTER TABLE tasks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE projects ENABLE ROW LEVEL SECURITY;
+ALTER TABLE labels ENABLE ROW LEVEL SECURITY;
+ALTER TABLE boards ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tasks_tenant ON tasks USING (tenant_visible(org_id));
+CREATE POLICY projects_tenant ON projects USING (tenant_visible(org_id));
+CREATE POLICY labels_tenant ON labels USING (tenant_visible(org_id));
+CREATE POLICY boards_tenant ON boards USING (tenant_visible(org_id));
@@ -0,0 +1,12 @@
+create table if not exists task_events
+(
+    id BIGSERIAL primary key,
+    task_id bigint references tasks(id) on delete cascade not null,
+    author varchar(64) not null,
+    field varchar(32) not null CHECK (field IN ('name', 'description')),
+    old_value text not null,
+    new_value text not null,
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS task_events_task_id_idx ON task_events (task_id, id);
@@ -0,0 +1,10 @@
+-- Trashed tasks would come back alive without the column
+UPDATE tasks SET parent_id = NULL WHERE parent_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL);
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS tasks_series_id_due_at_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_series_id_due_at_idx ON tasks (series_id, due_at) WHERE series_id IS NOT NULL;
+
+DROP INDEX IF EXISTS tasks_deleted_at_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,7 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Trashed occurrence does not take the place of a live one
+DROP INDEX IF EXISTS tasks_series_id_due_at_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_series_id_due_at_idx ON tasks (series_id, due_at) WHERE series_id IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS access_tokens_user_id_idx;
+
+-- Revoked and expired tokens would become valid again
+DELETE FROM access_tokens WHERE revoked_at IS NOT NULL OR expires_at <= now();
+
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS created_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS name;
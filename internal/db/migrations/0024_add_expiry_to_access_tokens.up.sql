@@ -0,0 +1,7 @@
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS name varchar(128) not null default '';
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS created_at timestamptz not null default now();
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS last_used_at timestamptz;
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS revoked_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS access_tokens_user_id_idx ON access_tokens (user_id);
@@ -0,0 +1,7 @@
+-- Hashed tokens can not be turned back into plaintext ones
+DELETE FROM access_tokens WHERE token IS NULL;
+
+ALTER TABLE access_tokens DROP CONSTRAINT IF EXISTS access_tokens_secret_check;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS token_prefix;
+ALTER TABLE access_tokens ALTER COLUMN token SET NOT NULL;
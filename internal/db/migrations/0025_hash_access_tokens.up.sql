@@ -0,0 +1,6 @@
+-- Tokens are kept as a keyed hash, the key is known to the application only. Plaintext tokens stay
+-- until the application rehashes them on startup, a row always has one of them.
+ALTER TABLE access_tokens ALTER COLUMN token DROP NOT NULL;
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS token_prefix varchar(16) not null default '';
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS token_hash varchar(64) unique;
+ALTER TABLE access_tokens ADD CONSTRAINT access_tokens_secret_check CHECK (token IS NOT NULL OR token_hash IS NOT NULL);
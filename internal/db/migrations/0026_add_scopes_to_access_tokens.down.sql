@@ -0,0 +1,5 @@
+-- Tokens get full power back, revoke limited ones to keep them from gaining it
+UPDATE access_tokens SET revoked_at = now() WHERE revoked_at IS NULL AND NOT ('admin' = ANY (scopes));
+
+ALTER TABLE access_tokens DROP CONSTRAINT IF EXISTS access_tokens_scopes_check;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS scopes;
@@ -0,0 +1,5 @@
+-- Tokens issued before scopes keep full power over the data of the user
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS scopes text[] not null default '{admin}';
+ALTER TABLE access_tokens ALTER COLUMN scopes SET DEFAULT '{read}';
+ALTER TABLE access_tokens ADD CONSTRAINT access_tokens_scopes_check
+    CHECK (cardinality(scopes) > 0 AND scopes <@ ARRAY['read', 'tasks:write', 'admin']::text[]);
@@ -0,0 +1,19 @@
+-- Users signed in with an identity provider, the subject is unique within the issuer only
+create table if not exists identities
+(
+    id BIGSERIAL primary key,
+    issuer varchar(256) not null,
+    subject varchar(256) not null,
+    user_id bigint references users(id) on delete cascade not null,
+    created_at timestamptz not null default now(),
+    unique (issuer, subject)
+);
+
+-- Pending logins, a state is taken once when the provider redirects back
+create table if not exists oidc_states
+(
+    state varchar(64) primary key,
+    verifier varchar(128) not null,
+    nonce varchar(64) not null,
+    created_at timestamptz not null default now()
+);
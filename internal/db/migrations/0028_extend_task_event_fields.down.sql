@@ -0,0 +1,4 @@
+DELETE FROM task_events WHERE field NOT IN ('name', 'description');
+
+ALTER TABLE task_events DROP CONSTRAINT IF EXISTS task_events_field_check;
+ALTER TABLE task_events ADD CONSTRAINT task_events_field_check CHECK (field IN ('name', 'description'));
@@ -0,0 +1,5 @@
+-- History keeps every field written by task updates and transitions
+ALTER TABLE task_events DROP CONSTRAINT IF EXISTS task_events_field_check;
+ALTER TABLE task_events ADD CONSTRAINT task_events_field_check
+    CHECK (field IN ('name', 'description', 'priority', 'start_at', 'due_at', 'parent_id', 'project_id',
+        'estimate_unit', 'original_estimate', 'remaining_estimate', 'assignee', 'reporter', 'status'));
@@ -0,0 +1 @@
+-- Personal organizations are kept, they hold data of their users
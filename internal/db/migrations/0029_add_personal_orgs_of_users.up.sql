@@ -0,0 +1,8 @@
+-- Personal organizations are created together with users instead of on the first request
+INSERT INTO organizations (name, personal_login)
+SELECT login, login FROM users
+ON CONFLICT (personal_login) DO NOTHING;
+
+INSERT INTO memberships (org_id, login, role)
+SELECT o.id, o.personal_login, 'owner' FROM organizations o JOIN users u ON u.login = o.personal_login
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+-- Only the latest running timer of the user is kept running
+UPDATE time_entries SET stopped_at = GREATEST(now(), started_at)
+WHERE stopped_at IS NULL AND id NOT IN (SELECT max(id) FROM time_entries WHERE stopped_at IS NULL GROUP BY login);
+
+DROP INDEX IF EXISTS time_entries_running_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_idx ON time_entries (login) WHERE stopped_at IS NULL;
+
+ALTER TABLE time_entries DROP COLUMN IF EXISTS org_id;
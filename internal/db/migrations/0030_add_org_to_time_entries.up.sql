@@ -0,0 +1,7 @@
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS org_id bigint references organizations(id) on delete cascade;
+UPDATE time_entries e SET org_id = t.org_id FROM tasks t WHERE t.id = e.task_id;
+ALTER TABLE time_entries ALTER COLUMN org_id SET NOT NULL;
+
+-- At most one running timer per user in every organization, a timer of another organization is never in the way
+DROP INDEX IF EXISTS time_entries_running_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_idx ON time_entries (login, org_id) WHERE stopped_at IS NULL;
@@ -0,0 +1,19 @@
+DROP POLICY IF EXISTS board_columns_tenant ON board_columns;
+DROP POLICY IF EXISTS task_labels_tenant ON task_labels;
+DROP POLICY IF EXISTS task_dependencies_tenant ON task_dependencies;
+DROP POLICY IF EXISTS task_shares_tenant ON task_shares;
+DROP POLICY IF EXISTS task_events_tenant ON task_events;
+DROP POLICY IF EXISTS time_entries_tenant ON time_entries;
+DROP POLICY IF EXISTS checklist_items_tenant ON checklist_items;
+DROP POLICY IF EXISTS comments_tenant ON comments;
+
+ALTER TABLE board_columns DISABLE ROW LEVEL SECURITY;
+ALTER TABLE task_labels DISABLE ROW LEVEL SECURITY;
+ALTER TABLE task_dependencies DISABLE ROW LEVEL SECURITY;
+ALTER TABLE task_shares DISABLE ROW LEVEL SECURITY;
+ALTER TABLE task_events DISABLE ROW LEVEL SECURITY;
+ALTER TABLE time_entries DISABLE ROW LEVEL SECURITY;
+ALTER TABLE checklist_items DISABLE ROW LEVEL SECURITY;
+ALTER TABLE comments DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS task_visible(bigint);
@@ -0,0 +1,33 @@
+-- Rows of child tables are visible along with their tenant visible parent, a query missing the join
+-- to the parent can not read or write rows of another organization
+CREATE OR REPLACE FUNCTION task_visible(task bigint) RETURNS boolean
+    LANGUAGE sql STABLE
+AS $$
+    SELECT EXISTS (SELECT 1 FROM tasks t WHERE t.id = task AND tenant_visible(t.org_id))
+$$;
+
+ALTER TABLE comments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE checklist_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE time_entries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_shares ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_dependencies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_labels ENABLE ROW LEVEL SECURITY;
+ALTER TABLE board_columns ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY comments_tenant ON comments USING (task_visible(task_id));
+CREATE POLICY checklist_items_tenant ON checklist_items USING (task_visible(task_id));
+CREATE POLICY time_entries_tenant ON time_entries USING (tenant_visible(org_id) AND task_visible(task_id));
+CREATE POLICY task_events_tenant ON task_events USING (task_visible(task_id));
+CREATE POLICY task_shares_tenant ON task_shares USING (
+    task_visible(task_id)
+    OR EXISTS (SELECT 1 FROM projects p WHERE p.id = task_shares.project_id AND tenant_visible(p.org_id))
+);
+CREATE POLICY task_dependencies_tenant ON task_dependencies USING (task_visible(task_id) AND task_visible(blocker_id));
+CREATE POLICY task_labels_tenant ON task_labels USING (
+    task_visible(task_id)
+    AND EXISTS (SELECT 1 FROM labels l WHERE l.id = task_labels.label_id AND tenant_visible(l.org_id))
+);
+CREATE POLICY board_columns_tenant ON board_columns USING (
+    EXISTS (SELECT 1 FROM boards b WHERE b.id = board_columns.board_id AND tenant_visible(b.org_id))
+);
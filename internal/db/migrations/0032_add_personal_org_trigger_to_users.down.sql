@@ -0,0 +1,3 @@
+-- Personal organizations are kept, they hold data of their users
+DROP TRIGGER IF EXISTS users_personal_org ON users;
+DROP FUNCTION IF EXISTS personal_org_add();
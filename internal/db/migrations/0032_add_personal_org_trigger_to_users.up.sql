@@ -0,0 +1,19 @@
+-- Every user gets the personal organization in the transaction adding the user, whichever code or
+-- script adds the row. Requests without an organization work in it.
+CREATE OR REPLACE FUNCTION personal_org_add() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+DECLARE
+    org bigint;
+BEGIN
+    INSERT INTO organizations (name, personal_login) VALUES (NEW.login, NEW.login)
+    ON CONFLICT (personal_login) DO UPDATE SET personal_login = EXCLUDED.personal_login
+    RETURNING id INTO org;
+
+    INSERT INTO memberships (org_id, login, role) VALUES (org, NEW.login, 'owner') ON CONFLICT DO NOTHING;
+
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER users_personal_org AFTER INSERT ON users FOR EACH ROW EXECUTE FUNCTION personal_org_add();
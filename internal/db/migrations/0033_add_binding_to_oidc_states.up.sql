@@ -0,0 +1,4 @@
+-- Pending login is bound to the browser which started it by a cookie, only the hash of its value is kept.
+-- Logins pending during the upgrade have no binding, they are dropped and have to be started again.
+DELETE FROM oidc_states;
+ALTER TABLE oidc_states ADD COLUMN IF NOT EXISTS binding_hash varchar(64) not null;
@@ -1,12 +1,537 @@
 package entities
 
+import (
+	"slices"
+	"time"
+)
+
+const (
+	UserLoginKey  = "user"
+	OrgIDKey      = "org"
+	UserScopesKey = "scopes"
+)
+
+type TaskStatus string
+
+const (
+	StatusTodo       TaskStatus = "todo"
+	StatusInProgress TaskStatus = "in_progress"
+	StatusBlocked    TaskStatus = "blocked"
+	StatusDone       TaskStatus = "done"
+	StatusCancelled  TaskStatus = "cancelled"
+)
+
+var TaskStatuses = []TaskStatus{StatusTodo, StatusInProgress, StatusBlocked, StatusDone, StatusCancelled}
+
+func (s TaskStatus) Valid() bool {
+	for _, status := range TaskStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+type TaskPriority string
+
+const (
+	PriorityP0 TaskPriority = "P0"
+	PriorityP1 TaskPriority = "P1"
+	PriorityP2 TaskPriority = "P2"
+	PriorityP3 TaskPriority = "P3"
+
+	DefaultPriority = PriorityP2
+)
+
+// TaskPriorities is ordered from the most to the least urgent priority.
+var TaskPriorities = []TaskPriority{PriorityP0, PriorityP1, PriorityP2, PriorityP3}
+
+func (p TaskPriority) Valid() bool {
+	return p.Level() >= 0
+}
+
+// Level returns numeric representation of priority, 0 is the most urgent.
+// Returns -1 for unknown priority.
+func (p TaskPriority) Level() int {
+	for i, priority := range TaskPriorities {
+		if p == priority {
+			return i
+		}
+	}
+	return -1
+}
+
+func PriorityFromLevel(level int) TaskPriority {
+	if level < 0 || level >= len(TaskPriorities) {
+		return ""
+	}
+	return TaskPriorities[level]
+}
+
+// EstimateUnit is measure of task estimates, only estimates in minutes are comparable with logged time.
+type EstimateUnit string
+
+const (
+	EstimateMinutes EstimateUnit = "minutes"
+	EstimatePoints  EstimateUnit = "points"
+)
+
+func (u EstimateUnit) Valid() bool {
+	return u == EstimateMinutes || u == EstimatePoints
+}
+
+// Access is the user role on a task, shares give other users viewer or editor access.
+type Access string
+
 const (
-	UserLoginKey = "user"
+	AccessViewer Access = "viewer"
+	AccessEditor Access = "editor"
+	AccessOwner  Access = "owner"
 )
 
+// accessLevels is ordered from the least to the most privileged access.
+var accessLevels = []Access{AccessViewer, AccessEditor, AccessOwner}
+
+// Valid reports whether the access can be granted by a share.
+func (a Access) Valid() bool {
+	return a == AccessViewer || a == AccessEditor
+}
+
+// Includes reports whether the access allows everything the other one does.
+func (a Access) Includes(other Access) bool {
+	level, otherLevel := -1, -1
+	for i, access := range accessLevels {
+		if a == access {
+			level = i
+		}
+		if other == access {
+			otherLevel = i
+		}
+	}
+	return otherLevel >= 0 && level >= otherLevel
+}
+
 type Task struct {
 	ID          uint64
 	Name        string
 	Description string
 	Owner       string
+	Access      Access
+	Status      TaskStatus
+	Priority    TaskPriority
+	StartAt     *time.Time
+	DueAt       *time.Time
+	ParentID    *uint64
+	SeriesID    *uint64
+	Recurrence  string
+	ProjectID   *uint64
+	// Key is the task number within its project like "WEB-42"
+	Key string
+	// Rank orders the task inside board columns, empty until the task is moved on a board
+	Rank       string
+	Labels     []Label
+	Blockers   []uint64
+	Dependents []uint64
+	Checklist  []ChecklistItem
+	// Completion is the percentage of done checklist items
+	Completion        int
+	EstimateUnit      EstimateUnit
+	OriginalEstimate  *int
+	RemainingEstimate *int
+	// Assignee is the user doing the task, Reporter is the one who asked for it
+	Assignee string
+	Reporter string
+	// DeletedAt is set while the task is in trash
+	DeletedAt *time.Time
+}
+
+// TaskEffort compares task estimates with time logged on it. Variance and accuracy
+// are known only for tasks estimated in minutes.
+type TaskEffort struct {
+	TaskID            uint64
+	EstimateUnit      EstimateUnit
+	OriginalEstimate  *int
+	RemainingEstimate *int
+	LoggedMinutes     int
+	// Variance is logged minutes above the original estimate, negative when the task took less
+	Variance *int
+	// Accuracy is the original estimate divided by logged time, 1 is a perfect estimate
+	Accuracy *float64
+}
+
+// EstimateAccuracy aggregates efforts of tasks estimated in minutes and completed within a period.
+type EstimateAccuracy struct {
+	Login            string
+	From             time.Time
+	To               time.Time
+	Tasks            int
+	EstimatedMinutes int
+	LoggedMinutes    int
+	Accuracy         *float64
+}
+
+// ChecklistItem is a step of the task, items are ordered by position.
+type ChecklistItem struct {
+	ID       uint64
+	TaskID   uint64
+	Title    string
+	Done     bool
+	Position int
+}
+
+// Series groups occurrences of a recurring task, rule is in RFC 5545 RRULE format.
+type Series struct {
+	ID    uint64
+	Owner string
+	Rule  string
+	Start time.Time
+}
+
+// SeriesUpdate changes an occurrence with all the following ones, empty fields are left as is.
+type SeriesUpdate struct {
+	Rule        string
+	Name        string
+	Description string
+	Priority    TaskPriority
+}
+
+// Comment is a message in the task discussion thread.
+type Comment struct {
+	ID        uint64
+	TaskID    uint64
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+// CommentPage is a page of comments in chronological order.
+type CommentPage struct {
+	Comments   []Comment
+	NextCursor string
+}
+
+// MaxCommentLength limits comment body size in characters.
+const MaxCommentLength = 10000
+
+// TaskField is a task field named as in the API, it is used by updates and kept in the task history.
+type TaskField string
+
+const (
+	TaskFieldName              TaskField = "name"
+	TaskFieldDescription       TaskField = "description"
+	TaskFieldPriority          TaskField = "priority"
+	TaskFieldStartAt           TaskField = "start_at"
+	TaskFieldDueAt             TaskField = "due_at"
+	TaskFieldParent            TaskField = "parent_id"
+	TaskFieldProject           TaskField = "project_id"
+	TaskFieldEstimateUnit      TaskField = "estimate_unit"
+	TaskFieldOriginalEstimate  TaskField = "original_estimate"
+	TaskFieldRemainingEstimate TaskField = "remaining_estimate"
+	TaskFieldAssignee          TaskField = "assignee"
+	TaskFieldReporter          TaskField = "reporter"
+	TaskFieldStatus            TaskField = "status"
+)
+
+// TaskUpdateFields are the fields changed by a task update, omitted fields keep their values.
+var TaskUpdateFields = []TaskField{
+	TaskFieldName, TaskFieldDescription, TaskFieldPriority, TaskFieldStartAt, TaskFieldDueAt, TaskFieldParent, TaskFieldProject,
+	TaskFieldEstimateUnit, TaskFieldOriginalEstimate, TaskFieldRemainingEstimate, TaskFieldAssignee, TaskFieldReporter,
+}
+
+// TaskFields are the fields kept in the task history, every field written by updates and transitions.
+var TaskFields = append(slices.Clone(TaskUpdateFields), TaskFieldStatus)
+
+// TaskEvent is a change of a task field, events of a single update share the creation time.
+type TaskEvent struct {
+	ID        uint64
+	TaskID    uint64
+	Author    string
+	Field     TaskField
+	OldValue  string
+	NewValue  string
+	CreatedAt time.Time
+}
+
+// TimeEntry is work logged by the user on the task, running timer has no stop time.
+type TimeEntry struct {
+	ID        uint64
+	TaskID    uint64
+	Login     string
+	StartedAt time.Time
+	StoppedAt *time.Time
+	Note      string
+}
+
+// TimeReportRow is time logged on a task or a label.
+type TimeReportRow struct {
+	ID    uint64
+	Name  string
+	Hours float64
+}
+
+// TimeReport aggregates logged time within a period, a task with several labels counts for each of them.
+type TimeReport struct {
+	From   time.Time
+	To     time.Time
+	Total  float64
+	Tasks  []TimeReportRow
+	Labels []TimeReportRow
+}
+
+// Project groups tasks, each task of the project is numbered after the project key.
+type Project struct {
+	ID          uint64
+	Owner       string
+	Key         string
+	Name        string
+	Description string
+	Archived    bool
+}
+
+// Share grants the login access to a single task or to all tasks of a project.
+type Share struct {
+	TaskID    *uint64
+	ProjectID *uint64
+	Login     string
+	Role      Access
+}
+
+// OrgRole is the role of a member in an organization.
+type OrgRole string
+
+const (
+	OrgMember OrgRole = "member"
+	OrgAdmin  OrgRole = "admin"
+	OrgOwner  OrgRole = "owner"
+)
+
+// orgRoles is ordered from the least to the most privileged role.
+var orgRoles = []OrgRole{OrgMember, OrgAdmin, OrgOwner}
+
+func (r OrgRole) Valid() bool {
+	return r == OrgMember || r == OrgAdmin || r == OrgOwner
+}
+
+// Includes reports whether the role allows everything the other one does.
+func (r OrgRole) Includes(other OrgRole) bool {
+	level, otherLevel := -1, -1
+	for i, role := range orgRoles {
+		if r == role {
+			level = i
+		}
+		if other == role {
+			otherLevel = i
+		}
+	}
+	return otherLevel >= 0 && level >= otherLevel
+}
+
+// Organization is a tenant, its tasks, projects, labels and boards are never visible in other organizations.
+// Role is the role of the current user in it.
+type Organization struct {
+	ID   uint64
+	Name string
+	Role OrgRole
+}
+
+type Membership struct {
+	OrgID uint64
+	Login string
+	Role  OrgRole
+}
+
+// Board shows tasks in ordered columns, each column holds tasks in one status.
+// Board of a project shows only the project tasks.
+type Board struct {
+	ID        uint64
+	Owner     string
+	Name      string
+	ProjectID *uint64
+	Columns   []BoardColumn
+}
+
+// BoardColumn limits number of its tasks when WIPLimit is set.
+type BoardColumn struct {
+	ID       uint64
+	BoardID  uint64
+	Name     string
+	Status   TaskStatus
+	Position int
+	WIPLimit *int
+	Tasks    []Task
+}
+
+// TaskMove places the task into the column between the given neighbours,
+// the task goes to the end of the column without them.
+type TaskMove struct {
+	ColumnID uint64
+	After    *uint64
+	Before   *uint64
+}
+
+// StatusChange moves the task from one status to another. Ranks of tasks in the board column
+// the task is moved to are set along with the status, completed occurrence of a recurring task
+// is followed by the next one unless it already exists.
+type StatusChange struct {
+	ID    uint64
+	From  TaskStatus
+	To    TaskStatus
+	Ranks map[uint64]string
+	Next  *Task
+}
+
+// Dependency means that the task can not be completed until the blocker is.
+type Dependency struct {
+	TaskID    uint64
+	BlockerID uint64
+}
+
+// MaxTaskDepth limits the number of levels in a task hierarchy including the root task.
+const MaxTaskDepth = 5
+
+// TaskNode is a task with its nested subtasks.
+type TaskNode struct {
+	Task
+	Children []TaskNode
+}
+
+// RemoveMode defines what happens with subtasks when their parent is removed.
+type RemoveMode string
+
+const (
+	// RemoveReject refuses to remove a task which has subtasks.
+	RemoveReject RemoveMode = "reject"
+	// RemoveCascade removes the task with all its descendants.
+	RemoveCascade RemoveMode = "cascade"
+	// RemoveOrphan turns subtasks into top level tasks.
+	RemoveOrphan RemoveMode = "orphan"
+)
+
+func (m RemoveMode) Valid() bool {
+	return m == RemoveReject || m == RemoveCascade || m == RemoveOrphan
+}
+
+type Label struct {
+	ID    uint64
+	Name  string
+	Color string
+	Owner string
+}
+
+// TaskFilter narrows down the list of tasks returned by storage.
+// Zero value means no filtering.
+type TaskFilter struct {
+	Statuses []TaskStatus
+	Sort     []SortKey
+	// Labels keeps label names, tasks must have all of them
+	// or at least one of them when LabelsAny is set.
+	Labels    []string
+	LabelsAny bool
+	ProjectID *uint64
+	Assignee  string
+}
+
+// TaskSortFields is the whitelist of fields tasks can be sorted by.
+var TaskSortFields = []string{"id", "name", "status", "priority", "start_at", "due_at"}
+
+// SortKey is a single sorting field. Priority is sorted by urgency,
+// so descending order returns the most urgent tasks first.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// PageRequest asks for at most Limit items placed after the opaque Cursor.
+// Empty cursor means the first page.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// TaskPage is a single page of tasks, NextCursor is empty for the last page.
+type TaskPage struct {
+	Tasks      []Task
+	NextCursor string
+}
+
+// AccessToken is an API token of the user, the token itself is shown only once when it is issued.
+// Token without expiry time never expires, such tokens were created before expiry was introduced.
+// Prefix is the public start of the token to tell tokens apart, it is empty for tokens created by hand.
+type AccessToken struct {
+	ID         uint64
+	Name       string
+	Prefix     string
+	Scopes     []Scope
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+const (
+	// DefaultTokenLifetime is used when expiry time of a new token is not given
+	DefaultTokenLifetime = 90 * 24 * time.Hour
+	MaxTokenLifetime     = 365 * 24 * time.Hour
+)
+
+// Scope limits what an access token may do, each route requires one.
+type Scope string
+
+const (
+	ScopeRead       Scope = "read"
+	ScopeTasksWrite Scope = "tasks:write"
+	ScopeAdmin      Scope = "admin"
+)
+
+// scopeLevels is ordered from the least to the most privileged scope.
+var scopeLevels = []Scope{ScopeRead, ScopeTasksWrite, ScopeAdmin}
+
+func (s Scope) Valid() bool {
+	return s == ScopeRead || s == ScopeTasksWrite || s == ScopeAdmin
+}
+
+// Includes reports whether the scope allows everything the other one does.
+func (s Scope) Includes(other Scope) bool {
+	level, otherLevel := -1, -1
+	for i, scope := range scopeLevels {
+		if s == scope {
+			level = i
+		}
+		if other == scope {
+			otherLevel = i
+		}
+	}
+	return otherLevel >= 0 && level >= otherLevel
+}
+
+// ScopesAllow reports whether any of the scopes allows what the required one does.
+func ScopesAllow(scopes []Scope, required Scope) bool {
+	for _, scope := range scopes {
+		if scope.Includes(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// OIDCState is a pending identity provider login: the PKCE code verifier and the nonce expected in the ID token.
+// BindingHash is the hash of the cookie value set in the browser which started the login.
+type OIDCState struct {
+	State       string
+	Verifier    string
+	Nonce       string
+	BindingHash string
+	CreatedAt   time.Time
+}
+
+// Session is the result of the identity provider login, the token is accepted like any access token.
+type Session struct {
+	Login     string
+	Token     string
+	ExpiresAt time.Time
 }
@@ -3,3 +3,56 @@ package entities
 import "errors"
 
 var ErrNoTask = errors.New("task not found")
+var ErrUnknownStatus = errors.New("unknown task status")
+var ErrInvalidTransition = errors.New("invalid task status transition")
+var ErrInvalidDates = errors.New("task due date is before start date")
+var ErrUnknownPriority = errors.New("unknown task priority")
+var ErrInvalidSort = errors.New("invalid sort field")
+var ErrInvalidCursor = errors.New("invalid page cursor")
+var ErrNoLabel = errors.New("label not found")
+var ErrLabelExists = errors.New("label already exists")
+var ErrInvalidLabel = errors.New("invalid label name")
+var ErrInvalidParent = errors.New("parent task not found")
+var ErrTaskCycle = errors.New("task can not be a descendant of itself")
+var ErrTaskTooDeep = errors.New("task hierarchy is too deep")
+var ErrTaskHasChildren = errors.New("task has subtasks")
+var ErrUnknownRemoveMode = errors.New("unknown remove mode")
+var ErrNoDependency = errors.New("task dependency not found")
+var ErrDependencyCycle = errors.New("task dependency creates a cycle")
+var ErrOpenBlockers = errors.New("task has open blockers")
+var ErrInvalidRecurrence = errors.New("invalid task recurrence")
+var ErrNoSeries = errors.New("task series not found")
+var ErrOccurrenceExists = errors.New("task occurrence already exists")
+var ErrNoComment = errors.New("comment not found")
+var ErrInvalidComment = errors.New("invalid comment body")
+var ErrNoTaskEvent = errors.New("task event not found")
+var ErrNoChecklistItem = errors.New("checklist item not found")
+var ErrInvalidChecklistItem = errors.New("invalid checklist item title")
+var ErrInvalidChecklistOrder = errors.New("checklist order does not match items")
+var ErrTimerRunning = errors.New("timer is already running")
+var ErrNoTimer = errors.New("no running timer")
+var ErrNoTimeEntry = errors.New("time entry not found")
+var ErrInvalidTimeEntry = errors.New("invalid time entry")
+var ErrInvalidPeriod = errors.New("invalid report period")
+var ErrInvalidEstimate = errors.New("invalid task estimate")
+var ErrNoProject = errors.New("project not found")
+var ErrInvalidProject = errors.New("invalid project")
+var ErrProjectExists = errors.New("project key already exists")
+var ErrProjectArchived = errors.New("project is archived")
+var ErrNoBoard = errors.New("board not found")
+var ErrInvalidBoard = errors.New("invalid board")
+var ErrNoColumn = errors.New("board column not found")
+var ErrWIPLimit = errors.New("column work in progress limit is reached")
+var ErrInvalidMove = errors.New("invalid task move")
+var ErrForbidden = errors.New("access denied")
+var ErrNoShare = errors.New("share not found")
+var ErrInvalidShare = errors.New("invalid share")
+var ErrNoUser = errors.New("user not found")
+var ErrNoOrg = errors.New("organization not found")
+var ErrInvalidOrg = errors.New("invalid organization")
+var ErrNoMember = errors.New("organization member not found")
+var ErrLastOwner = errors.New("organization must keep an owner")
+var ErrNoToken = errors.New("access token not found")
+var ErrInvalidToken = errors.New("invalid access token")
+var ErrNoOIDCState = errors.New("login state not found or expired")
+var ErrOIDCLogin = errors.New("identity provider login failed")
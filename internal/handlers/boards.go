@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type BoardService interface {
+	Boards(ctx context.Context, login string) ([]entities.Board, error)
+	Board(ctx context.Context, id uint64, login string) (entities.Board, error)
+	BoardAdd(ctx context.Context, board entities.Board, login string) (uint64, error)
+	BoardRemove(ctx context.Context, id uint64, login string) error
+	BoardColumnUpdate(ctx context.Context, column entities.BoardColumn, login string) error
+	TaskMove(ctx context.Context, id uint64, move entities.TaskMove, login string) (entities.Task, error)
+}
+
+type BoardsHandler struct {
+	Service BoardService
+}
+
+type BoardJSON struct {
+	Name      string            `json:"name"`
+	ProjectID *uint64           `json:"project_id,omitempty"`
+	Columns   []BoardColumnJSON `json:"columns"`
+}
+
+type BoardColumnJSON struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	WIPLimit *int   `json:"wip_limit,omitempty"`
+}
+
+// TaskMoveJSON places the task after one neighbour and before another, both are optional.
+type TaskMoveJSON struct {
+	ColumnID uint64  `json:"column_id"`
+	AfterID  *uint64 `json:"after_id,omitempty"`
+	BeforeID *uint64 `json:"before_id,omitempty"`
+}
+
+func (h *BoardsHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	boards, err := h.Service.Boards(c.Context(), login)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if boards == nil {
+		boards = []entities.Board{}
+	}
+
+	return c.JSON(boards)
+}
+
+// ItemHandler returns the board columns with their tasks.
+func (h *BoardsHandler) ItemHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	boardId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	board, err := h.Service.Board(c.Context(), boardId, login)
+	if errors.Is(err, entities.ErrNoBoard) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	for i := range board.Columns {
+		if board.Columns[i].Tasks == nil {
+			board.Columns[i].Tasks = []entities.Task{}
+		}
+	}
+
+	return c.JSON(board)
+}
+
+func (h *BoardsHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	//Read body and parse JSON to DTO
+	var boardDTO BoardJSON
+	err := json.Unmarshal(c.Body(), &boardDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	board := entities.Board{Name: boardDTO.Name, ProjectID: boardDTO.ProjectID}
+	for _, column := range boardDTO.Columns {
+		board.Columns = append(board.Columns, entities.BoardColumn{
+			Name:     column.Name,
+			Status:   entities.TaskStatus(column.Status),
+			WIPLimit: column.WIPLimit,
+		})
+	}
+
+	id, err := h.Service.BoardAdd(c.Context(), board, login)
+	if errors.Is(err, entities.ErrInvalidBoard) || errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrBadRequest
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(id)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *BoardsHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	boardId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.BoardRemove(c.Context(), boardId, login)
+	if errors.Is(err, entities.ErrNoBoard) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ColumnUpdateHandler changes column name and WIP limit, the column status can not be changed.
+func (h *BoardsHandler) ColumnUpdateHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	boardId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	columnId, err := strconv.ParseUint(c.Params("columnId"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var columnDTO BoardColumnJSON
+	err = json.Unmarshal(c.Body(), &columnDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	column := entities.BoardColumn{ID: columnId, BoardID: boardId, Name: columnDTO.Name, WIPLimit: columnDTO.WIPLimit}
+	err = h.Service.BoardColumnUpdate(c.Context(), column, login)
+	if errors.Is(err, entities.ErrInvalidBoard) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoColumn) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *BoardsHandler) MoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var moveDTO TaskMoveJSON
+	err = json.Unmarshal(c.Body(), &moveDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	move := entities.TaskMove{ColumnID: moveDTO.ColumnID, After: moveDTO.AfterID, Before: moveDTO.BeforeID}
+	task, err := h.Service.TaskMove(c.Context(), taskId, move, login)
+	if errors.Is(err, entities.ErrInvalidMove) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoColumn) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if errors.Is(err, entities.ErrWIPLimit) || errors.Is(err, entities.ErrInvalidTransition) || errors.Is(err, entities.ErrOpenBlockers) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(task)
+}
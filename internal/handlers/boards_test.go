@@ -0,0 +1,218 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedBoardServices struct {
+	mock.Mock
+}
+
+func (m *MockedBoardServices) Boards(ctx context.Context, login string) ([]entities.Board, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Board), args.Error(1)
+}
+
+func (m *MockedBoardServices) Board(ctx context.Context, id uint64, login string) (entities.Board, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.Board), args.Error(1)
+}
+
+func (m *MockedBoardServices) BoardAdd(ctx context.Context, board entities.Board, login string) (uint64, error) {
+	args := m.Called(ctx, board, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedBoardServices) BoardRemove(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedBoardServices) BoardColumnUpdate(ctx context.Context, column entities.BoardColumn, login string) error {
+	args := m.Called(ctx, column, login)
+	return args.Error(0)
+}
+
+func (m *MockedBoardServices) TaskMove(ctx context.Context, id uint64, move entities.TaskMove, login string) (entities.Task, error) {
+	args := m.Called(ctx, id, move, login)
+	return args.Get(0).(entities.Task), args.Error(1)
+}
+
+func newBoardsApp(s *MockedBoardServices, login string) *fiber.App {
+	h := &handlers.BoardsHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/boards", h.ListHandler)
+	app.Get("/boards/:id", h.ItemHandler)
+	app.Post("/boards", h.AddHandler)
+	app.Delete("/boards/:id", h.RemoveHandler)
+	app.Put("/boards/:id/columns/:columnId", h.ColumnUpdateHandler)
+	app.Post("/tasks/:id/move", h.MoveHandler)
+
+	return app
+}
+
+func TestBoardItemHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		board := entities.Board{ID: 1, Name: "Sprint", Owner: "user", Columns: []entities.BoardColumn{
+			{ID: 1, BoardID: 1, Name: "To do", Status: entities.StatusTodo, Tasks: []entities.Task{{ID: 2, Status: entities.StatusTodo, Rank: "i"}}},
+			{ID: 2, BoardID: 1, Name: "Done", Status: entities.StatusDone, Position: 1, Tasks: []entities.Task{}},
+		}}
+
+		s := new(MockedBoardServices)
+		s.On("Board", mock.Anything, uint64(1), "user").Return(board, nil)
+		app := newBoardsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/boards/1", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded entities.Board
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, board, encoded)
+	})
+
+	t.Run("board not found", func(t *testing.T) {
+		s := new(MockedBoardServices)
+		s.On("Board", mock.Anything, uint64(1), "user").Return(entities.Board{}, entities.ErrNoBoard)
+		app := newBoardsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/boards/1", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestBoardAddHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		board := entities.Board{Name: "Sprint", Columns: []entities.BoardColumn{
+			{Name: "To do", Status: entities.StatusTodo},
+			{Name: "Doing", Status: entities.StatusInProgress, WIPLimit: intPtr(2)},
+		}}
+
+		s := new(MockedBoardServices)
+		s.On("BoardAdd", mock.Anything, board, "user").Return(uint64(1), nil)
+		app := newBoardsApp(s, "user")
+
+		body := `{"name":"Sprint","columns":[{"name":"To do","status":"todo"},{"name":"Doing","status":"in_progress","wip_limit":2}]}`
+		req := httptest.NewRequest(http.MethodPost, "/boards", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("invalid board", func(t *testing.T) {
+		s := new(MockedBoardServices)
+		s.On("BoardAdd", mock.Anything, entities.Board{Name: "Sprint"}, "user").Return(uint64(0), entities.ErrInvalidBoard)
+		app := newBoardsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/boards", bytes.NewReader([]byte(`{"name":"Sprint"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestBoardColumnUpdateHandler(t *testing.T) {
+	s := new(MockedBoardServices)
+	s.On("BoardColumnUpdate", mock.Anything, entities.BoardColumn{ID: 2, BoardID: 1, Name: "Doing", WIPLimit: intPtr(3)}, "user").Return(nil)
+	app := newBoardsApp(s, "user")
+
+	req := httptest.NewRequest(http.MethodPut, "/boards/1/columns/2", bytes.NewReader([]byte(`{"name":"Doing","wip_limit":3}`)))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestTaskMoveHandler(t *testing.T) {
+	after := uint64(3)
+	move := entities.TaskMove{ColumnID: 2, After: &after}
+
+	t.Run("success request", func(t *testing.T) {
+		task := entities.Task{ID: 1, Owner: "user", Status: entities.StatusInProgress, Rank: "m"}
+
+		s := new(MockedBoardServices)
+		s.On("TaskMove", mock.Anything, uint64(1), move, "user").Return(task, nil)
+		app := newBoardsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/move", bytes.NewReader([]byte(`{"column_id":2,"after_id":3}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded entities.Task
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, task, encoded)
+	})
+
+	for _, tc := range []struct {
+		err    error
+		status int
+	}{
+		{entities.ErrInvalidMove, http.StatusBadRequest},
+		{entities.ErrNoColumn, http.StatusNotFound},
+		{entities.ErrNoTask, http.StatusNotFound},
+		{entities.ErrWIPLimit, http.StatusConflict},
+		{entities.ErrInvalidTransition, http.StatusConflict},
+		{fmt.Errorf("error"), http.StatusInternalServerError},
+	} {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			s := new(MockedBoardServices)
+			s.On("TaskMove", mock.Anything, uint64(1), move, "user").Return(entities.Task{}, tc.err)
+			app := newBoardsApp(s, "user")
+
+			req := httptest.NewRequest(http.MethodPost, "/tasks/1/move", bytes.NewReader([]byte(`{"column_id":2,"after_id":3}`)))
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedBoardServices)
+		app := newBoardsApp(s, "")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/move", bytes.NewReader([]byte(`{"column_id":2}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "TaskMove", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func intPtr(v int) *int {
+	return &v
+}
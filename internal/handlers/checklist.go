@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type ChecklistService interface {
+	Checklist(ctx context.Context, taskID uint64, login string) ([]entities.ChecklistItem, error)
+	ChecklistItemAdd(ctx context.Context, item entities.ChecklistItem, login string) (uint64, error)
+	ChecklistItemToggle(ctx context.Context, taskID uint64, id uint64, login string) (entities.ChecklistItem, error)
+	ChecklistReorder(ctx context.Context, taskID uint64, ids []uint64, login string) error
+	ChecklistItemRemove(ctx context.Context, taskID uint64, id uint64, login string) error
+}
+
+type ChecklistHandler struct {
+	Service ChecklistService
+}
+
+type ChecklistItemJSON struct {
+	Title string `json:"title"`
+}
+
+type ChecklistOrderJSON struct {
+	IDs []uint64 `json:"ids"`
+}
+
+func (h *ChecklistHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	items, err := h.Service.Checklist(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if items == nil {
+		items = []entities.ChecklistItem{}
+	}
+
+	return c.JSON(items)
+}
+
+func (h *ChecklistHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var itemDTO ChecklistItemJSON
+	err = json.Unmarshal(c.Body(), &itemDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	id, err := h.Service.ChecklistItemAdd(c.Context(), entities.ChecklistItem{TaskID: taskId, Title: itemDTO.Title}, login)
+	if errors.Is(err, entities.ErrInvalidChecklistItem) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(id)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *ChecklistHandler) ToggleHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, itemId, err := taskChecklistParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	item, err := h.Service.ChecklistItemToggle(c.Context(), taskId, itemId, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoChecklistItem) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(item)
+}
+
+func (h *ChecklistHandler) ReorderHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var orderDTO ChecklistOrderJSON
+	err = json.Unmarshal(c.Body(), &orderDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.ChecklistReorder(c.Context(), taskId, orderDTO.IDs, login)
+	if errors.Is(err, entities.ErrInvalidChecklistOrder) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ChecklistHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, itemId, err := taskChecklistParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.ChecklistItemRemove(c.Context(), taskId, itemId, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoChecklistItem) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func taskChecklistParams(c *fiber.Ctx) (uint64, uint64, error) {
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	itemId, err := strconv.ParseUint(c.Params("itemId"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return taskId, itemId, nil
+}
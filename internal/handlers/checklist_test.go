@@ -0,0 +1,196 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedChecklistServices struct {
+	mock.Mock
+}
+
+func (m *MockedChecklistServices) Checklist(ctx context.Context, taskID uint64, login string) ([]entities.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, login)
+	return args.Get(0).([]entities.ChecklistItem), args.Error(1)
+}
+
+func (m *MockedChecklistServices) ChecklistItemAdd(ctx context.Context, item entities.ChecklistItem, login string) (uint64, error) {
+	args := m.Called(ctx, item, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedChecklistServices) ChecklistItemToggle(ctx context.Context, taskID uint64, id uint64, login string) (entities.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Get(0).(entities.ChecklistItem), args.Error(1)
+}
+
+func (m *MockedChecklistServices) ChecklistReorder(ctx context.Context, taskID uint64, ids []uint64, login string) error {
+	args := m.Called(ctx, taskID, ids, login)
+	return args.Error(0)
+}
+
+func (m *MockedChecklistServices) ChecklistItemRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Error(0)
+}
+
+func newChecklistApp(s *MockedChecklistServices, login string) *fiber.App {
+	h := &handlers.ChecklistHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tasks/:id/checklist", h.ListHandler)
+	app.Post("/tasks/:id/checklist", h.AddHandler)
+	app.Put("/tasks/:id/checklist/order", h.ReorderHandler)
+	app.Post("/tasks/:id/checklist/:itemId/toggle", h.ToggleHandler)
+	app.Delete("/tasks/:id/checklist/:itemId", h.RemoveHandler)
+
+	return app
+}
+
+func TestChecklistListHandler(t *testing.T) {
+	t.Run("empty checklist", func(t *testing.T) {
+		s := new(MockedChecklistServices)
+		s.On("Checklist", mock.Anything, uint64(1), "user").Return([]entities.ChecklistItem(nil), nil)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/checklist", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var items []entities.ChecklistItem
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&items))
+		assert.NotNil(t, items)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		s := new(MockedChecklistServices)
+		s.On("Checklist", mock.Anything, uint64(1), "user").Return([]entities.ChecklistItem(nil), entities.ErrNoTask)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/checklist", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestChecklistAddHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.ChecklistItemJSON{Title: "write tests"})
+		s := new(MockedChecklistServices)
+		s.On("ChecklistItemAdd", mock.Anything, entities.ChecklistItem{TaskID: 1, Title: "write tests"}, "user").Return(uint64(3), nil)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/checklist", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("invalid title", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.ChecklistItemJSON{})
+		s := new(MockedChecklistServices)
+		s.On("ChecklistItemAdd", mock.Anything, entities.ChecklistItem{TaskID: 1}, "user").Return(uint64(0), entities.ErrInvalidChecklistItem)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/checklist", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestChecklistToggleHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		item := entities.ChecklistItem{ID: 3, TaskID: 1, Title: "write tests", Done: true, Position: 1}
+		s := new(MockedChecklistServices)
+		s.On("ChecklistItemToggle", mock.Anything, uint64(1), uint64(3), "user").Return(item, nil)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/checklist/3/toggle", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res entities.ChecklistItem
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		assert.Equal(t, item, res)
+	})
+
+	t.Run("item not found", func(t *testing.T) {
+		s := new(MockedChecklistServices)
+		s.On("ChecklistItemToggle", mock.Anything, uint64(1), uint64(3), "user").Return(entities.ChecklistItem{}, entities.ErrNoChecklistItem)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/checklist/3/toggle", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestChecklistReorderHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.ChecklistOrderJSON{IDs: []uint64{3, 1, 2}})
+		s := new(MockedChecklistServices)
+		s.On("ChecklistReorder", mock.Anything, uint64(1), []uint64{3, 1, 2}, "user").Return(nil)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/checklist/order", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("order does not match items", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.ChecklistOrderJSON{IDs: []uint64{3}})
+		s := new(MockedChecklistServices)
+		s.On("ChecklistReorder", mock.Anything, uint64(1), []uint64{3}, "user").Return(entities.ErrInvalidChecklistOrder)
+		app := newChecklistApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/checklist/order", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestChecklistRemoveHandler(t *testing.T) {
+	s := new(MockedChecklistServices)
+	s.On("ChecklistItemRemove", mock.Anything, uint64(1), uint64(3), "user").Return(nil)
+	app := newChecklistApp(s, "user")
+
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/1/checklist/3", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
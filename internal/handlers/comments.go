@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type CommentService interface {
+	Comments(ctx context.Context, taskID uint64, login string, page entities.PageRequest) (entities.CommentPage, error)
+	CommentAdd(ctx context.Context, comment entities.Comment, login string) (uint64, error)
+	CommentUpdate(ctx context.Context, comment entities.Comment, login string) error
+	CommentRemove(ctx context.Context, taskID uint64, id uint64, login string) error
+}
+
+type CommentsHandler struct {
+	Service CommentService
+}
+
+type CommentJSON struct {
+	Body string `json:"body"`
+}
+
+type CommentPageJSON struct {
+	Items      []entities.Comment `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+func (h *CommentsHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Parse page request from query string
+	page, err := parsePage(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	comments, err := h.Service.Comments(c.Context(), taskId, login, page)
+	if errors.Is(err, entities.ErrInvalidCursor) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err := setNextLink(c, page, comments.NextCursor); err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if comments.Comments == nil {
+		comments.Comments = []entities.Comment{}
+	}
+
+	return c.JSON(CommentPageJSON{
+		Items:      comments.Comments,
+		NextCursor: comments.NextCursor,
+	})
+}
+
+func (h *CommentsHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var commentDTO CommentJSON
+	err = json.Unmarshal(c.Body(), &commentDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	id, err := h.Service.CommentAdd(c.Context(), entities.Comment{TaskID: taskId, Body: commentDTO.Body}, login)
+	if errors.Is(err, entities.ErrInvalidComment) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(id)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *CommentsHandler) UpdateHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, commentId, err := taskCommentParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var commentDTO CommentJSON
+	err = json.Unmarshal(c.Body(), &commentDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.CommentUpdate(c.Context(), entities.Comment{ID: commentId, TaskID: taskId, Body: commentDTO.Body}, login)
+	if errors.Is(err, entities.ErrInvalidComment) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoComment) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *CommentsHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, commentId, err := taskCommentParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.CommentRemove(c.Context(), taskId, commentId, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoComment) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func taskCommentParams(c *fiber.Ctx) (uint64, uint64, error) {
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	commentId, err := strconv.ParseUint(c.Params("cid"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return taskId, commentId, nil
+}
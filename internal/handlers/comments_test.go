@@ -0,0 +1,210 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedCommentServices struct {
+	mock.Mock
+}
+
+func (m *MockedCommentServices) Comments(ctx context.Context, taskID uint64, login string, page entities.PageRequest) (entities.CommentPage, error) {
+	args := m.Called(ctx, taskID, login, page)
+	return args.Get(0).(entities.CommentPage), args.Error(1)
+}
+
+func (m *MockedCommentServices) CommentAdd(ctx context.Context, comment entities.Comment, login string) (uint64, error) {
+	args := m.Called(ctx, comment, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedCommentServices) CommentUpdate(ctx context.Context, comment entities.Comment, login string) error {
+	args := m.Called(ctx, comment, login)
+	return args.Error(0)
+}
+
+func (m *MockedCommentServices) CommentRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Error(0)
+}
+
+func newCommentsApp(s *MockedCommentServices, login string) *fiber.App {
+	h := &handlers.CommentsHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tasks/:id/comments", h.ListHandler)
+	app.Post("/tasks/:id/comments", h.AddHandler)
+	app.Put("/tasks/:id/comments/:cid", h.UpdateHandler)
+	app.Delete("/tasks/:id/comments/:cid", h.RemoveHandler)
+
+	return app
+}
+
+func TestCommentListHandler(t *testing.T) {
+	t.Run("success request with next page", func(t *testing.T) {
+		page := entities.PageRequest{Limit: 1}
+		comments := entities.CommentPage{Comments: []entities.Comment{{ID: 1, TaskID: 1, Author: "user", Body: "hello"}}, NextCursor: "abc"}
+
+		s := new(MockedCommentServices)
+		s.On("Comments", mock.Anything, uint64(1), "user", page).Return(comments, nil)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/comments?limit=1", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `</tasks/1/comments?cursor=abc&limit=1>; rel="next"`, resp.Header.Get(fiber.HeaderLink))
+
+		var res struct {
+			Items      []entities.Comment `json:"items"`
+			NextCursor string             `json:"next_cursor"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		assert.Equal(t, comments.Comments, res.Items)
+		assert.Equal(t, "abc", res.NextCursor)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		s := new(MockedCommentServices)
+		s.On("Comments", mock.Anything, uint64(1), "user", defaultPage).Return(entities.CommentPage{}, entities.ErrNoTask)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/comments", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		page := entities.PageRequest{Limit: entities.DefaultPageLimit, Cursor: "bad"}
+		s := new(MockedCommentServices)
+		s.On("Comments", mock.Anything, uint64(1), "user", page).Return(entities.CommentPage{}, entities.ErrInvalidCursor)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/comments?cursor=bad", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCommentAddHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.CommentJSON{Body: "hello"})
+		s := new(MockedCommentServices)
+		s.On("CommentAdd", mock.Anything, entities.Comment{TaskID: 1, Body: "hello"}, "user").Return(uint64(5), nil)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/comments", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var id uint64
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&id))
+		assert.Equal(t, uint64(5), id)
+	})
+
+	t.Run("invalid comment", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.CommentJSON{})
+		s := new(MockedCommentServices)
+		s.On("CommentAdd", mock.Anything, entities.Comment{TaskID: 1}, "user").Return(uint64(0), entities.ErrInvalidComment)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/comments", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedCommentServices)
+		app := newCommentsApp(s, "")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/comments", bytes.NewReader([]byte(`{"body":"hello"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "CommentAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCommentUpdateHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.CommentJSON{Body: "edited"})
+		s := new(MockedCommentServices)
+		s.On("CommentUpdate", mock.Anything, entities.Comment{ID: 5, TaskID: 1, Body: "edited"}, "user").Return(nil)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/comments/5", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("comment of another author", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.CommentJSON{Body: "edited"})
+		s := new(MockedCommentServices)
+		s.On("CommentUpdate", mock.Anything, entities.Comment{ID: 5, TaskID: 1, Body: "edited"}, "user").Return(entities.ErrNoComment)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/comments/5", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestCommentRemoveHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		s := new(MockedCommentServices)
+		s.On("CommentRemove", mock.Anything, uint64(1), uint64(5), "user").Return(nil)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodDelete, "/tasks/1/comments/5", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("invalid comment id", func(t *testing.T) {
+		s := new(MockedCommentServices)
+		app := newCommentsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodDelete, "/tasks/1/comments/abc", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "CommentRemove", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type DependencyService interface {
+	TaskDependencyAdd(ctx context.Context, taskID uint64, blockerID uint64, login string) error
+	TaskDependencyRemove(ctx context.Context, taskID uint64, blockerID uint64, login string) error
+	TasksOrder(ctx context.Context, login string) ([]uint64, error)
+}
+
+type DependenciesHandler struct {
+	Service DependencyService
+}
+
+func (h *DependenciesHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, blockerId, err := taskBlockerParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TaskDependencyAdd(c.Context(), taskId, blockerId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if errors.Is(err, entities.ErrDependencyCycle) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *DependenciesHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, blockerId, err := taskBlockerParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TaskDependencyRemove(c.Context(), taskId, blockerId, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoDependency) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// OrderHandler returns task ids in topological order, blockers go first.
+func (h *DependenciesHandler) OrderHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	ids, err := h.Service.TasksOrder(c.Context(), login)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if ids == nil {
+		ids = []uint64{}
+	}
+
+	return c.JSON(ids)
+}
+
+func taskBlockerParams(c *fiber.Ctx) (uint64, uint64, error) {
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	blockerId, err := strconv.ParseUint(c.Params("blockerId"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return taskId, blockerId, nil
+}
@@ -0,0 +1,154 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedDependencyServices struct {
+	mock.Mock
+}
+
+func (m *MockedDependencyServices) TaskDependencyAdd(ctx context.Context, taskID uint64, blockerID uint64, login string) error {
+	args := m.Called(ctx, taskID, blockerID, login)
+	return args.Error(0)
+}
+
+func (m *MockedDependencyServices) TaskDependencyRemove(ctx context.Context, taskID uint64, blockerID uint64, login string) error {
+	args := m.Called(ctx, taskID, blockerID, login)
+	return args.Error(0)
+}
+
+func (m *MockedDependencyServices) TasksOrder(ctx context.Context, login string) ([]uint64, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+func newDependenciesApp(s *MockedDependencyServices, login string) *fiber.App {
+	h := &handlers.DependenciesHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tasks/order", h.OrderHandler)
+	app.Put("/tasks/:id/blockers/:blockerId", h.AddHandler)
+	app.Delete("/tasks/:id/blockers/:blockerId", h.RemoveHandler)
+
+	return app
+}
+
+func TestDependencyAddHandler(t *testing.T) {
+	login := "user"
+
+	cases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"success request", nil, http.StatusNoContent},
+		{"task not found", entities.ErrNoTask, http.StatusNotFound},
+		{"not an editor", entities.ErrForbidden, http.StatusForbidden},
+		{"dependency creates a cycle", entities.ErrDependencyCycle, http.StatusConflict},
+		{"internal server error", fmt.Errorf("error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := new(MockedDependencyServices)
+			s.On("TaskDependencyAdd", mock.Anything, uint64(1), uint64(2), login).Return(tc.err)
+			app := newDependenciesApp(s, login)
+
+			req := httptest.NewRequest(http.MethodPut, "/tasks/1/blockers/2", nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+			s.AssertExpectations(t)
+		})
+	}
+
+	t.Run("invalid blocker id", func(t *testing.T) {
+		s := new(MockedDependencyServices)
+		app := newDependenciesApp(s, login)
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/blockers/abc", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "TaskDependencyAdd", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDependencyRemoveHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		s := new(MockedDependencyServices)
+		s.On("TaskDependencyRemove", mock.Anything, uint64(1), uint64(2), "user").Return(nil)
+		app := newDependenciesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodDelete, "/tasks/1/blockers/2", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("dependency not found", func(t *testing.T) {
+		s := new(MockedDependencyServices)
+		s.On("TaskDependencyRemove", mock.Anything, uint64(1), uint64(2), "user").Return(entities.ErrNoDependency)
+		app := newDependenciesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodDelete, "/tasks/1/blockers/2", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestTasksOrderHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		s := new(MockedDependencyServices)
+		s.On("TasksOrder", mock.Anything, "user").Return([]uint64{3, 1, 2}, nil)
+		app := newDependenciesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/order", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var ids []uint64
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&ids))
+		assert.Equal(t, []uint64{3, 1, 2}, ids)
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedDependencyServices)
+		app := newDependenciesApp(s, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/order", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "TasksOrder", mock.Anything, mock.Anything)
+	})
+}
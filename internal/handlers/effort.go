@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type EffortService interface {
+	TaskEffort(ctx context.Context, id uint64, login string) (entities.TaskEffort, error)
+	EstimateAccuracy(ctx context.Context, login string, from time.Time, to time.Time) ([]entities.EstimateAccuracy, error)
+}
+
+type EffortHandler struct {
+	Service EffortService
+}
+
+func (h *EffortHandler) TaskHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	effort, err := h.Service.TaskEffort(c.Context(), id, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(effort)
+}
+
+// AccuracyHandler compares estimates of organization tasks completed within [from, to) with logged time per user.
+func (h *EffortHandler) AccuracyHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	accuracy, err := h.Service.EstimateAccuracy(c.Context(), login, from, to)
+	if errors.Is(err, entities.ErrInvalidPeriod) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrForbidden) || errors.Is(err, entities.ErrNoOrg) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(accuracy)
+}
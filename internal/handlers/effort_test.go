@@ -0,0 +1,144 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedEffortServices struct {
+	mock.Mock
+}
+
+func (m *MockedEffortServices) TaskEffort(ctx context.Context, id uint64, login string) (entities.TaskEffort, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.TaskEffort), args.Error(1)
+}
+
+func (m *MockedEffortServices) EstimateAccuracy(ctx context.Context, login string, from time.Time, to time.Time) ([]entities.EstimateAccuracy, error) {
+	args := m.Called(ctx, login, from, to)
+	return args.Get(0).([]entities.EstimateAccuracy), args.Error(1)
+}
+
+func newEffortApp(s *MockedEffortServices, login string) *fiber.App {
+	h := &handlers.EffortHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tasks/:id/effort", h.TaskHandler)
+	app.Get("/reports/estimates", h.AccuracyHandler)
+
+	return app
+}
+
+func TestTaskEffortHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		original, variance, accuracy := 60, 30, 0.5
+		effort := entities.TaskEffort{
+			TaskID:           1,
+			EstimateUnit:     entities.EstimateMinutes,
+			OriginalEstimate: &original,
+			LoggedMinutes:    90,
+			Variance:         &variance,
+			Accuracy:         &accuracy,
+		}
+
+		s := new(MockedEffortServices)
+		s.On("TaskEffort", mock.Anything, uint64(1), "user").Return(effort, nil)
+		app := newEffortApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/effort", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded entities.TaskEffort
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, effort, encoded)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		s := new(MockedEffortServices)
+		s.On("TaskEffort", mock.Anything, uint64(1), "user").Return(entities.TaskEffort{}, entities.ErrNoTask)
+		app := newEffortApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/effort", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedEffortServices)
+		app := newEffortApp(s, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/effort", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "TaskEffort", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEstimateAccuracyHandler(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success request", func(t *testing.T) {
+		s := new(MockedEffortServices)
+		s.On("EstimateAccuracy", mock.Anything, "user", from, to).Return([]entities.EstimateAccuracy{{Login: "user", Tasks: 2}}, nil)
+		app := newEffortApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/estimates?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("not an admin", func(t *testing.T) {
+		s := new(MockedEffortServices)
+		s.On("EstimateAccuracy", mock.Anything, "user", from, to).Return([]entities.EstimateAccuracy(nil), entities.ErrForbidden)
+		app := newEffortApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/estimates?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		s := new(MockedEffortServices)
+		app := newEffortApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/estimates?from=today", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "EstimateAccuracy", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
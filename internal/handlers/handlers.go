@@ -4,7 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -12,21 +17,197 @@ import (
 )
 
 type Service interface {
-	Tasks(ctx context.Context, login string) ([]entities.Task, error)
+	Tasks(ctx context.Context, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error)
 	TaskAdd(ctx context.Context, task entities.Task, login string) (uint64, error)
 	Task(ctx context.Context, id uint64, login string) (entities.Task, error)
-	TaskRemove(ctx context.Context, id uint64, login string) error
-	TaskUpdate(ctx context.Context, task entities.Task, login string) error
+	TaskRemove(ctx context.Context, id uint64, login string, mode entities.RemoveMode) error
+	TaskUpdate(ctx context.Context, task entities.Task, fields []entities.TaskField, login string) error
+	TaskTransition(ctx context.Context, id uint64, to entities.TaskStatus, login string) (entities.Task, error)
+	TasksOverdue(ctx context.Context, login string) ([]entities.Task, error)
+	TasksUpcoming(ctx context.Context, login string, within time.Duration) ([]entities.Task, error)
+	TaskChildren(ctx context.Context, id uint64, login string) ([]entities.Task, error)
+	TaskTree(ctx context.Context, id uint64, login string) (entities.TaskNode, error)
 }
 
+const defaultUpcomingWithin = 24 * time.Hour
+
 type TasksHandler struct {
 	Service Service
 }
 
 type TaskJSON struct {
-	ID          uint64 `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          uint64  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Status      string  `json:"status,omitempty"`
+	Priority    string  `json:"priority,omitempty"`
+	StartAt     string  `json:"start_at,omitempty"`
+	DueAt       string  `json:"due_at,omitempty"`
+	ParentID    *uint64 `json:"parent_id,omitempty"`
+	Recurrence  string  `json:"recurrence,omitempty"`
+	ProjectID   *uint64 `json:"project_id,omitempty"`
+
+	EstimateUnit      string `json:"estimate_unit,omitempty"`
+	OriginalEstimate  *int   `json:"original_estimate,omitempty"`
+	RemainingEstimate *int   `json:"remaining_estimate,omitempty"`
+
+	Assignee string `json:"assignee,omitempty"`
+	Reporter string `json:"reporter,omitempty"`
+}
+
+// Convert DTO to task entity, dates are expected in RFC 3339 format
+func (t TaskJSON) entity() (entities.Task, error) {
+	task := entities.Task{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Status:      entities.TaskStatus(t.Status),
+		Priority:    entities.TaskPriority(t.Priority),
+		ParentID:    t.ParentID,
+		Recurrence:  t.Recurrence,
+		ProjectID:   t.ProjectID,
+
+		EstimateUnit:      entities.EstimateUnit(t.EstimateUnit),
+		OriginalEstimate:  t.OriginalEstimate,
+		RemainingEstimate: t.RemainingEstimate,
+
+		Assignee: t.Assignee,
+		Reporter: t.Reporter,
+	}
+
+	var err error
+	if task.StartAt, err = parseTime(t.StartAt); err != nil {
+		return task, err
+	}
+	if task.DueAt, err = parseTime(t.DueAt); err != nil {
+		return task, err
+	}
+
+	return task, nil
+}
+
+// taskFields returns updatable task fields present in JSON body.
+func taskFields(body []byte) ([]entities.TaskField, error) {
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(body, &present); err != nil {
+		return nil, err
+	}
+
+	var fields []entities.TaskField
+	for _, field := range entities.TaskUpdateFields {
+		if _, ok := present[string(field)]; ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// parseTaskFilter reads task filter from query string: comma separated statuses and labels,
+// label operator, assignee and sort keys. Assignee "me" stands for the requesting user.
+func parseTaskFilter(c *fiber.Ctx) (entities.TaskFilter, error) {
+	var filter entities.TaskFilter
+	if statuses := c.Query("status"); statuses != "" {
+		for _, s := range strings.Split(statuses, ",") {
+			status := entities.TaskStatus(s)
+			if !status.Valid() {
+				return filter, fmt.Errorf("%w: %q", entities.ErrUnknownStatus, s)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	if labels := c.Query("label"); labels != "" {
+		filter.Labels = strings.Split(labels, ",")
+		switch op := c.Query("label_op", "and"); op {
+		case "and":
+		case "or":
+			filter.LabelsAny = true
+		default:
+			return filter, fmt.Errorf("unknown label operator %q", op)
+		}
+	}
+	if assignee := c.Query("assignee"); assignee != "" {
+		if assignee == "me" {
+			assignee, _ = c.Locals(entities.UserLoginKey).(string)
+		}
+		filter.Assignee = assignee
+	}
+	if sort := c.Query("sort"); sort != "" {
+		var err error
+		filter.Sort, err = parseSort(sort)
+		if err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// parseSort parses comma separated sort keys like "-priority,due_at,id",
+// leading minus means descending order.
+func parseSort(s string) ([]entities.SortKey, error) {
+	var keys []entities.SortKey
+	for _, field := range strings.Split(s, ",") {
+		key := entities.SortKey{Field: field}
+		if strings.HasPrefix(field, "-") {
+			key = entities.SortKey{Field: field[1:], Desc: true}
+		}
+		if !slices.Contains(entities.TaskSortFields, key.Field) {
+			return nil, fmt.Errorf("%w: %q", entities.ErrInvalidSort, key.Field)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parsePage reads limit and cursor from query string.
+func parsePage(c *fiber.Ctx) (entities.PageRequest, error) {
+	page := entities.PageRequest{
+		Limit:  entities.DefaultPageLimit,
+		Cursor: c.Query("cursor"),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil || page.Limit <= 0 || page.Limit > entities.MaxPageLimit {
+			return page, fmt.Errorf("invalid page limit %q", limit)
+		}
+	}
+	return page, nil
+}
+
+// setNextLink points client to the next page keeping the rest of query parameters.
+func setNextLink(c *fiber.Ctx, page entities.PageRequest, cursor string) error {
+	if cursor == "" {
+		return nil
+	}
+
+	next, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return err
+	}
+	next.Set("cursor", cursor)
+	next.Set("limit", strconv.Itoa(page.Limit))
+	c.Set(fiber.HeaderLink, fmt.Sprintf(`<%s?%s>; rel="next"`, c.Path(), next.Encode()))
+	return nil
+}
+
+type TaskPageJSON struct {
+	Items      []entities.Task `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type TransitionJSON struct {
+	Status string `json:"status"`
 }
 
 func (h *TasksHandler) ItemHandler(c *fiber.Ctx) error {
@@ -56,7 +237,70 @@ func (h *TasksHandler) ListHandler(c *fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	tasks, err := h.Service.Tasks(c.Context(), login)
+	//Parse filter and page request from query string
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	tasks, err := h.Service.Tasks(c.Context(), login, filter, page)
+	if errors.Is(err, entities.ErrInvalidSort) || errors.Is(err, entities.ErrInvalidCursor) {
+		return fiber.ErrBadRequest
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err := setNextLink(c, page, tasks.NextCursor); err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if tasks.Tasks == nil {
+		tasks.Tasks = []entities.Task{}
+	}
+
+	return c.JSON(TaskPageJSON{
+		Items:      tasks.Tasks,
+		NextCursor: tasks.NextCursor,
+	})
+}
+
+func (h *TasksHandler) OverdueHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	tasks, err := h.Service.TasksOverdue(c.Context(), login)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(tasks)
+}
+
+func (h *TasksHandler) UpcomingHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	within := defaultUpcomingWithin
+	if w := c.Query("within"); w != "" {
+		var err error
+		within, err = time.ParseDuration(w)
+		if err != nil || within <= 0 {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	tasks, err := h.Service.TasksUpcoming(c.Context(), login, within)
 	if err != nil {
 		return fiber.ErrInternalServerError
 	}
@@ -72,20 +316,33 @@ func (h *TasksHandler) AddHandler(c *fiber.Ctx) error {
 	}
 
 	//Read body and parse JSON to DTO
-	var task entities.Task
-	err := json.Unmarshal(c.Body(), &task)
+	var taskJSON TaskJSON
+	err := json.Unmarshal(c.Body(), &taskJSON)
 	if err != nil {
 		return fiber.ErrBadRequest
 	}
 
-	//Convert to DTO
-	taskJSON := TaskJSON{
-		Name:        task.Name,
-		Description: task.Description,
+	//Convert to task entity
+	task, err := taskJSON.entity()
+	if err != nil {
+		return fiber.ErrBadRequest
 	}
+	task.Owner = login
 
 	//Add task with service
 	taskJSON.ID, err = h.Service.TaskAdd(c.Context(), task, login)
+	if errors.Is(err, entities.ErrUnknownStatus) || errors.Is(err, entities.ErrUnknownPriority) || errors.Is(err, entities.ErrInvalidDates) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrInvalidEstimate) || errors.Is(err, entities.ErrNoProject) || errors.Is(err, entities.ErrNoUser) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrProjectArchived) || errors.Is(err, entities.ErrInvalidTransition) || errors.Is(err, entities.ErrWIPLimit) {
+		return fiber.ErrConflict
+	}
+	if errors.Is(err, entities.ErrInvalidParent) || errors.Is(err, entities.ErrTaskTooDeep) || errors.Is(err, entities.ErrInvalidRecurrence) {
+		return fiber.ErrBadRequest
+	}
 	if err != nil {
 		return fiber.ErrInternalServerError
 	}
@@ -111,11 +368,21 @@ func (h *TasksHandler) RemoveHandler(c *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	//Removing task with service
-	err = h.Service.TaskRemove(c.Context(), taskId, login)
+	//Removing task with service, mode defines what happens with subtasks
+	mode := entities.RemoveMode(c.Query("mode"))
+	err = h.Service.TaskRemove(c.Context(), taskId, login, mode)
+	if errors.Is(err, entities.ErrUnknownRemoveMode) {
+		return fiber.ErrBadRequest
+	}
 	if errors.Is(err, entities.ErrNoTask) {
 		return fiber.ErrNotFound
 	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if errors.Is(err, entities.ErrTaskHasChildren) {
+		return fiber.ErrConflict
+	}
 	if err != nil {
 		return fiber.ErrInternalServerError
 	}
@@ -142,21 +409,130 @@ func (h *TasksHandler) UpdateHandler(c *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	//Convert to task entity
-	task := entities.Task{
-		ID:          uint64(taskId),
-		Name:        taskDTO.Name,
-		Description: taskDTO.Description,
+	//Convert to task entity, status is changed only with transitions and recurrence with series update
+	taskDTO.ID = taskId
+	taskDTO.Status = ""
+	taskDTO.Recurrence = ""
+	task, err := taskDTO.entity()
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Only fields present in the body are changed, null clears the field
+	fields, err := taskFields(c.Body())
+	if err != nil {
+		return fiber.ErrBadRequest
 	}
 
 	//Update task in service
-	err = h.Service.TaskUpdate(c.Context(), task, login)
+	err = h.Service.TaskUpdate(c.Context(), task, fields, login)
+	if errors.Is(err, entities.ErrUnknownPriority) || errors.Is(err, entities.ErrInvalidDates) || errors.Is(err, entities.ErrInvalidEstimate) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrInvalidParent) || errors.Is(err, entities.ErrTaskCycle) || errors.Is(err, entities.ErrTaskTooDeep) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoProject) || errors.Is(err, entities.ErrNoUser) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrProjectArchived) {
+		return fiber.ErrConflict
+	}
 	if errors.Is(err, entities.ErrNoTask) {
 		return fiber.ErrNotFound
 	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
 	if err != nil {
 		return fiber.ErrInternalServerError
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+func (h *TasksHandler) TransitionHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var transitionDTO TransitionJSON
+	err = json.Unmarshal(c.Body(), &transitionDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Move task to the new status with service
+	task, err := h.Service.TaskTransition(c.Context(), taskId, entities.TaskStatus(transitionDTO.Status), login)
+	if errors.Is(err, entities.ErrUnknownStatus) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if errors.Is(err, entities.ErrInvalidTransition) || errors.Is(err, entities.ErrOpenBlockers) || errors.Is(err, entities.ErrWIPLimit) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(task)
+}
+
+func (h *TasksHandler) ChildrenHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	tasks, err := h.Service.TaskChildren(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(tasks)
+}
+
+func (h *TasksHandler) TreeHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	tree, err := h.Service.TaskTree(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(tree)
+}
@@ -8,7 +8,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -22,9 +24,9 @@ type MockedServices struct {
 	mock.Mock
 }
 
-func (m *MockedServices) Tasks(ctx context.Context, login string) ([]entities.Task, error) {
-	args := m.Called(ctx, login)
-	return args.Get(0).([]entities.Task), args.Error(1)
+func (m *MockedServices) Tasks(ctx context.Context, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error) {
+	args := m.Called(ctx, login, filter, page)
+	return args.Get(0).(entities.TaskPage), args.Error(1)
 }
 
 func (m *MockedServices) Task(ctx context.Context, taskId uint64, login string) (entities.Task, error) {
@@ -37,16 +39,43 @@ func (m *MockedServices) TaskAdd(ctx context.Context, task entities.Task, login
 	return args.Get(0).(uint64), args.Error(1)
 }
 
-func (m *MockedServices) TaskRemove(ctx context.Context, id uint64, login string) error {
-	args := m.Called(ctx, id, login)
+func (m *MockedServices) TaskRemove(ctx context.Context, id uint64, login string, mode entities.RemoveMode) error {
+	args := m.Called(ctx, id, login, mode)
 	return args.Error(0)
 }
 
-func (m *MockedServices) TaskUpdate(ctx context.Context, task entities.Task, login string) error {
-	args := m.Called(ctx, task, login)
+func (m *MockedServices) TaskUpdate(ctx context.Context, task entities.Task, fields []entities.TaskField, login string) error {
+	args := m.Called(ctx, task, fields, login)
 	return args.Error(0)
 }
 
+func (m *MockedServices) TaskTransition(ctx context.Context, id uint64, to entities.TaskStatus, login string) (entities.Task, error) {
+	args := m.Called(ctx, id, to, login)
+	return args.Get(0).(entities.Task), args.Error(1)
+}
+
+func (m *MockedServices) TasksOverdue(ctx context.Context, login string) ([]entities.Task, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedServices) TasksUpcoming(ctx context.Context, login string, within time.Duration) ([]entities.Task, error) {
+	args := m.Called(ctx, login, within)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedServices) TaskChildren(ctx context.Context, id uint64, login string) ([]entities.Task, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedServices) TaskTree(ctx context.Context, id uint64, login string) (entities.TaskNode, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.TaskNode), args.Error(1)
+}
+
+var defaultPage = entities.PageRequest{Limit: entities.DefaultPageLimit}
+
 func TestTaskListHandler(t *testing.T) {
 
 	t.Run("success request", func(t *testing.T) {
@@ -62,7 +91,7 @@ func TestTaskListHandler(t *testing.T) {
 		h := &handlers.TasksHandler{
 			Service: s,
 		}
-		s.On("Tasks", mock.Anything, task.Owner).Return([]entities.Task{task}, nil)
+		s.On("Tasks", mock.Anything, task.Owner, entities.TaskFilter{}, defaultPage).Return(entities.TaskPage{Tasks: []entities.Task{task}}, nil)
 
 		app := fiber.New()
 		app.Use(func(c *fiber.Ctx) error {
@@ -92,12 +121,13 @@ func TestTaskListHandler(t *testing.T) {
 			t.Fatal("Error closing body:", err)
 		}
 
-		encoded := []entities.Task{}
+		encoded := handlers.TaskPageJSON{}
 		err = json.Unmarshal(body, &encoded)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 1, len(encoded))
-		assert.Equal(t, task, encoded[0])
+		assert.Equal(t, 1, len(encoded.Items))
+		assert.Equal(t, task, encoded.Items[0])
+		assert.Empty(t, encoded.NextCursor)
 
 		// s.AssertExpectations(t)
 	})
@@ -152,7 +182,7 @@ func TestTaskListHandler(t *testing.T) {
 		h := &handlers.TasksHandler{
 			Service: s,
 		}
-		s.On("Tasks", mock.Anything, login).Return([]entities.Task{}, fmt.Errorf("error"))
+		s.On("Tasks", mock.Anything, login, entities.TaskFilter{}, defaultPage).Return(entities.TaskPage{}, fmt.Errorf("error"))
 
 		app := fiber.New()
 		app.Use(func(c *fiber.Ctx) error {
@@ -451,7 +481,7 @@ func TestTaskRemoveHandler(t *testing.T) {
 		h := &handlers.TasksHandler{
 			Service: s,
 		}
-		s.On("TaskRemove", mock.Anything, task.ID, task.Owner).Return(nil)
+		s.On("TaskRemove", mock.Anything, task.ID, task.Owner, entities.RemoveMode("")).Return(nil)
 
 		app := fiber.New()
 		app.Use(func(c *fiber.Ctx) error {
@@ -523,7 +553,7 @@ func TestTaskRemoveHandler(t *testing.T) {
 		h := &handlers.TasksHandler{
 			Service: s,
 		}
-		s.On("TaskRemove", mock.Anything, taskId, login).Return(entities.ErrNoTask)
+		s.On("TaskRemove", mock.Anything, taskId, login, entities.RemoveMode("")).Return(entities.ErrNoTask)
 
 		app := fiber.New()
 		app.Use(func(c *fiber.Ctx) error {
@@ -549,7 +579,7 @@ func TestTaskRemoveHandler(t *testing.T) {
 		h := &handlers.TasksHandler{
 			Service: s,
 		}
-		s.On("TaskRemove", mock.Anything, taskId, login).Return(fmt.Errorf("error"))
+		s.On("TaskRemove", mock.Anything, taskId, login, entities.RemoveMode("")).Return(fmt.Errorf("error"))
 
 		app := fiber.New()
 		app.Use(func(c *fiber.Ctx) error {
@@ -568,6 +598,50 @@ func TestTaskRemoveHandler(t *testing.T) {
 	})
 }
 
+func TestTaskRemoveHandlerModes(t *testing.T) {
+	login := "user"
+	taskId := uint64(1)
+
+	cases := []struct {
+		name   string
+		query  string
+		mode   entities.RemoveMode
+		err    error
+		status int
+	}{
+		{"cascade mode", "?mode=cascade", entities.RemoveCascade, nil, http.StatusOK},
+		{"orphan mode", "?mode=orphan", entities.RemoveOrphan, nil, http.StatusOK},
+		{"task has subtasks", "", "", entities.ErrTaskHasChildren, http.StatusConflict},
+		{"unknown mode", "?mode=purge", "purge", entities.ErrUnknownRemoveMode, http.StatusBadRequest},
+		{"shared task", "", "", entities.ErrForbidden, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := new(MockedServices)
+			h := &handlers.TasksHandler{
+				Service: s,
+			}
+			s.On("TaskRemove", mock.Anything, taskId, login, tc.mode).Return(tc.err)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(entities.UserLoginKey, login)
+				return c.Next()
+			})
+			app.Delete("/tasks/:id", h.RemoveHandler)
+
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%d%s", taskId, tc.query), nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+
+			s.AssertExpectations(t)
+		})
+	}
+}
+
 func TestTaskUpdateHandler(t *testing.T) {
 	t.Run("success request", func(t *testing.T) {
 		taskId := uint64(1)
@@ -592,7 +666,7 @@ func TestTaskUpdateHandler(t *testing.T) {
 			Description: taskDTO.Description,
 		}
 
-		s.On("TaskUpdate", mock.Anything, task, login).Return(nil)
+		s.On("TaskUpdate", mock.Anything, task, []entities.TaskField{entities.TaskFieldName, entities.TaskFieldDescription}, login).Return(nil)
 
 		app := fiber.New()
 		app.Use(func(c *fiber.Ctx) error {
@@ -722,7 +796,7 @@ func TestTaskUpdateHandler(t *testing.T) {
 		})
 		app.Put("/tasks/:id", h.UpdateHandler)
 
-		s.On("TaskUpdate", mock.Anything, task, login).Return(entities.ErrNoTask)
+		s.On("TaskUpdate", mock.Anything, task, mock.Anything, login).Return(entities.ErrNoTask)
 
 		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%d", taskId), bytes.NewReader(body))
 		defer func() {
@@ -767,7 +841,7 @@ func TestTaskUpdateHandler(t *testing.T) {
 		})
 		app.Put("/tasks/:id", h.UpdateHandler)
 
-		s.On("TaskUpdate", mock.Anything, task, login).Return(fmt.Errorf("error"))
+		s.On("TaskUpdate", mock.Anything, task, mock.Anything, login).Return(fmt.Errorf("error"))
 
 		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%d", taskId), bytes.NewReader(body))
 		defer func() {
@@ -783,3 +857,862 @@ func TestTaskUpdateHandler(t *testing.T) {
 		s.AssertExpectations(t)
 	})
 }
+
+func TestTaskListHandlerStatusFilter(t *testing.T) {
+
+	t.Run("success request with status filter", func(t *testing.T) {
+
+		login := "user"
+		filter := entities.TaskFilter{
+			Statuses: []entities.TaskStatus{entities.StatusTodo, entities.StatusDone},
+		}
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("Tasks", mock.Anything, login, filter, defaultPage).Return(entities.TaskPage{}, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks?status=todo,done", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("unknown status in filter", func(t *testing.T) {
+
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks?status=finished", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		s.AssertNotCalled(t, "Tasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskTransitionHandler(t *testing.T) {
+
+	t.Run("success request", func(t *testing.T) {
+		taskId := uint64(1)
+		login := "user"
+		task := entities.Task{
+			ID:     taskId,
+			Name:   "Test task",
+			Owner:  login,
+			Status: entities.StatusDone,
+		}
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TaskTransition", mock.Anything, taskId, entities.StatusDone, login).Return(task, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Post("/tasks/:id/transitions", h.TransitionHandler)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/tasks/%d/transitions", taskId), bytes.NewReader([]byte(`{"status":"done"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("errors mapping", func(t *testing.T) {
+		taskId := uint64(1)
+		login := "user"
+
+		for _, tc := range []struct {
+			err    error
+			status int
+		}{
+			{entities.ErrInvalidTransition, http.StatusConflict},
+			{entities.ErrUnknownStatus, http.StatusBadRequest},
+			{entities.ErrNoTask, http.StatusNotFound},
+			{fmt.Errorf("error"), http.StatusInternalServerError},
+		} {
+			s := new(MockedServices)
+			h := &handlers.TasksHandler{
+				Service: s,
+			}
+			s.On("TaskTransition", mock.Anything, taskId, entities.StatusDone, login).Return(entities.Task{}, tc.err)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(entities.UserLoginKey, login)
+				return c.Next()
+			})
+			app.Post("/tasks/:id/transitions", h.TransitionHandler)
+
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/tasks/%d/transitions", taskId), bytes.NewReader([]byte(`{"status":"done"}`)))
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+
+			s.AssertExpectations(t)
+		}
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+
+		app := fiber.New()
+		app.Post("/tasks/:id/transitions", h.TransitionHandler)
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/transitions", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		s.AssertNotCalled(t, "TaskTransition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Post("/tasks/:id/transitions", h.TransitionHandler)
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/transitions", bytes.NewReader([]byte("{invalid json}")))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		s.AssertNotCalled(t, "TaskTransition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskAddHandlerDates(t *testing.T) {
+	t.Run("success request with dates", func(t *testing.T) {
+		login := "user"
+		startAt := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+		dueAt := time.Date(2025, 5, 3, 18, 0, 0, 0, time.UTC)
+		task := entities.Task{
+			Name:    "Test task",
+			Owner:   login,
+			StartAt: &startAt,
+			DueAt:   &dueAt,
+		}
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TaskAdd", mock.Anything, task, login).Return(uint64(1), nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Post("/tasks", h.AddHandler)
+
+		body := `{"name":"Test task","start_at":"2025-05-01T09:00:00Z","due_at":"2025-05-03T18:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("invalid date format", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Post("/tasks", h.AddHandler)
+
+		body := `{"name":"Test task","due_at":"03.05.2025"}`
+		req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		s.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("due date before start date", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TaskAdd", mock.Anything, mock.Anything, login).Return(uint64(0), entities.ErrInvalidDates)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Post("/tasks", h.AddHandler)
+
+		body := `{"name":"Test task","start_at":"2025-05-03T09:00:00Z","due_at":"2025-05-01T18:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("column of the status is full", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TaskAdd", mock.Anything, mock.Anything, login).Return(uint64(0), entities.ErrWIPLimit)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Post("/tasks", h.AddHandler)
+
+		body := `{"name":"Test task","status":"in_progress"}`
+		req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+}
+
+func TestTaskOverdueHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TasksOverdue", mock.Anything, login).Return([]entities.Task{{ID: 1, Owner: login}}, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks/overdue", h.OverdueHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/overdue", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("internal server error", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TasksOverdue", mock.Anything, login).Return([]entities.Task{}, fmt.Errorf("error"))
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks/overdue", h.OverdueHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/overdue", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestTaskUpcomingHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+
+		for url, within := range map[string]time.Duration{
+			"/tasks/upcoming":            24 * time.Hour,
+			"/tasks/upcoming?within=72h": 72 * time.Hour,
+		} {
+			s := new(MockedServices)
+			h := &handlers.TasksHandler{
+				Service: s,
+			}
+			s.On("TasksUpcoming", mock.Anything, login, within).Return([]entities.Task{}, nil)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(entities.UserLoginKey, login)
+				return c.Next()
+			})
+			app.Get("/tasks/upcoming", h.UpcomingHandler)
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			s.AssertExpectations(t)
+		}
+	})
+
+	t.Run("invalid within", func(t *testing.T) {
+		login := "user"
+
+		for _, within := range []string{"abc", "-1h", "0s"} {
+			s := new(MockedServices)
+			h := &handlers.TasksHandler{
+				Service: s,
+			}
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(entities.UserLoginKey, login)
+				return c.Next()
+			})
+			app.Get("/tasks/upcoming", h.UpcomingHandler)
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks/upcoming?within="+within, nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			s.AssertNotCalled(t, "TasksUpcoming", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestTaskListHandlerSort(t *testing.T) {
+
+	t.Run("success request with sort", func(t *testing.T) {
+
+		login := "user"
+		filter := entities.TaskFilter{
+			Sort: []entities.SortKey{
+				{Field: "priority", Desc: true},
+				{Field: "due_at"},
+				{Field: "id"},
+			},
+		}
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("Tasks", mock.Anything, login, filter, defaultPage).Return(entities.TaskPage{}, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks?sort=-priority,due_at,id", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("sort field not in whitelist", func(t *testing.T) {
+
+		login := "user"
+
+		for _, sort := range []string{"owner", "-description", "id;DROP TABLE tasks", "priority,"} {
+			s := new(MockedServices)
+			h := &handlers.TasksHandler{
+				Service: s,
+			}
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(entities.UserLoginKey, login)
+				return c.Next()
+			})
+			app.Get("/tasks", h.ListHandler)
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks?sort="+url.QueryEscape(sort), nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			s.AssertNotCalled(t, "Tasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestTaskListHandlerPagination(t *testing.T) {
+
+	t.Run("success request with next page", func(t *testing.T) {
+
+		login := "user"
+		page := entities.PageRequest{Limit: 2, Cursor: "abc"}
+		filter := entities.TaskFilter{Statuses: []entities.TaskStatus{entities.StatusTodo}}
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("Tasks", mock.Anything, login, filter, page).Return(entities.TaskPage{
+			Tasks:      []entities.Task{{ID: 3}, {ID: 4}},
+			NextCursor: "def",
+		}, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks?status=todo&limit=2&cursor=abc", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `</tasks?cursor=def&limit=2&status=todo>; rel="next"`, resp.Header.Get(fiber.HeaderLink))
+
+		encoded := handlers.TaskPageJSON{}
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(encoded.Items))
+		assert.Equal(t, "def", encoded.NextCursor)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("last page has no link", func(t *testing.T) {
+
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("Tasks", mock.Anything, login, entities.TaskFilter{}, defaultPage).Return(entities.TaskPage{}, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(fiber.HeaderLink))
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"items":[]}`, string(body))
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+
+		login := "user"
+
+		for _, limit := range []string{"abc", "0", "-5", "501"} {
+			s := new(MockedServices)
+			h := &handlers.TasksHandler{
+				Service: s,
+			}
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(entities.UserLoginKey, login)
+				return c.Next()
+			})
+			app.Get("/tasks", h.ListHandler)
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks?limit="+limit, nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			s.AssertNotCalled(t, "Tasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+
+		login := "user"
+		page := entities.PageRequest{Limit: entities.DefaultPageLimit, Cursor: "broken"}
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("Tasks", mock.Anything, login, entities.TaskFilter{}, page).Return(entities.TaskPage{}, entities.ErrInvalidCursor)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks?cursor=broken", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+}
+
+func TestTaskListHandlerLabelFilter(t *testing.T) {
+
+	t.Run("success request with label filter", func(t *testing.T) {
+
+		login := "user"
+
+		for url, filter := range map[string]entities.TaskFilter{
+			"/tasks?label=bug,ui":              {Labels: []string{"bug", "ui"}},
+			"/tasks?label=bug,ui&label_op=and": {Labels: []string{"bug", "ui"}},
+			"/tasks?label=bug,ui&label_op=or":  {Labels: []string{"bug", "ui"}, LabelsAny: true},
+		} {
+			s := new(MockedServices)
+			h := &handlers.TasksHandler{
+				Service: s,
+			}
+			s.On("Tasks", mock.Anything, login, filter, defaultPage).Return(entities.TaskPage{}, nil)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(entities.UserLoginKey, login)
+				return c.Next()
+			})
+			app.Get("/tasks", h.ListHandler)
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			s.AssertExpectations(t)
+		}
+	})
+
+	t.Run("unknown label operator", func(t *testing.T) {
+
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks?label=bug&label_op=xor", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		s.AssertNotCalled(t, "Tasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskListHandlerAssigneeFilter(t *testing.T) {
+
+	login := "user"
+
+	for url, filter := range map[string]entities.TaskFilter{
+		"/tasks?assignee=me":     {Assignee: login},
+		"/tasks?assignee=friend": {Assignee: "friend"},
+	} {
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("Tasks", mock.Anything, login, filter, defaultPage).Return(entities.TaskPage{}, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks", h.ListHandler)
+
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	}
+}
+
+func TestTaskChildrenHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+		parentID := uint64(1)
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TaskChildren", mock.Anything, parentID, login).Return([]entities.Task{{ID: 2, ParentID: &parentID, Owner: login}}, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks/:id/children", h.ChildrenHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/children", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var tasks []entities.Task
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&tasks))
+		if assert.Equal(t, 1, len(tasks)) {
+			assert.Equal(t, &parentID, tasks[0].ParentID)
+		}
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TaskChildren", mock.Anything, uint64(1), login).Return([]entities.Task(nil), entities.ErrNoTask)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks/:id/children", h.ChildrenHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/children", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		s.AssertExpectations(t)
+	})
+}
+
+func TestTaskTreeHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+		tree := entities.TaskNode{
+			Task:     entities.Task{ID: 1, Owner: login},
+			Children: []entities.TaskNode{{Task: entities.Task{ID: 2, Owner: login}}},
+		}
+
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		s.On("TaskTree", mock.Anything, uint64(1), login).Return(tree, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Get("/tasks/:id/tree", h.TreeHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/tree", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res entities.TaskNode
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		assert.Equal(t, tree, res)
+
+		s.AssertExpectations(t)
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedServices)
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+
+		app := fiber.New()
+		app.Get("/tasks/:id/tree", h.TreeHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/tree", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		s.AssertNotCalled(t, "TaskTree")
+	})
+}
+
+func TestTaskUpdateHandlerFields(t *testing.T) {
+	login := "user"
+	s := new(MockedServices)
+	h := &handlers.TasksHandler{
+		Service: s,
+	}
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(entities.UserLoginKey, login)
+		return c.Next()
+	})
+	app.Put("/tasks/:id", h.UpdateHandler)
+
+	// Omitted fields are not listed, null clears the parent
+	fields := []entities.TaskField{entities.TaskFieldName, entities.TaskFieldParent}
+	s.On("TaskUpdate", mock.Anything, entities.Task{ID: 1, Name: "renamed"}, fields, login).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/tasks/1", bytes.NewReader([]byte(`{"name":"renamed","parent_id":null,"status":"done"}`)))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	s.AssertExpectations(t)
+}
+
+func TestTaskUpdateHandlerEstimates(t *testing.T) {
+	login := "user"
+	newApp := func(s *MockedServices) *fiber.App {
+		h := &handlers.TasksHandler{
+			Service: s,
+		}
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+		app.Put("/tasks/:id", h.UpdateHandler)
+		return app
+	}
+
+	t.Run("success request", func(t *testing.T) {
+		original, remaining := 120, 30
+		task := entities.Task{
+			ID:                1,
+			Name:              "Test task",
+			EstimateUnit:      entities.EstimateMinutes,
+			OriginalEstimate:  &original,
+			RemainingEstimate: &remaining,
+		}
+
+		s := new(MockedServices)
+		fields := []entities.TaskField{entities.TaskFieldName, entities.TaskFieldEstimateUnit, entities.TaskFieldOriginalEstimate, entities.TaskFieldRemainingEstimate}
+		s.On("TaskUpdate", mock.Anything, task, fields, login).Return(nil)
+		app := newApp(s)
+
+		body := `{"name":"Test task","estimate_unit":"minutes","original_estimate":120,"remaining_estimate":30}`
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("invalid estimate", func(t *testing.T) {
+		s := new(MockedServices)
+		s.On("TaskUpdate", mock.Anything, mock.Anything, mock.Anything, login).Return(entities.ErrInvalidEstimate)
+		app := newApp(s)
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1", bytes.NewReader([]byte(`{"name":"Test task","estimate_unit":"days"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type HistoryService interface {
+	TaskHistory(ctx context.Context, id uint64, login string) ([]entities.TaskEvent, error)
+	TaskRevert(ctx context.Context, id uint64, eventID uint64, login string) (entities.Task, error)
+}
+
+type HistoryHandler struct {
+	Service HistoryService
+}
+
+// ListHandler returns the timeline of the task changes, oldest first.
+func (h *HistoryHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	events, err := h.Service.TaskHistory(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if events == nil {
+		events = []entities.TaskEvent{}
+	}
+
+	return c.JSON(events)
+}
+
+// RevertHandler restores the task as it was before the event and returns the task.
+func (h *HistoryHandler) RevertHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	eventId, err := strconv.ParseUint(c.Params("eventId"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	task, err := h.Service.TaskRevert(c.Context(), taskId, eventId, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoTaskEvent) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	// Parent or project of the revision may be gone since
+	if errors.Is(err, entities.ErrInvalidParent) || errors.Is(err, entities.ErrTaskTooDeep) || errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(task)
+}
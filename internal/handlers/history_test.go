@@ -0,0 +1,115 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedHistoryServices struct {
+	mock.Mock
+}
+
+func (m *MockedHistoryServices) TaskHistory(ctx context.Context, id uint64, login string) ([]entities.TaskEvent, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).([]entities.TaskEvent), args.Error(1)
+}
+
+func (m *MockedHistoryServices) TaskRevert(ctx context.Context, id uint64, eventID uint64, login string) (entities.Task, error) {
+	args := m.Called(ctx, id, eventID, login)
+	return args.Get(0).(entities.Task), args.Error(1)
+}
+
+func newHistoryApp(s *MockedHistoryServices, login string) *fiber.App {
+	h := &handlers.HistoryHandler{Service: s}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tasks/:id/history", h.ListHandler)
+	app.Post("/tasks/:id/history/:eventId/revert", h.RevertHandler)
+
+	return app
+}
+
+func TestHistoryListHandler(t *testing.T) {
+	t.Run("timeline of the task", func(t *testing.T) {
+		events := []entities.TaskEvent{{ID: 1, TaskID: 1, Author: "user", Field: entities.TaskFieldName, OldValue: "a", NewValue: "b"}}
+		s := new(MockedHistoryServices)
+		s.On("TaskHistory", mock.Anything, uint64(1), "user").Return(events, nil)
+		app := newHistoryApp(s, "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/tasks/1/history", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.TaskEvent
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, events, encoded)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		s := new(MockedHistoryServices)
+		s.On("TaskHistory", mock.Anything, uint64(1), "user").Return([]entities.TaskEvent(nil), entities.ErrNoTask)
+		app := newHistoryApp(s, "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/tasks/1/history", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("unauthorized without login", func(t *testing.T) {
+		app := newHistoryApp(new(MockedHistoryServices), "")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/tasks/1/history", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestHistoryRevertHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"task reverted", nil, http.StatusOK},
+		{"task not found", entities.ErrNoTask, http.StatusNotFound},
+		{"event not found", entities.ErrNoTaskEvent, http.StatusNotFound},
+		{"not an editor", entities.ErrForbidden, http.StatusForbidden},
+		{"parent of the revision is gone", entities.ErrInvalidParent, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockedHistoryServices)
+			s.On("TaskRevert", mock.Anything, uint64(1), uint64(5), "user").Return(entities.Task{ID: 1, Name: "a"}, tt.err)
+			app := newHistoryApp(s, "user")
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/tasks/1/history/5/revert", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	t.Run("bad event id", func(t *testing.T) {
+		app := newHistoryApp(new(MockedHistoryServices), "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/tasks/1/history/abc/revert", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type LabelService interface {
+	Labels(ctx context.Context, login string) ([]entities.Label, error)
+	Label(ctx context.Context, id uint64, login string) (entities.Label, error)
+	LabelAdd(ctx context.Context, label entities.Label, login string) (uint64, error)
+	LabelUpdate(ctx context.Context, label entities.Label, login string) error
+	LabelRemove(ctx context.Context, id uint64, login string) error
+	TaskLabelAttach(ctx context.Context, taskID uint64, labelID uint64, login string) error
+	TaskLabelDetach(ctx context.Context, taskID uint64, labelID uint64, login string) error
+}
+
+type LabelsHandler struct {
+	Service LabelService
+}
+
+type LabelJSON struct {
+	ID    uint64 `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (h *LabelsHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	labels, err := h.Service.Labels(c.Context(), login)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(labels)
+}
+
+func (h *LabelsHandler) ItemHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	labelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	label, err := h.Service.Label(c.Context(), labelId, login)
+	if errors.Is(err, entities.ErrNoLabel) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(label)
+}
+
+func (h *LabelsHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	//Read body and parse JSON to DTO
+	var labelJSON LabelJSON
+	err := json.Unmarshal(c.Body(), &labelJSON)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Convert to label entity
+	label := entities.Label{
+		Name:  labelJSON.Name,
+		Color: labelJSON.Color,
+		Owner: login,
+	}
+
+	//Add label with service
+	labelJSON.ID, err = h.Service.LabelAdd(c.Context(), label, login)
+	if errors.Is(err, entities.ErrInvalidLabel) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrLabelExists) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(labelJSON.ID)
+}
+
+func (h *LabelsHandler) UpdateHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	labelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var labelJSON LabelJSON
+	err = json.Unmarshal(c.Body(), &labelJSON)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Convert to label entity
+	label := entities.Label{
+		ID:    labelId,
+		Name:  labelJSON.Name,
+		Color: labelJSON.Color,
+	}
+
+	//Update label in service
+	err = h.Service.LabelUpdate(c.Context(), label, login)
+	if errors.Is(err, entities.ErrInvalidLabel) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoLabel) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrLabelExists) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *LabelsHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	labelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.LabelRemove(c.Context(), labelId, login)
+	if errors.Is(err, entities.ErrNoLabel) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (h *LabelsHandler) AttachHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, labelId, err := taskLabelParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TaskLabelAttach(c.Context(), taskId, labelId, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoLabel) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *LabelsHandler) DetachHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, labelId, err := taskLabelParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TaskLabelDetach(c.Context(), taskId, labelId, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoLabel) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func taskLabelParams(c *fiber.Ctx) (uint64, uint64, error) {
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	labelId, err := strconv.ParseUint(c.Params("labelId"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return taskId, labelId, nil
+}
@@ -0,0 +1,315 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedLabelServices struct {
+	mock.Mock
+}
+
+func (m *MockedLabelServices) Labels(ctx context.Context, login string) ([]entities.Label, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Label), args.Error(1)
+}
+
+func (m *MockedLabelServices) Label(ctx context.Context, id uint64, login string) (entities.Label, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.Label), args.Error(1)
+}
+
+func (m *MockedLabelServices) LabelAdd(ctx context.Context, label entities.Label, login string) (uint64, error) {
+	args := m.Called(ctx, label, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedLabelServices) LabelUpdate(ctx context.Context, label entities.Label, login string) error {
+	args := m.Called(ctx, label, login)
+	return args.Error(0)
+}
+
+func (m *MockedLabelServices) LabelRemove(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedLabelServices) TaskLabelAttach(ctx context.Context, taskID uint64, labelID uint64, login string) error {
+	args := m.Called(ctx, taskID, labelID, login)
+	return args.Error(0)
+}
+
+func (m *MockedLabelServices) TaskLabelDetach(ctx context.Context, taskID uint64, labelID uint64, login string) error {
+	args := m.Called(ctx, taskID, labelID, login)
+	return args.Error(0)
+}
+
+func newLabelsApp(s *MockedLabelServices, login string) *fiber.App {
+	h := &handlers.LabelsHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/labels", h.ListHandler)
+	app.Get("/labels/:id", h.ItemHandler)
+	app.Post("/labels", h.AddHandler)
+	app.Put("/labels/:id", h.UpdateHandler)
+	app.Delete("/labels/:id", h.RemoveHandler)
+	app.Put("/tasks/:id/labels/:labelId", h.AttachHandler)
+	app.Delete("/tasks/:id/labels/:labelId", h.DetachHandler)
+
+	return app
+}
+
+func TestLabelListHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+		labels := []entities.Label{{ID: 1, Name: "bug", Owner: login}}
+
+		s := new(MockedLabelServices)
+		s.On("Labels", mock.Anything, login).Return(labels, nil)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodGet, "/labels", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.Label
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, labels, encoded)
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedLabelServices)
+		app := newLabelsApp(s, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/labels", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "Labels", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLabelItemHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+		label := entities.Label{ID: 1, Name: "bug", Owner: login}
+
+		s := new(MockedLabelServices)
+		s.On("Label", mock.Anything, label.ID, login).Return(label, nil)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodGet, "/labels/1", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("label not found", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedLabelServices)
+		s.On("Label", mock.Anything, uint64(1), login).Return(entities.Label{}, entities.ErrNoLabel)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodGet, "/labels/1", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestLabelAddHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+		label := entities.Label{Name: "bug", Color: "red", Owner: login}
+
+		s := new(MockedLabelServices)
+		s.On("LabelAdd", mock.Anything, label, login).Return(uint64(1), nil)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodPost, "/labels", bytes.NewReader([]byte(`{"name":"bug","color":"red"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("errors mapping", func(t *testing.T) {
+		login := "user"
+
+		for _, tc := range []struct {
+			err    error
+			status int
+		}{
+			{entities.ErrInvalidLabel, http.StatusBadRequest},
+			{entities.ErrLabelExists, http.StatusConflict},
+			{fmt.Errorf("error"), http.StatusInternalServerError},
+		} {
+			s := new(MockedLabelServices)
+			s.On("LabelAdd", mock.Anything, mock.Anything, login).Return(uint64(0), tc.err)
+			app := newLabelsApp(s, login)
+
+			req := httptest.NewRequest(http.MethodPost, "/labels", bytes.NewReader([]byte(`{"name":"bug"}`)))
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		s := new(MockedLabelServices)
+		app := newLabelsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/labels", bytes.NewReader([]byte("{invalid json}")))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "LabelAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLabelUpdateHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+		label := entities.Label{ID: 1, Name: "bug"}
+
+		s := new(MockedLabelServices)
+		s.On("LabelUpdate", mock.Anything, label, login).Return(nil)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodPut, "/labels/1", bytes.NewReader([]byte(`{"name":"bug"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("label not found", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedLabelServices)
+		s.On("LabelUpdate", mock.Anything, mock.Anything, login).Return(entities.ErrNoLabel)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodPut, "/labels/1", bytes.NewReader([]byte(`{"name":"bug"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestLabelRemoveHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedLabelServices)
+		s.On("LabelRemove", mock.Anything, uint64(1), login).Return(nil)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodDelete, "/labels/1", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("invalid id format", func(t *testing.T) {
+		s := new(MockedLabelServices)
+		app := newLabelsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodDelete, "/labels/abc", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "LabelRemove", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskLabelHandlers(t *testing.T) {
+	t.Run("success attaching", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedLabelServices)
+		s.On("TaskLabelAttach", mock.Anything, uint64(1), uint64(2), login).Return(nil)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/labels/2", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("attaching to unexisted task", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedLabelServices)
+		s.On("TaskLabelAttach", mock.Anything, uint64(1), uint64(2), login).Return(entities.ErrNoTask)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/labels/2", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("success detaching", func(t *testing.T) {
+		login := "user"
+
+		s := new(MockedLabelServices)
+		s.On("TaskLabelDetach", mock.Anything, uint64(1), uint64(2), login).Return(nil)
+		app := newLabelsApp(s, login)
+
+		req := httptest.NewRequest(http.MethodDelete, "/tasks/1/labels/2", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("invalid label id", func(t *testing.T) {
+		s := new(MockedLabelServices)
+		app := newLabelsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodDelete, "/tasks/1/labels/abc", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "TaskLabelDetach", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
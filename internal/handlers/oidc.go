@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+// BindingCookie keeps the binding of the pending login in the browser which started it.
+const BindingCookie = "wp_oidc_binding"
+
+type OIDCService interface {
+	LoginURL(ctx context.Context) (string, string, error)
+	Callback(ctx context.Context, code string, state string, binding string) (entities.Session, error)
+}
+
+// OIDCHandler serves the identity provider login, its routes are public.
+// SecureCookie limits the binding cookie to HTTPS, it is set when the service is served over it.
+type OIDCHandler struct {
+	Service      OIDCService
+	SecureCookie bool
+}
+
+// LoginHandler sends the browser to the provider login page.
+func (h *OIDCHandler) LoginHandler(c *fiber.Ctx) error {
+
+	url, binding, err := h.Service.LoginURL(c.Context())
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Cookie(h.bindingCookie(binding, time.Time{}))
+
+	return c.Redirect(url, fiber.StatusFound)
+}
+
+// bindingCookie is not readable by scripts and is sent along the top level redirect from the provider
+// only, zero expiry keeps it for the browser session.
+func (h *OIDCHandler) bindingCookie(value string, expires time.Time) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     BindingCookie,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   h.SecureCookie,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+}
+
+// CallbackHandler finishes the login the provider redirected back with and returns the session token.
+func (h *OIDCHandler) CallbackHandler(c *fiber.Ctx) error {
+
+	// Provider reports a denied or failed login instead of the code
+	if c.Query("error") != "" {
+		return fiber.ErrUnauthorized
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	binding := c.Cookies(BindingCookie)
+	if code == "" || state == "" || binding == "" {
+		return fiber.ErrBadRequest
+	}
+
+	// Binding is good for one callback whatever its result
+	c.Cookie(h.bindingCookie("", time.Unix(0, 0)))
+
+	session, err := h.Service.Callback(c.Context(), code, state, binding)
+	if errors.Is(err, entities.ErrNoOIDCState) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrOIDCLogin) {
+		return fiber.ErrUnauthorized
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(session)
+}
@@ -0,0 +1,142 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedOIDCServices struct {
+	mock.Mock
+}
+
+func (m *MockedOIDCServices) LoginURL(ctx context.Context) (string, string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockedOIDCServices) Callback(ctx context.Context, code string, state string, binding string) (entities.Session, error) {
+	args := m.Called(ctx, code, state, binding)
+	return args.Get(0).(entities.Session), args.Error(1)
+}
+
+func newOIDCApp(s *MockedOIDCServices) *fiber.App {
+	h := &handlers.OIDCHandler{Service: s, SecureCookie: true}
+
+	app := fiber.New()
+	app.Get("/auth/oidc/login", h.LoginHandler)
+	app.Get("/auth/oidc/callback", h.CallbackHandler)
+
+	return app
+}
+
+func TestOIDCLoginHandler(t *testing.T) {
+	s := new(MockedOIDCServices)
+	s.On("LoginURL", mock.Anything).Return("https://issuer.example/authorize?state=abc", "binding", nil)
+	app := newOIDCApp(s)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "https://issuer.example/authorize?state=abc", resp.Header.Get("Location"))
+
+	// Browser keeps the binding in a cookie scripts can not read
+	cookies := resp.Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, handlers.BindingCookie, cookies[0].Name)
+		assert.Equal(t, "binding", cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.True(t, cookies[0].Secure)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	}
+}
+
+// callbackRequest is the redirect from the provider in the browser holding the binding cookie.
+func callbackRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.AddCookie(&http.Cookie{Name: handlers.BindingCookie, Value: "binding"})
+	return req
+}
+
+func TestOIDCCallbackHandler(t *testing.T) {
+	t.Run("session issued", func(t *testing.T) {
+		session := entities.Session{Login: "alice", Token: "wpt_session", ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+		s := new(MockedOIDCServices)
+		s.On("Callback", mock.Anything, "code", "state", "binding").Return(session, nil)
+		app := newOIDCApp(s)
+
+		resp, err := app.Test(callbackRequest("/auth/oidc/callback?code=code&state=state"))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+
+		// Binding is removed
+		cookies := resp.Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, handlers.BindingCookie, cookies[0].Name)
+			assert.Empty(t, cookies[0].Value)
+			assert.True(t, cookies[0].Expires.Before(time.Now()))
+		}
+
+		var encoded entities.Session
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, session, encoded)
+	})
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"unknown state", entities.ErrNoOIDCState, http.StatusBadRequest},
+		{"provider login failed", entities.ErrOIDCLogin, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockedOIDCServices)
+			s.On("Callback", mock.Anything, "code", "state", "binding").Return(entities.Session{}, tt.err)
+			app := newOIDCApp(s)
+
+			resp, err := app.Test(callbackRequest("/auth/oidc/callback?code=code&state=state"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	t.Run("missing code", func(t *testing.T) {
+		app := newOIDCApp(new(MockedOIDCServices))
+
+		resp, err := app.Test(callbackRequest("/auth/oidc/callback?state=state"))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("browser without the binding cookie", func(t *testing.T) {
+		s := new(MockedOIDCServices)
+		app := newOIDCApp(s)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?code=code&state=state", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "Callback", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("login denied by the provider", func(t *testing.T) {
+		app := newOIDCApp(new(MockedOIDCServices))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?error=access_denied&state=state", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
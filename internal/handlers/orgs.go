@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type OrgService interface {
+	Orgs(ctx context.Context, login string) ([]entities.Organization, error)
+	OrgAdd(ctx context.Context, org entities.Organization, login string) (uint64, error)
+	Members(ctx context.Context, id uint64, login string) ([]entities.Membership, error)
+	MemberSet(ctx context.Context, member entities.Membership, login string) error
+	MemberRemove(ctx context.Context, id uint64, member string, login string) error
+}
+
+type OrgsHandler struct {
+	Service OrgService
+}
+
+type OrgJSON struct {
+	Name string `json:"name"`
+}
+
+type MemberJSON struct {
+	Role string `json:"role"`
+}
+
+// ListHandler returns organizations of the user with the user role in each of them.
+func (h *OrgsHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	orgs, err := h.Service.Orgs(c.Context(), login)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if orgs == nil {
+		orgs = []entities.Organization{}
+	}
+
+	return c.JSON(orgs)
+}
+
+func (h *OrgsHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	//Read body and parse JSON to DTO
+	var orgDTO OrgJSON
+	err := json.Unmarshal(c.Body(), &orgDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	id, err := h.Service.OrgAdd(c.Context(), entities.Organization{Name: orgDTO.Name}, login)
+	if errors.Is(err, entities.ErrInvalidOrg) {
+		return fiber.ErrBadRequest
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(id)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *OrgsHandler) MembersHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	orgId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	members, err := h.Service.Members(c.Context(), orgId, login)
+	if errors.Is(err, entities.ErrNoOrg) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if members == nil {
+		members = []entities.Membership{}
+	}
+
+	return c.JSON(members)
+}
+
+// MemberSetHandler adds the login from the URL to the organization with the role from the body.
+func (h *OrgsHandler) MemberSetHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	orgId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var memberDTO MemberJSON
+	err = json.Unmarshal(c.Body(), &memberDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	member := entities.Membership{
+		OrgID: orgId,
+		Login: c.Params("login"),
+		Role:  entities.OrgRole(memberDTO.Role),
+	}
+	err = h.Service.MemberSet(c.Context(), member, login)
+	if errors.Is(err, entities.ErrInvalidOrg) || errors.Is(err, entities.ErrNoUser) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoOrg) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if errors.Is(err, entities.ErrLastOwner) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *OrgsHandler) MemberRemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	orgId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.MemberRemove(c.Context(), orgId, c.Params("login"), login)
+	if errors.Is(err, entities.ErrNoOrg) || errors.Is(err, entities.ErrNoMember) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if errors.Is(err, entities.ErrLastOwner) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,205 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedOrgServices struct {
+	mock.Mock
+}
+
+func (m *MockedOrgServices) Orgs(ctx context.Context, login string) ([]entities.Organization, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Organization), args.Error(1)
+}
+
+func (m *MockedOrgServices) OrgAdd(ctx context.Context, org entities.Organization, login string) (uint64, error) {
+	args := m.Called(ctx, org, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedOrgServices) Members(ctx context.Context, id uint64, login string) ([]entities.Membership, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).([]entities.Membership), args.Error(1)
+}
+
+func (m *MockedOrgServices) MemberSet(ctx context.Context, member entities.Membership, login string) error {
+	args := m.Called(ctx, member, login)
+	return args.Error(0)
+}
+
+func (m *MockedOrgServices) MemberRemove(ctx context.Context, id uint64, member string, login string) error {
+	args := m.Called(ctx, id, member, login)
+	return args.Error(0)
+}
+
+func newOrgsApp(s *MockedOrgServices, login string) *fiber.App {
+	h := &handlers.OrgsHandler{Service: s}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/orgs", h.ListHandler)
+	app.Post("/orgs", h.AddHandler)
+	app.Get("/orgs/:id/members", h.MembersHandler)
+	app.Put("/orgs/:id/members/:login", h.MemberSetHandler)
+	app.Delete("/orgs/:id/members/:login", h.MemberRemoveHandler)
+
+	return app
+}
+
+func TestOrgListHandler(t *testing.T) {
+	t.Run("organizations of the user", func(t *testing.T) {
+		orgs := []entities.Organization{{ID: 1, Name: "user", Role: entities.OrgOwner}}
+		s := new(MockedOrgServices)
+		s.On("Orgs", mock.Anything, "user").Return(orgs, nil)
+		app := newOrgsApp(s, "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orgs", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.Organization
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, orgs, encoded)
+	})
+
+	t.Run("unauthorized without login", func(t *testing.T) {
+		app := newOrgsApp(new(MockedOrgServices), "")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orgs", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestOrgAddHandler(t *testing.T) {
+	t.Run("organization added", func(t *testing.T) {
+		s := new(MockedOrgServices)
+		s.On("OrgAdd", mock.Anything, entities.Organization{Name: "team"}, "user").Return(uint64(2), nil)
+		app := newOrgsApp(s, "user")
+
+		body, _ := json.Marshal(handlers.OrgJSON{Name: "team"})
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orgs", bytes.NewReader(body)))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		s := new(MockedOrgServices)
+		s.On("OrgAdd", mock.Anything, entities.Organization{Name: ""}, "user").Return(uint64(0), entities.ErrInvalidOrg)
+		app := newOrgsApp(s, "user")
+
+		body, _ := json.Marshal(handlers.OrgJSON{})
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orgs", bytes.NewReader(body)))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestOrgMembersHandler(t *testing.T) {
+	t.Run("members of the organization", func(t *testing.T) {
+		members := []entities.Membership{{OrgID: 1, Login: "user", Role: entities.OrgOwner}}
+		s := new(MockedOrgServices)
+		s.On("Members", mock.Anything, uint64(1), "user").Return(members, nil)
+		app := newOrgsApp(s, "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orgs/1/members", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.Membership
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, members, encoded)
+	})
+
+	t.Run("not found for other users", func(t *testing.T) {
+		s := new(MockedOrgServices)
+		s.On("Members", mock.Anything, uint64(1), "user").Return([]entities.Membership(nil), entities.ErrNoOrg)
+		app := newOrgsApp(s, "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orgs/1/members", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestOrgMemberSetHandler(t *testing.T) {
+	member := entities.Membership{OrgID: 1, Login: "friend", Role: entities.OrgAdmin}
+	body, _ := json.Marshal(handlers.MemberJSON{Role: "admin"})
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"member set", nil, http.StatusNoContent},
+		{"invalid role", entities.ErrInvalidOrg, http.StatusBadRequest},
+		{"unknown user", entities.ErrNoUser, http.StatusBadRequest},
+		{"not a member", entities.ErrNoOrg, http.StatusNotFound},
+		{"not an admin", entities.ErrForbidden, http.StatusForbidden},
+		{"last owner", entities.ErrLastOwner, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockedOrgServices)
+			s.On("MemberSet", mock.Anything, member, "user").Return(tt.err)
+			app := newOrgsApp(s, "user")
+
+			req := httptest.NewRequest(http.MethodPut, "/orgs/1/members/friend", bytes.NewReader(body))
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestOrgMemberRemoveHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"member removed", nil, http.StatusNoContent},
+		{"unknown member", entities.ErrNoMember, http.StatusNotFound},
+		{"not an admin", entities.ErrForbidden, http.StatusForbidden},
+		{"last owner", entities.ErrLastOwner, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockedOrgServices)
+			s.On("MemberRemove", mock.Anything, uint64(1), "friend", "user").Return(tt.err)
+			app := newOrgsApp(s, "user")
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/orgs/1/members/friend", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	t.Run("bad organization id", func(t *testing.T) {
+		app := newOrgsApp(new(MockedOrgServices), "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/orgs/abc/members/friend", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type ProjectService interface {
+	Projects(ctx context.Context, login string, archived bool) ([]entities.Project, error)
+	Project(ctx context.Context, id uint64, login string) (entities.Project, error)
+	ProjectAdd(ctx context.Context, project entities.Project, login string) (uint64, error)
+	ProjectUpdate(ctx context.Context, project entities.Project, login string) error
+	ProjectRemove(ctx context.Context, id uint64, login string) error
+	ProjectTasks(ctx context.Context, id uint64, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error)
+}
+
+type ProjectsHandler struct {
+	Service ProjectService
+}
+
+type ProjectJSON struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Archived    bool   `json:"archived"`
+}
+
+// ListHandler returns active projects, archived ones are included with "archived=true".
+func (h *ProjectsHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	projects, err := h.Service.Projects(c.Context(), login, c.QueryBool("archived"))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if projects == nil {
+		projects = []entities.Project{}
+	}
+
+	return c.JSON(projects)
+}
+
+func (h *ProjectsHandler) ItemHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	projectId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	project, err := h.Service.Project(c.Context(), projectId, login)
+	if errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(project)
+}
+
+func (h *ProjectsHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	//Read body and parse JSON to DTO
+	var projectDTO ProjectJSON
+	err := json.Unmarshal(c.Body(), &projectDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	project := entities.Project{
+		Key:         projectDTO.Key,
+		Name:        projectDTO.Name,
+		Description: projectDTO.Description,
+	}
+	id, err := h.Service.ProjectAdd(c.Context(), project, login)
+	if errors.Is(err, entities.ErrInvalidProject) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrProjectExists) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(id)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// UpdateHandler changes project name, description and archived flag, the key can not be changed.
+func (h *ProjectsHandler) UpdateHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	projectId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var projectDTO ProjectJSON
+	err = json.Unmarshal(c.Body(), &projectDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	project := entities.Project{
+		ID:          projectId,
+		Name:        projectDTO.Name,
+		Description: projectDTO.Description,
+		Archived:    projectDTO.Archived,
+	}
+	err = h.Service.ProjectUpdate(c.Context(), project, login)
+	if errors.Is(err, entities.ErrInvalidProject) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RemoveHandler removes the project, its tasks are kept without a project.
+func (h *ProjectsHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	projectId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.ProjectRemove(c.Context(), projectId, login)
+	if errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// TasksHandler lists the project tasks with the same filters and pagination as the task list.
+func (h *ProjectsHandler) TasksHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	projectId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Parse filter and page request from query string
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	tasks, err := h.Service.ProjectTasks(c.Context(), projectId, login, filter, page)
+	if errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrInvalidSort) || errors.Is(err, entities.ErrInvalidCursor) {
+		return fiber.ErrBadRequest
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err := setNextLink(c, page, tasks.NextCursor); err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if tasks.Tasks == nil {
+		tasks.Tasks = []entities.Task{}
+	}
+
+	return c.JSON(TaskPageJSON{
+		Items:      tasks.Tasks,
+		NextCursor: tasks.NextCursor,
+	})
+}
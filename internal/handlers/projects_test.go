@@ -0,0 +1,235 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedProjectServices struct {
+	mock.Mock
+}
+
+func (m *MockedProjectServices) Projects(ctx context.Context, login string, archived bool) ([]entities.Project, error) {
+	args := m.Called(ctx, login, archived)
+	return args.Get(0).([]entities.Project), args.Error(1)
+}
+
+func (m *MockedProjectServices) Project(ctx context.Context, id uint64, login string) (entities.Project, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.Project), args.Error(1)
+}
+
+func (m *MockedProjectServices) ProjectAdd(ctx context.Context, project entities.Project, login string) (uint64, error) {
+	args := m.Called(ctx, project, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedProjectServices) ProjectUpdate(ctx context.Context, project entities.Project, login string) error {
+	args := m.Called(ctx, project, login)
+	return args.Error(0)
+}
+
+func (m *MockedProjectServices) ProjectRemove(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedProjectServices) ProjectTasks(ctx context.Context, id uint64, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error) {
+	args := m.Called(ctx, id, login, filter, page)
+	return args.Get(0).(entities.TaskPage), args.Error(1)
+}
+
+func newProjectsApp(s *MockedProjectServices, login string) *fiber.App {
+	h := &handlers.ProjectsHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/projects", h.ListHandler)
+	app.Get("/projects/:id", h.ItemHandler)
+	app.Post("/projects", h.AddHandler)
+	app.Put("/projects/:id", h.UpdateHandler)
+	app.Delete("/projects/:id", h.RemoveHandler)
+	app.Get("/projects/:id/tasks", h.TasksHandler)
+
+	return app
+}
+
+func TestProjectListHandler(t *testing.T) {
+	t.Run("archived projects on request", func(t *testing.T) {
+		projects := []entities.Project{{ID: 1, Key: "WEB", Name: "Website", Owner: "user", Archived: true}}
+
+		s := new(MockedProjectServices)
+		s.On("Projects", mock.Anything, "user", true).Return(projects, nil)
+		app := newProjectsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/projects?archived=true", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.Project
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, projects, encoded)
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedProjectServices)
+		app := newProjectsApp(s, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/projects", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "Projects", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProjectAddHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		project := entities.Project{Key: "WEB", Name: "Website"}
+
+		s := new(MockedProjectServices)
+		s.On("ProjectAdd", mock.Anything, project, "user").Return(uint64(1), nil)
+		app := newProjectsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader([]byte(`{"key":"WEB","name":"Website"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("errors mapping", func(t *testing.T) {
+		for _, tc := range []struct {
+			err    error
+			status int
+		}{
+			{entities.ErrInvalidProject, http.StatusBadRequest},
+			{entities.ErrProjectExists, http.StatusConflict},
+			{fmt.Errorf("error"), http.StatusInternalServerError},
+		} {
+			s := new(MockedProjectServices)
+			s.On("ProjectAdd", mock.Anything, mock.Anything, "user").Return(uint64(0), tc.err)
+			app := newProjectsApp(s, "user")
+
+			req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader([]byte(`{"key":"WEB","name":"Website"}`)))
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		}
+	})
+}
+
+func TestProjectUpdateHandler(t *testing.T) {
+	t.Run("key is ignored", func(t *testing.T) {
+		project := entities.Project{ID: 1, Name: "Website", Archived: true}
+
+		s := new(MockedProjectServices)
+		s.On("ProjectUpdate", mock.Anything, project, "user").Return(nil)
+		app := newProjectsApp(s, "user")
+
+		body := `{"key":"NEW","name":"Website","archived":true}`
+		req := httptest.NewRequest(http.MethodPut, "/projects/1", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		s := new(MockedProjectServices)
+		s.On("ProjectUpdate", mock.Anything, mock.Anything, "user").Return(entities.ErrNoProject)
+		app := newProjectsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/projects/1", bytes.NewReader([]byte(`{"name":"Website"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestProjectRemoveHandler(t *testing.T) {
+	s := new(MockedProjectServices)
+	s.On("ProjectRemove", mock.Anything, uint64(1), "user").Return(nil)
+	app := newProjectsApp(s, "user")
+
+	req := httptest.NewRequest(http.MethodDelete, "/projects/1", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	s.AssertExpectations(t)
+}
+
+func TestProjectTasksHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		projectID := uint64(1)
+		page := entities.TaskPage{Tasks: []entities.Task{{ID: 5, Name: "task", ProjectID: &projectID, Key: "WEB-1"}}}
+		filter := entities.TaskFilter{Statuses: []entities.TaskStatus{entities.StatusTodo}}
+
+		s := new(MockedProjectServices)
+		s.On("ProjectTasks", mock.Anything, projectID, "user", filter, entities.PageRequest{Limit: entities.DefaultPageLimit}).Return(page, nil)
+		app := newProjectsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/projects/1/tasks?status=todo", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded handlers.TaskPageJSON
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, page.Tasks, encoded.Items)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		s := new(MockedProjectServices)
+		s.On("ProjectTasks", mock.Anything, uint64(1), "user", mock.Anything, mock.Anything).Return(entities.TaskPage{}, entities.ErrNoProject)
+		app := newProjectsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/projects/1/tasks", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		s := new(MockedProjectServices)
+		app := newProjectsApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/projects/1/tasks?status=unknown", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "ProjectTasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+const (
+	defaultOccurrencesCount = 5
+	maxOccurrencesCount     = 100
+)
+
+type SeriesService interface {
+	TaskOccurrences(ctx context.Context, id uint64, login string, count int) ([]time.Time, error)
+	TaskSeriesUpdate(ctx context.Context, id uint64, update entities.SeriesUpdate, login string) (uint64, error)
+}
+
+type SeriesHandler struct {
+	Service SeriesService
+}
+
+type SeriesUpdateJSON struct {
+	Recurrence  string `json:"recurrence"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Priority    string `json:"priority"`
+}
+
+type SeriesJSON struct {
+	SeriesID uint64 `json:"series_id"`
+}
+
+// OccurrencesHandler previews upcoming occurrences of a recurring task.
+func (h *SeriesHandler) OccurrencesHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	count := defaultOccurrencesCount
+	if v := c.Query("count"); v != "" {
+		count, err = strconv.Atoi(v)
+		if err != nil || count < 1 || count > maxOccurrencesCount {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	occurrences, err := h.Service.TaskOccurrences(c.Context(), taskId, login, count)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoSeries) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	dates := make([]string, len(occurrences))
+	for i, t := range occurrences {
+		dates[i] = t.Format(time.RFC3339)
+	}
+
+	return c.JSON(dates)
+}
+
+// UpdateHandler edits the occurrence and all the following ones.
+func (h *SeriesHandler) UpdateHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var updateDTO SeriesUpdateJSON
+	err = json.Unmarshal(c.Body(), &updateDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	update := entities.SeriesUpdate{
+		Rule:        updateDTO.Recurrence,
+		Name:        updateDTO.Name,
+		Description: updateDTO.Description,
+		Priority:    entities.TaskPriority(updateDTO.Priority),
+	}
+
+	seriesId, err := h.Service.TaskSeriesUpdate(c.Context(), taskId, update, login)
+	if errors.Is(err, entities.ErrInvalidRecurrence) || errors.Is(err, entities.ErrUnknownPriority) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoSeries) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(SeriesJSON{SeriesID: seriesId})
+}
@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedSeriesServices struct {
+	mock.Mock
+}
+
+func (m *MockedSeriesServices) TaskOccurrences(ctx context.Context, id uint64, login string, count int) ([]time.Time, error) {
+	args := m.Called(ctx, id, login, count)
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockedSeriesServices) TaskSeriesUpdate(ctx context.Context, id uint64, update entities.SeriesUpdate, login string) (uint64, error) {
+	args := m.Called(ctx, id, update, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func newSeriesApp(s *MockedSeriesServices, login string) *fiber.App {
+	h := &handlers.SeriesHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tasks/:id/occurrences", h.OccurrencesHandler)
+	app.Put("/tasks/:id/series", h.UpdateHandler)
+
+	return app
+}
+
+func TestOccurrencesHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		dueAt := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC)
+		s := new(MockedSeriesServices)
+		s.On("TaskOccurrences", mock.Anything, uint64(1), "user", 2).Return([]time.Time{dueAt}, nil)
+		app := newSeriesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/occurrences?count=2", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var dates []string
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&dates))
+		assert.Equal(t, []string{"2025-05-05T10:00:00Z"}, dates)
+	})
+
+	t.Run("invalid count", func(t *testing.T) {
+		s := new(MockedSeriesServices)
+		app := newSeriesApp(s, "user")
+
+		for _, count := range []string{"0", "101", "abc"} {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/1/occurrences?count="+count, nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		}
+		s.AssertNotCalled(t, "TaskOccurrences", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("task is not recurring", func(t *testing.T) {
+		s := new(MockedSeriesServices)
+		s.On("TaskOccurrences", mock.Anything, uint64(1), "user", 5).Return([]time.Time(nil), entities.ErrNoSeries)
+		app := newSeriesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/occurrences", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestSeriesUpdateHandler(t *testing.T) {
+	t.Run("success request", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.SeriesUpdateJSON{Recurrence: "FREQ=WEEKLY", Name: "renamed"})
+		s := new(MockedSeriesServices)
+		s.On("TaskSeriesUpdate", mock.Anything, uint64(1), entities.SeriesUpdate{Rule: "FREQ=WEEKLY", Name: "renamed"}, "user").Return(uint64(8), nil)
+		app := newSeriesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/series", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res handlers.SeriesJSON
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		assert.Equal(t, uint64(8), res.SeriesID)
+	})
+
+	t.Run("invalid recurrence", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.SeriesUpdateJSON{Recurrence: "FREQ=NEVER"})
+		s := new(MockedSeriesServices)
+		s.On("TaskSeriesUpdate", mock.Anything, uint64(1), entities.SeriesUpdate{Rule: "FREQ=NEVER"}, "user").Return(uint64(0), entities.ErrInvalidRecurrence)
+		app := newSeriesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/series", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("viewer can not update the series", func(t *testing.T) {
+		body, _ := json.Marshal(handlers.SeriesUpdateJSON{Name: "renamed"})
+		s := new(MockedSeriesServices)
+		s.On("TaskSeriesUpdate", mock.Anything, uint64(1), entities.SeriesUpdate{Name: "renamed"}, "user").Return(uint64(0), entities.ErrForbidden)
+		app := newSeriesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/series", bytes.NewReader(body))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedSeriesServices)
+		app := newSeriesApp(s, "")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/series", bytes.NewReader([]byte(`{}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "TaskSeriesUpdate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type ShareService interface {
+	Shares(ctx context.Context, target entities.Share, login string) ([]entities.Share, error)
+	ShareSet(ctx context.Context, share entities.Share, login string) error
+	ShareRemove(ctx context.Context, share entities.Share, login string) error
+}
+
+// SharesHandler manages shares of tasks and projects, Project switches it to project shares.
+type SharesHandler struct {
+	Service ShareService
+	Project bool
+}
+
+type ShareJSON struct {
+	Role string `json:"role"`
+}
+
+// target builds share of the task or the project from the URL.
+func (h *SharesHandler) target(c *fiber.Ctx) (entities.Share, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return entities.Share{}, err
+	}
+
+	share := entities.Share{Login: c.Params("login")}
+	if h.Project {
+		share.ProjectID = &id
+	} else {
+		share.TaskID = &id
+	}
+	return share, nil
+}
+
+func (h *SharesHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	target, err := h.target(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	shares, err := h.Service.Shares(c.Context(), target, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if shares == nil {
+		shares = []entities.Share{}
+	}
+
+	return c.JSON(shares)
+}
+
+// SetHandler grants the login from the URL access with the role from the body.
+func (h *SharesHandler) SetHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	share, err := h.target(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var shareDTO ShareJSON
+	err = json.Unmarshal(c.Body(), &shareDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	share.Role = entities.Access(shareDTO.Role)
+
+	err = h.Service.ShareSet(c.Context(), share, login)
+	if errors.Is(err, entities.ErrInvalidShare) || errors.Is(err, entities.ErrNoUser) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoProject) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) || errors.Is(err, entities.ErrNoOrg) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *SharesHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	share, err := h.target(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.ShareRemove(c.Context(), share, login)
+	if errors.Is(err, entities.ErrNoTask) || errors.Is(err, entities.ErrNoProject) || errors.Is(err, entities.ErrNoShare) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedShareServices struct {
+	mock.Mock
+}
+
+func (m *MockedShareServices) Shares(ctx context.Context, target entities.Share, login string) ([]entities.Share, error) {
+	args := m.Called(ctx, target, login)
+	return args.Get(0).([]entities.Share), args.Error(1)
+}
+
+func (m *MockedShareServices) ShareSet(ctx context.Context, share entities.Share, login string) error {
+	args := m.Called(ctx, share, login)
+	return args.Error(0)
+}
+
+func (m *MockedShareServices) ShareRemove(ctx context.Context, share entities.Share, login string) error {
+	args := m.Called(ctx, share, login)
+	return args.Error(0)
+}
+
+func newSharesApp(s *MockedShareServices, login string) *fiber.App {
+	tasks := &handlers.SharesHandler{Service: s}
+	projects := &handlers.SharesHandler{Service: s, Project: true}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tasks/:id/shares", tasks.ListHandler)
+	app.Put("/tasks/:id/shares/:login", tasks.SetHandler)
+	app.Delete("/tasks/:id/shares/:login", tasks.RemoveHandler)
+	app.Get("/projects/:id/shares", projects.ListHandler)
+	app.Put("/projects/:id/shares/:login", projects.SetHandler)
+
+	return app
+}
+
+func TestShareListHandler(t *testing.T) {
+	taskID := uint64(1)
+	shares := []entities.Share{{TaskID: &taskID, Login: "friend", Role: entities.AccessViewer}}
+
+	s := new(MockedShareServices)
+	s.On("Shares", mock.Anything, entities.Share{TaskID: &taskID}, "user").Return(shares, nil)
+	app := newSharesApp(s, "user")
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1/shares", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var encoded []entities.Share
+	err = json.NewDecoder(resp.Body).Decode(&encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, shares, encoded)
+}
+
+func TestShareSetHandler(t *testing.T) {
+	t.Run("task share", func(t *testing.T) {
+		taskID := uint64(1)
+		share := entities.Share{TaskID: &taskID, Login: "friend", Role: entities.AccessEditor}
+
+		s := new(MockedShareServices)
+		s.On("ShareSet", mock.Anything, share, "user").Return(nil)
+		app := newSharesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/shares/friend", bytes.NewReader([]byte(`{"role":"editor"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("project share", func(t *testing.T) {
+		projectID := uint64(2)
+		share := entities.Share{ProjectID: &projectID, Login: "friend", Role: entities.AccessViewer}
+
+		s := new(MockedShareServices)
+		s.On("ShareSet", mock.Anything, share, "user").Return(nil)
+		app := newSharesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/projects/2/shares/friend", bytes.NewReader([]byte(`{"role":"viewer"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		s := new(MockedShareServices)
+		s.On("ShareSet", mock.Anything, mock.Anything, "user").Return(entities.ErrForbidden)
+		app := newSharesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/shares/friend", bytes.NewReader([]byte(`{"role":"viewer"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		s := new(MockedShareServices)
+		s.On("ShareSet", mock.Anything, mock.Anything, "user").Return(entities.ErrNoUser)
+		app := newSharesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/shares/nobody", bytes.NewReader([]byte(`{"role":"viewer"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		s := new(MockedShareServices)
+		s.On("ShareSet", mock.Anything, mock.Anything, "user").Return(entities.ErrInvalidShare)
+		app := newSharesApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/shares/friend", bytes.NewReader([]byte(`{"role":"owner"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestShareRemoveHandler(t *testing.T) {
+	taskID := uint64(1)
+
+	s := new(MockedShareServices)
+	s.On("ShareRemove", mock.Anything, entities.Share{TaskID: &taskID, Login: "friend"}, "user").Return(entities.ErrNoShare)
+	app := newSharesApp(s, "user")
+
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/1/shares/friend", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
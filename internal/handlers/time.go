@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type TimeService interface {
+	TimerStart(ctx context.Context, taskID uint64, login string) (entities.TimeEntry, error)
+	TimerStop(ctx context.Context, taskID uint64, login string) (entities.TimeEntry, error)
+	TimeEntries(ctx context.Context, taskID uint64, login string) ([]entities.TimeEntry, error)
+	TimeEntryAdd(ctx context.Context, entry entities.TimeEntry, login string) (uint64, error)
+	TimeEntryUpdate(ctx context.Context, entry entities.TimeEntry, login string) error
+	TimeEntryRemove(ctx context.Context, taskID uint64, id uint64, login string) error
+	TimeReport(ctx context.Context, login string, from time.Time, to time.Time) (entities.TimeReport, error)
+}
+
+type TimeHandler struct {
+	Service TimeService
+}
+
+type TimeEntryJSON struct {
+	StartedAt string `json:"started_at"`
+	StoppedAt string `json:"stopped_at"`
+	Note      string `json:"note"`
+}
+
+// Convert DTO to time entry entity, times are expected in RFC 3339 format
+func (e TimeEntryJSON) entity(taskID uint64, id uint64) (entities.TimeEntry, error) {
+	entry := entities.TimeEntry{ID: id, TaskID: taskID, Note: e.Note}
+
+	startedAt, err := parseTime(e.StartedAt)
+	if err != nil {
+		return entry, err
+	}
+	if startedAt != nil {
+		entry.StartedAt = *startedAt
+	}
+	if entry.StoppedAt, err = parseTime(e.StoppedAt); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+func (h *TimeHandler) StartHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	entry, err := h.Service.TimerStart(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if errors.Is(err, entities.ErrTimerRunning) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(entry)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *TimeHandler) StopHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	entry, err := h.Service.TimerStop(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTimer) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(entry)
+}
+
+func (h *TimeHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	entries, err := h.Service.TimeEntries(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if entries == nil {
+		entries = []entities.TimeEntry{}
+	}
+
+	return c.JSON(entries)
+}
+
+func (h *TimeHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var entryDTO TimeEntryJSON
+	err = json.Unmarshal(c.Body(), &entryDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	entry, err := entryDTO.entity(taskId, 0)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	id, err := h.Service.TimeEntryAdd(c.Context(), entry, login)
+	if errors.Is(err, entities.ErrInvalidTimeEntry) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrForbidden) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(id)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *TimeHandler) UpdateHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, entryId, err := taskTimeEntryParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	//Read body and parse JSON to DTO
+	var entryDTO TimeEntryJSON
+	err = json.Unmarshal(c.Body(), &entryDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	entry, err := entryDTO.entity(taskId, entryId)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TimeEntryUpdate(c.Context(), entry, login)
+	if errors.Is(err, entities.ErrInvalidTimeEntry) {
+		return fiber.ErrBadRequest
+	}
+	if errors.Is(err, entities.ErrNoTimeEntry) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *TimeHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, entryId, err := taskTimeEntryParams(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TimeEntryRemove(c.Context(), taskId, entryId, login)
+	if errors.Is(err, entities.ErrNoTimeEntry) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ReportHandler aggregates hours logged within [from, to), bounds are required and expected in RFC 3339 format.
+func (h *TimeHandler) ReportHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	report, err := h.Service.TimeReport(c.Context(), login, from, to)
+	if errors.Is(err, entities.ErrInvalidPeriod) {
+		return fiber.ErrBadRequest
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if report.Tasks == nil {
+		report.Tasks = []entities.TimeReportRow{}
+	}
+	if report.Labels == nil {
+		report.Labels = []entities.TimeReportRow{}
+	}
+
+	return c.JSON(report)
+}
+
+// parsePeriod reads required "from" and "to" query parameters in RFC 3339 format.
+func parsePeriod(c *fiber.Ctx) (time.Time, time.Time, error) {
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+func taskTimeEntryParams(c *fiber.Ctx) (uint64, uint64, error) {
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	entryId, err := strconv.ParseUint(c.Params("entryId"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return taskId, entryId, nil
+}
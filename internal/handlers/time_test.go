@@ -0,0 +1,259 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedTimeServices struct {
+	mock.Mock
+}
+
+func (m *MockedTimeServices) TimerStart(ctx context.Context, taskID uint64, login string) (entities.TimeEntry, error) {
+	args := m.Called(ctx, taskID, login)
+	return args.Get(0).(entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockedTimeServices) TimerStop(ctx context.Context, taskID uint64, login string) (entities.TimeEntry, error) {
+	args := m.Called(ctx, taskID, login)
+	return args.Get(0).(entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockedTimeServices) TimeEntries(ctx context.Context, taskID uint64, login string) ([]entities.TimeEntry, error) {
+	args := m.Called(ctx, taskID, login)
+	return args.Get(0).([]entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockedTimeServices) TimeEntryAdd(ctx context.Context, entry entities.TimeEntry, login string) (uint64, error) {
+	args := m.Called(ctx, entry, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedTimeServices) TimeEntryUpdate(ctx context.Context, entry entities.TimeEntry, login string) error {
+	args := m.Called(ctx, entry, login)
+	return args.Error(0)
+}
+
+func (m *MockedTimeServices) TimeEntryRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedTimeServices) TimeReport(ctx context.Context, login string, from time.Time, to time.Time) (entities.TimeReport, error) {
+	args := m.Called(ctx, login, from, to)
+	return args.Get(0).(entities.TimeReport), args.Error(1)
+}
+
+func newTimeApp(s *MockedTimeServices, login string) *fiber.App {
+	h := &handlers.TimeHandler{
+		Service: s,
+	}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Post("/tasks/:id/timer/start", h.StartHandler)
+	app.Post("/tasks/:id/timer/stop", h.StopHandler)
+	app.Get("/tasks/:id/time-entries", h.ListHandler)
+	app.Post("/tasks/:id/time-entries", h.AddHandler)
+	app.Put("/tasks/:id/time-entries/:entryId", h.UpdateHandler)
+	app.Delete("/tasks/:id/time-entries/:entryId", h.RemoveHandler)
+	app.Get("/reports/time", h.ReportHandler)
+
+	return app
+}
+
+func TestTimerHandlers(t *testing.T) {
+	t.Run("success starting", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		s.On("TimerStart", mock.Anything, uint64(1), "user").Return(entities.TimeEntry{ID: 2, TaskID: 1}, nil)
+		app := newTimeApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/timer/start", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("errors mapping", func(t *testing.T) {
+		for _, tc := range []struct {
+			method string
+			err    error
+			status int
+		}{
+			{"TimerStart", entities.ErrTimerRunning, http.StatusConflict},
+			{"TimerStart", entities.ErrNoTask, http.StatusNotFound},
+			{"TimerStart", entities.ErrForbidden, http.StatusForbidden},
+			{"TimerStop", entities.ErrNoTimer, http.StatusConflict},
+		} {
+			s := new(MockedTimeServices)
+			s.On(tc.method, mock.Anything, uint64(1), "user").Return(entities.TimeEntry{}, tc.err)
+			app := newTimeApp(s, "user")
+
+			path := "/tasks/1/timer/start"
+			if tc.method == "TimerStop" {
+				path = "/tasks/1/timer/stop"
+			}
+			req := httptest.NewRequest(http.MethodPost, path, nil)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		}
+	})
+
+	t.Run("unauthorized error", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		app := newTimeApp(s, "")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/timer/stop", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		s.AssertNotCalled(t, "TimerStop", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTimeEntryHandlers(t *testing.T) {
+	startedAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	stoppedAt := startedAt.Add(time.Hour)
+
+	t.Run("empty list", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		s.On("TimeEntries", mock.Anything, uint64(1), "user").Return([]entities.TimeEntry(nil), nil)
+		app := newTimeApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1/time-entries", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.TimeEntry
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Empty(t, encoded)
+	})
+
+	t.Run("success adding", func(t *testing.T) {
+		entry := entities.TimeEntry{TaskID: 1, StartedAt: startedAt, StoppedAt: &stoppedAt, Note: "call"}
+
+		s := new(MockedTimeServices)
+		s.On("TimeEntryAdd", mock.Anything, entry, "user").Return(uint64(3), nil)
+		app := newTimeApp(s, "user")
+
+		body := `{"started_at":"2025-01-01T09:00:00Z","stopped_at":"2025-01-01T10:00:00Z","note":"call"}`
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/time-entries", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("adding with invalid time", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		app := newTimeApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/1/time-entries", bytes.NewReader([]byte(`{"started_at":"yesterday"}`)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "TimeEntryAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("updating unexisted entry", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		s.On("TimeEntryUpdate", mock.Anything, mock.Anything, "user").Return(entities.ErrNoTimeEntry)
+		app := newTimeApp(s, "user")
+
+		body := `{"started_at":"2025-01-01T09:00:00Z","stopped_at":"2025-01-01T10:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPut, "/tasks/1/time-entries/2", bytes.NewReader([]byte(body)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("success removing", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		s.On("TimeEntryRemove", mock.Anything, uint64(1), uint64(2), "user").Return(nil)
+		app := newTimeApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodDelete, "/tasks/1/time-entries/2", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		s.AssertExpectations(t)
+	})
+}
+
+func TestTimeReportHandler(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success request", func(t *testing.T) {
+		report := entities.TimeReport{From: from, To: to, Total: 2, Tasks: []entities.TimeReportRow{{ID: 1, Name: "task", Hours: 2}}}
+
+		s := new(MockedTimeServices)
+		s.On("TimeReport", mock.Anything, "user", from, to).Return(report, nil)
+		app := newTimeApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/time?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded entities.TimeReport
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, report.Tasks, encoded.Tasks)
+		assert.Equal(t, []entities.TimeReportRow{}, encoded.Labels)
+	})
+
+	t.Run("missing bounds", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		app := newTimeApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/time?from=2025-01-01T00:00:00Z", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		s.AssertNotCalled(t, "TimeReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		s := new(MockedTimeServices)
+		s.On("TimeReport", mock.Anything, "user", to, from).Return(entities.TimeReport{}, entities.ErrInvalidPeriod)
+		app := newTimeApp(s, "user")
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/time?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
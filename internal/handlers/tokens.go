@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type TokenService interface {
+	Tokens(ctx context.Context, login string) ([]entities.AccessToken, error)
+	TokenAdd(ctx context.Context, token entities.AccessToken, login string) (uint64, string, error)
+	TokenRevoke(ctx context.Context, id uint64, login string) error
+}
+
+type TokensHandler struct {
+	Service TokenService
+}
+
+type TokenJSON struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// IssuedTokenJSON carries the new token, it is returned only once.
+type IssuedTokenJSON struct {
+	ID    uint64 `json:"id"`
+	Token string `json:"token"`
+}
+
+// ListHandler returns access tokens of the user without the tokens themselves.
+func (h *TokensHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	tokens, err := h.Service.Tokens(c.Context(), login)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if tokens == nil {
+		tokens = []entities.AccessToken{}
+	}
+
+	return c.JSON(tokens)
+}
+
+func (h *TokensHandler) AddHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	//Read body and parse JSON to DTO
+	var tokenDTO TokenJSON
+	err := json.Unmarshal(c.Body(), &tokenDTO)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	token := entities.AccessToken{Name: tokenDTO.Name, ExpiresAt: tokenDTO.ExpiresAt}
+	for _, scope := range tokenDTO.Scopes {
+		token.Scopes = append(token.Scopes, entities.Scope(scope))
+	}
+	id, secret, err := h.Service.TokenAdd(c.Context(), token, login)
+	if errors.Is(err, entities.ErrInvalidToken) {
+		return fiber.ErrBadRequest
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	err = c.JSON(IssuedTokenJSON{ID: id, Token: secret})
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *TokensHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	tokenId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TokenRevoke(c.Context(), tokenId, login)
+	if errors.Is(err, entities.ErrNoToken) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
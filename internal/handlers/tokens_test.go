@@ -0,0 +1,142 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedTokenServices struct {
+	mock.Mock
+}
+
+func (m *MockedTokenServices) Tokens(ctx context.Context, login string) ([]entities.AccessToken, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.AccessToken), args.Error(1)
+}
+
+func (m *MockedTokenServices) TokenAdd(ctx context.Context, token entities.AccessToken, login string) (uint64, string, error) {
+	args := m.Called(ctx, token, login)
+	return args.Get(0).(uint64), args.String(1), args.Error(2)
+}
+
+func (m *MockedTokenServices) TokenRevoke(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func newTokensApp(s *MockedTokenServices, login string) *fiber.App {
+	h := &handlers.TokensHandler{Service: s}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/tokens", h.ListHandler)
+	app.Post("/tokens", h.AddHandler)
+	app.Delete("/tokens/:id", h.RemoveHandler)
+
+	return app
+}
+
+func TestTokenListHandler(t *testing.T) {
+	t.Run("tokens of the user", func(t *testing.T) {
+		created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		tokens := []entities.AccessToken{{ID: 1, Name: "ci", CreatedAt: created}}
+		s := new(MockedTokenServices)
+		s.On("Tokens", mock.Anything, "user").Return(tokens, nil)
+		app := newTokensApp(s, "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/tokens", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.AccessToken
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, tokens, encoded)
+	})
+
+	t.Run("unauthorized without login", func(t *testing.T) {
+		app := newTokensApp(new(MockedTokenServices), "")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/tokens", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestTokenAddHandler(t *testing.T) {
+	t.Run("token issued", func(t *testing.T) {
+		expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		s := new(MockedTokenServices)
+		token := entities.AccessToken{Name: "ci", Scopes: []entities.Scope{entities.ScopeTasksWrite}, ExpiresAt: &expiresAt}
+		s.On("TokenAdd", mock.Anything, token, "user").Return(uint64(2), "secret", nil)
+		app := newTokensApp(s, "user")
+
+		body, _ := json.Marshal(handlers.TokenJSON{Name: "ci", Scopes: []string{"tasks:write"}, ExpiresAt: &expiresAt})
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/tokens", bytes.NewReader(body)))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var issued handlers.IssuedTokenJSON
+		err = json.NewDecoder(resp.Body).Decode(&issued)
+		assert.NoError(t, err)
+		assert.Equal(t, handlers.IssuedTokenJSON{ID: 2, Token: "secret"}, issued)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		s := new(MockedTokenServices)
+		s.On("TokenAdd", mock.Anything, entities.AccessToken{}, "user").Return(uint64(0), "", entities.ErrInvalidToken)
+		app := newTokensApp(s, "user")
+
+		body, _ := json.Marshal(handlers.TokenJSON{})
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/tokens", bytes.NewReader(body)))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestTokenRemoveHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"token revoked", nil, http.StatusNoContent},
+		{"unknown token", entities.ErrNoToken, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockedTokenServices)
+			s.On("TokenRevoke", mock.Anything, uint64(1), "user").Return(tt.err)
+			app := newTokensApp(s, "user")
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/tokens/1", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	t.Run("bad token id", func(t *testing.T) {
+		app := newTokensApp(new(MockedTokenServices), "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/tokens/abc", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type TrashService interface {
+	Trash(ctx context.Context, login string) ([]entities.Task, error)
+	TaskRestore(ctx context.Context, id uint64, login string) error
+	TaskPurge(ctx context.Context, id uint64, login string) error
+}
+
+type TrashHandler struct {
+	Service TrashService
+}
+
+func (h *TrashHandler) ListHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	tasks, err := h.Service.Trash(c.Context(), login)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if tasks == nil {
+		tasks = []entities.Task{}
+	}
+
+	return c.JSON(tasks)
+}
+
+func (h *TrashHandler) RestoreHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TaskRestore(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if errors.Is(err, entities.ErrOccurrenceExists) || errors.Is(err, entities.ErrWIPLimit) {
+		return fiber.ErrConflict
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RemoveHandler deletes the trashed task permanently.
+func (h *TrashHandler) RemoveHandler(c *fiber.Ctx) error {
+
+	login, ok := c.Locals(entities.UserLoginKey).(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	taskId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	err = h.Service.TaskPurge(c.Context(), taskId, login)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/handlers"
+)
+
+type MockedTrashServices struct {
+	mock.Mock
+}
+
+func (m *MockedTrashServices) Trash(ctx context.Context, login string) ([]entities.Task, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedTrashServices) TaskRestore(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedTrashServices) TaskPurge(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func newTrashApp(s *MockedTrashServices, login string) *fiber.App {
+	h := &handlers.TrashHandler{Service: s}
+
+	app := fiber.New()
+	if login != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(entities.UserLoginKey, login)
+			return c.Next()
+		})
+	}
+	app.Get("/trash", h.ListHandler)
+	app.Post("/trash/:id/restore", h.RestoreHandler)
+	app.Delete("/trash/:id", h.RemoveHandler)
+
+	return app
+}
+
+func TestTrashListHandler(t *testing.T) {
+	t.Run("trashed tasks", func(t *testing.T) {
+		tasks := []entities.Task{{ID: 1, Name: "task", Owner: "user"}}
+		s := new(MockedTrashServices)
+		s.On("Trash", mock.Anything, "user").Return(tasks, nil)
+		app := newTrashApp(s, "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/trash", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var encoded []entities.Task
+		err = json.NewDecoder(resp.Body).Decode(&encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, tasks, encoded)
+	})
+
+	t.Run("unauthorized without login", func(t *testing.T) {
+		app := newTrashApp(new(MockedTrashServices), "")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/trash", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestTrashRestoreHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"task restored", nil, http.StatusNoContent},
+		{"task is not in trash", entities.ErrNoTask, http.StatusNotFound},
+		{"occurrence exists", entities.ErrOccurrenceExists, http.StatusConflict},
+		{"column is full", entities.ErrWIPLimit, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockedTrashServices)
+			s.On("TaskRestore", mock.Anything, uint64(1), "user").Return(tt.err)
+			app := newTrashApp(s, "user")
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/trash/1/restore", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestTrashRemoveHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"task removed", nil, http.StatusNoContent},
+		{"task is not in trash", entities.ErrNoTask, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockedTrashServices)
+			s.On("TaskPurge", mock.Anything, uint64(1), "user").Return(tt.err)
+			app := newTrashApp(s, "user")
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/trash/1", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	t.Run("bad task id", func(t *testing.T) {
+		app := newTrashApp(new(MockedTrashServices), "user")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/trash/abc", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,70 @@
+package jwtauth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-code-mentor/wp-task/internal/middleware/jwtauth"
+)
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	set := map[string]any{"keys": []map[string]any{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": b64(edPublic)},
+		{"kty": "oct", "kid": "hs", "k": b64(secret)},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": b64(rsaKey.N.Bytes()), "e": "AQAB"},
+		{"kty": "EC", "kid": "ec", "crv": "P-256"},
+	}}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	keys, err := jwtauth.LoadJWKS(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []jwtauth.Key{
+		{ID: "rsa", Key: &rsaKey.PublicKey},
+		{ID: "ed", Key: edPublic},
+		{ID: "hs", Key: secret},
+	}, keys)
+
+	_, err = jwtauth.ParseJWKS([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AQID"}]}`))
+	assert.Error(t, err)
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(edPublic)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	key, err := jwtauth.LoadPublicKey(path)
+	assert.NoError(t, err)
+	assert.Equal(t, jwtauth.Key{Key: edPublic}, key)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+	_, err = jwtauth.LoadPublicKey(path)
+	assert.Error(t, err)
+}
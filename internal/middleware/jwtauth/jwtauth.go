@@ -0,0 +1,243 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+const (
+	BearerPrefix = "Bearer "
+
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	// DefaultLoginClaim is the claim the login is taken from when none is configured
+	DefaultLoginClaim = "sub"
+	// ScopeClaim holds space separated scopes of the token
+	ScopeClaim = "scope"
+	// OrgClaim holds the numeric id of the active organization of the token
+	OrgClaim = "org_id"
+)
+
+// Key verifies token signatures, its algorithm follows the key type: HS256 for a []byte secret,
+// RS256 for *rsa.PublicKey and EdDSA for ed25519.PublicKey. Tokens signed with another algorithm
+// are never verified with the key. Key with an ID verifies only tokens with the same key id,
+// key without it verifies any token.
+type Key struct {
+	ID  string
+	Key any
+}
+
+func (k Key) algorithm() string {
+	switch k.Key.(type) {
+	case []byte:
+		return AlgHS256
+	case *rsa.PublicKey:
+		return AlgRS256
+	case ed25519.PublicKey:
+		return AlgEdDSA
+	}
+	return ""
+}
+
+// Verifier authenticates requests with signed JWT bearer tokens without a storage lookup.
+type Verifier struct {
+	Keys       []Key
+	Audience   string
+	LoginClaim string
+	// Leeway is the allowed clock difference with the token issuer
+	Leeway time.Duration
+
+	now func() time.Time
+}
+
+// New creates the verifier, empty login claim selects the subject.
+func New(keys []Key, audience string, loginClaim string) (*Verifier, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwt verifier needs at least one key")
+	}
+	for _, key := range keys {
+		if key.algorithm() == "" {
+			return nil, fmt.Errorf("unsupported jwt key type %T", key.Key)
+		}
+	}
+	if audience == "" {
+		return nil, fmt.Errorf("jwt verifier needs an audience")
+	}
+	if loginClaim == "" {
+		loginClaim = DefaultLoginClaim
+	}
+
+	return &Verifier{
+		Keys:       keys,
+		Audience:   audience,
+		LoginClaim: loginClaim,
+		Leeway:     time.Minute,
+		now:        time.Now,
+	}, nil
+}
+
+// Accepts reports whether the authorization header carries a bearer token.
+func (v *Verifier) Accepts(header string) bool {
+	return strings.HasPrefix(header, BearerPrefix)
+}
+
+// Authenticate verifies the bearer token and returns the login and the scopes from its claims.
+func (v *Verifier) Authenticate(ctx context.Context, header string) (string, []entities.Scope, error) {
+	login, scopes, _, err := v.AuthenticateOrg(ctx, header)
+	return login, scopes, err
+}
+
+// AuthenticateOrg verifies the bearer token and returns the login, the scopes and the active organization
+// from its claims. Organization is zero when the token has no organization claim.
+func (v *Verifier) AuthenticateOrg(_ context.Context, header string) (string, []entities.Scope, uint64, error) {
+	claims, err := v.Claims(strings.TrimPrefix(header, BearerPrefix))
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	login, _ := claims[v.LoginClaim].(string)
+	if login == "" {
+		return "", nil, 0, fmt.Errorf("unable to verify jwt: %w: no %q claim", entities.ErrInvalidToken, v.LoginClaim)
+	}
+
+	var orgID uint64
+	if claim, ok := claims[OrgClaim]; ok {
+		id, ok := claim.(float64)
+		if !ok || id < 1 || id != math.Trunc(id) {
+			return "", nil, 0, fmt.Errorf("unable to verify jwt: %w: malformed %q claim", entities.ErrInvalidToken, OrgClaim)
+		}
+		orgID = uint64(id)
+	}
+
+	var scopes []entities.Scope
+	scope, _ := claims[ScopeClaim].(string)
+	for _, s := range strings.Fields(scope) {
+		if entities.Scope(s).Valid() {
+			scopes = append(scopes, entities.Scope(s))
+		}
+	}
+
+	return login, scopes, orgID, nil
+}
+
+// Claims verifies the token and returns all of its claims.
+func (v *Verifier) Claims(token string) (map[string]any, error) {
+	claims, err := v.verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify jwt: %w: %w", entities.ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// verify checks the signature and the time and audience claims of the token and returns its claims.
+func (v *Verifier) verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var head header
+	if err := decodeSegment(parts[0], &head); err != nil {
+		return nil, fmt.Errorf("malformed header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %w", err)
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	if !v.verifySignature(head, signed, signature) {
+		return nil, fmt.Errorf("signature mismatch")
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed claims: %w", err)
+	}
+
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("no expiry time")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(v.Leeway)) {
+		return nil, fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, fmt.Errorf("token not valid yet")
+	}
+	if !hasAudience(claims["aud"], v.Audience) {
+		return nil, fmt.Errorf("audience mismatch")
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) verifySignature(head header, signed []byte, signature []byte) bool {
+	for _, key := range v.Keys {
+		if (key.ID != "" && key.ID != head.Kid) || key.algorithm() != head.Alg {
+			continue
+		}
+
+		switch k := key.Key.(type) {
+		case []byte:
+			mac := hmac.New(sha256.New, k)
+			mac.Write(signed)
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			digest := sha256.Sum256(signed)
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, signed, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasAudience reports whether the aud claim, a string or a list of strings, includes the audience.
+func hasAudience(claim any, audience string) bool {
+	switch aud := claim.(type) {
+	case string:
+		return aud == audience
+	case []any:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,173 @@
+package jwtauth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/middleware/jwtauth"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+// sign builds a token with the header and claims signed by the private key or the secret.
+func sign(t *testing.T, head map[string]any, claims map[string]any, key any) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(head) + "." + encode(claims)
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signed))
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func claims(extra map[string]any) map[string]any {
+	c := map[string]any{
+		"sub":   "user",
+		"aud":   "wp-task",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "read tasks:write unknown",
+	}
+	for k, v := range extra {
+		c[k] = v
+	}
+	return c
+}
+
+func TestAuthenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	verifier, err := jwtauth.New([]jwtauth.Key{
+		{Key: secret},
+		{ID: "rsa", Key: &rsaKey.PublicKey},
+		{ID: "ed", Key: edPublic},
+	}, "wp-task", "")
+	require.NoError(t, err)
+
+	valid := []struct {
+		name  string
+		token string
+	}{
+		{"HS256", sign(t, map[string]any{"alg": "HS256"}, claims(nil), secret)},
+		{"RS256", sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(nil), rsaKey)},
+		{"EdDSA", sign(t, map[string]any{"alg": "EdDSA", "kid": "ed"}, claims(nil), edPrivate)},
+		{"audience list", sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"aud": []string{"other", "wp-task"}}), secret)},
+		{"not before in the past", sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"nbf": time.Now().Add(-time.Hour).Unix()}), secret)},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			header := jwtauth.BearerPrefix + tt.token
+			assert.True(t, verifier.Accepts(header))
+
+			login, scopes, err := verifier.Authenticate(context.Background(), header)
+			assert.NoError(t, err)
+			assert.Equal(t, "user", login)
+			assert.Equal(t, []entities.Scope{entities.ScopeRead, entities.ScopeTasksWrite}, scopes)
+		})
+	}
+
+	otherSecret := []byte("fedcba9876543210fedcba9876543210")
+	rsaPublic, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	invalid := []struct {
+		name  string
+		token string
+	}{
+		{"wrong secret", sign(t, map[string]any{"alg": "HS256"}, claims(nil), otherSecret)},
+		{"unknown key id", sign(t, map[string]any{"alg": "RS256", "kid": "other"}, claims(nil), rsaKey)},
+		{"public key as secret", sign(t, map[string]any{"alg": "HS256", "kid": "rsa"}, claims(nil), rsaPublic)},
+		{"none algorithm", sign(t, map[string]any{"alg": "none"}, claims(nil), []byte{})},
+		{"expired", sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}), secret)},
+		{"no expiry", sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"exp": nil}), secret)},
+		{"not before in the future", sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"nbf": time.Now().Add(time.Hour).Unix()}), secret)},
+		{"other audience", sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"aud": "other"}), secret)},
+		{"no login", sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"sub": ""}), secret)},
+		{"malformed", "abc.def"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := verifier.Authenticate(context.Background(), jwtauth.BearerPrefix+tt.token)
+			assert.ErrorIs(t, err, entities.ErrInvalidToken)
+		})
+	}
+
+	t.Run("simple tokens are not accepted", func(t *testing.T) {
+		assert.False(t, verifier.Accepts("wpt_AbCd-_12_secret"))
+	})
+}
+
+func TestOrgClaim(t *testing.T) {
+	verifier, err := jwtauth.New([]jwtauth.Key{{Key: secret}}, "wp-task", "")
+	require.NoError(t, err)
+
+	token := sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"org_id": 7}), secret)
+	login, _, orgID, err := verifier.AuthenticateOrg(context.Background(), jwtauth.BearerPrefix+token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", login)
+	assert.Equal(t, uint64(7), orgID)
+
+	// Token without the claim names no organization
+	token = sign(t, map[string]any{"alg": "HS256"}, claims(nil), secret)
+	_, _, orgID, err = verifier.AuthenticateOrg(context.Background(), jwtauth.BearerPrefix+token)
+	assert.NoError(t, err)
+	assert.Zero(t, orgID)
+
+	for _, claim := range []any{"7", 0, -1, 1.5} {
+		token := sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"org_id": claim}), secret)
+		_, _, _, err := verifier.AuthenticateOrg(context.Background(), jwtauth.BearerPrefix+token)
+		assert.ErrorIs(t, err, entities.ErrInvalidToken, claim)
+	}
+}
+
+func TestLoginClaim(t *testing.T) {
+	verifier, err := jwtauth.New([]jwtauth.Key{{Key: secret}}, "wp-task", "preferred_username")
+	require.NoError(t, err)
+
+	token := sign(t, map[string]any{"alg": "HS256"}, claims(map[string]any{"preferred_username": "alice"}), secret)
+	login, _, err := verifier.Authenticate(context.Background(), jwtauth.BearerPrefix+token)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", login)
+}
+
+func TestNew(t *testing.T) {
+	_, err := jwtauth.New(nil, "wp-task", "")
+	assert.Error(t, err)
+	_, err = jwtauth.New([]jwtauth.Key{{Key: secret}}, "", "")
+	assert.Error(t, err)
+	_, err = jwtauth.New([]jwtauth.Key{{Key: "secret"}}, "wp-task", "")
+	assert.Error(t, err)
+}
@@ -2,6 +2,8 @@ package simpletoken
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -10,14 +12,33 @@ import (
 
 const (
 	AuthHeader = "Authorization"
+	OrgHeader  = "X-Org-ID"
 )
 
 type AuthService interface {
-	GetUserLogin(ctx context.Context, token string) (string, error)
+	GetUserLogin(ctx context.Context, token string) (string, []entities.Scope, error)
+	UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error)
 }
 
+// Authenticator resolves authorization headers of one kind, bearer tokens for instance,
+// to the login and the scopes of the request.
+type Authenticator interface {
+	Accepts(header string) bool
+	Authenticate(ctx context.Context, header string) (string, []entities.Scope, error)
+}
+
+// OrgAuthenticator is an authenticator whose credentials may name the active organization themselves,
+// zero organization means the credentials name none. Requests with the organization in the credentials
+// skip the organization lookup, row level security still checks the membership on every statement.
+type OrgAuthenticator interface {
+	AuthenticateOrg(ctx context.Context, header string) (string, []entities.Scope, uint64, error)
+}
+
+// AuthMiddleware tries the chain first, the first authenticator accepting the header decides.
+// Headers no authenticator accepts are checked as simple tokens by the service.
 type AuthMiddleware struct {
 	Service AuthService
+	Chain   []Authenticator
 }
 
 func (m *AuthMiddleware) Auth(c *fiber.Ctx) error {
@@ -28,12 +49,68 @@ func (m *AuthMiddleware) Auth(c *fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	userLogin, err := m.Service.GetUserLogin(c.Context(), token)
+	userLogin, scopes, tokenOrgID, err := m.authenticate(c.Context(), token)
 	if err != nil {
 		return fiber.ErrUnauthorized
 	}
 
 	c.Locals(entities.UserLoginKey, userLogin)
+	c.Locals(entities.UserScopesKey, scopes)
+
+	// Active organization is taken from the header, the personal one is used without it
+	var orgID uint64
+	if header := c.Get(OrgHeader, ""); header != "" {
+		orgID, err = strconv.ParseUint(header, 10, 64)
+		if err != nil || orgID == 0 {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	// Organization of the token is the only one it works in
+	if tokenOrgID != 0 {
+		if orgID != 0 && orgID != tokenOrgID {
+			return fiber.ErrForbidden
+		}
+		c.Locals(entities.OrgIDKey, tokenOrgID)
+		return c.Next()
+	}
+
+	org, err := m.Service.UserOrg(c.Context(), userLogin, orgID)
+	if errors.Is(err, entities.ErrNoOrg) {
+		return fiber.ErrForbidden
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Locals(entities.OrgIDKey, org.ID)
 
 	return c.Next()
 }
+
+func (m *AuthMiddleware) authenticate(ctx context.Context, header string) (string, []entities.Scope, uint64, error) {
+	for _, auth := range m.Chain {
+		if !auth.Accepts(header) {
+			continue
+		}
+		if auth, ok := auth.(OrgAuthenticator); ok {
+			return auth.AuthenticateOrg(ctx, header)
+		}
+		login, scopes, err := auth.Authenticate(ctx, header)
+		return login, scopes, 0, err
+	}
+	login, scopes, err := m.Service.GetUserLogin(ctx, header)
+	return login, scopes, 0, err
+}
+
+// RequireScope allows the route only to tokens with a scope including the required one,
+// requests without scopes are forbidden.
+func RequireScope(scope entities.Scope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scopes, _ := c.Locals(entities.UserScopesKey).([]entities.Scope)
+		if !entities.ScopesAllow(scopes, scope) {
+			return fiber.ErrForbidden
+		}
+		return c.Next()
+	}
+}
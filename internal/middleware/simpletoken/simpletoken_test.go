@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,9 +20,16 @@ type MockedUserService struct {
 	mock.Mock
 }
 
-func (m *MockedUserService) GetUserLogin(ctx context.Context, token string) (string, error) {
+func (m *MockedUserService) GetUserLogin(ctx context.Context, token string) (string, []entities.Scope, error) {
 	args := m.Called(ctx, token)
-	return args.Get(0).(string), args.Error(1)
+	return args.Get(0).(string), args.Get(1).([]entities.Scope), args.Error(2)
+}
+
+var adminScopes = []entities.Scope{entities.ScopeAdmin}
+
+func (m *MockedUserService) UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error) {
+	args := m.Called(ctx, login, id)
+	return args.Get(0).(entities.Organization), args.Error(1)
 }
 
 func TestAuthMiddleware(t *testing.T) {
@@ -30,7 +38,8 @@ func TestAuthMiddleware(t *testing.T) {
 		login := "user"
 
 		serviceMock := new(MockedUserService)
-		serviceMock.On("GetUserLogin", mock.Anything, token).Return(login, nil)
+		serviceMock.On("GetUserLogin", mock.Anything, token).Return(login, adminScopes, nil)
+		serviceMock.On("UserOrg", mock.Anything, login, uint64(0)).Return(entities.Organization{ID: 7, Role: entities.OrgOwner}, nil)
 		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock}
 
 		app := fiber.New()
@@ -41,6 +50,8 @@ func TestAuthMiddleware(t *testing.T) {
 				t.Errorf("getting login from context failed: want: %v got: %v", login, l)
 			}
 			assert.Equal(t, l, login)
+			assert.Equal(t, adminScopes, c.Locals(entities.UserScopesKey))
+			assert.Equal(t, uint64(7), c.Locals(entities.OrgIDKey))
 			return c.SendStatus(fiber.StatusOK)
 		})
 
@@ -94,7 +105,7 @@ func TestAuthMiddleware(t *testing.T) {
 		token := "123"
 
 		serviceMock := new(MockedUserService)
-		serviceMock.On("GetUserLogin", mock.Anything, token).Return("", fmt.Errorf("error"))
+		serviceMock.On("GetUserLogin", mock.Anything, token).Return("", []entities.Scope(nil), fmt.Errorf("error"))
 		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock}
 
 		app := fiber.New()
@@ -111,3 +122,249 @@ func TestAuthMiddleware(t *testing.T) {
 	})
 
 }
+
+func TestAuthMiddlewareOrg(t *testing.T) {
+	t.Run("organization from header", func(t *testing.T) {
+		serviceMock := new(MockedUserService)
+		serviceMock.On("GetUserLogin", mock.Anything, "123").Return("user", adminScopes, nil)
+		serviceMock.On("UserOrg", mock.Anything, "user", uint64(3)).Return(entities.Organization{ID: 3, Role: entities.OrgMember}, nil)
+		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock}
+
+		app := fiber.New()
+		app.Use(authMiddleware.Auth)
+		app.Get("/dummy", func(c *fiber.Ctx) error {
+			assert.Equal(t, uint64(3), c.Locals(entities.OrgIDKey))
+			assert.Equal(t, uint64(3), c.Context().Value(entities.OrgIDKey))
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+		req.Header.Set(simpletoken.AuthHeader, "123")
+		req.Header.Set(simpletoken.OrgHeader, "3")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		serviceMock.AssertExpectations(t)
+	})
+
+	t.Run("bad request if organization header is invalid", func(t *testing.T) {
+		serviceMock := new(MockedUserService)
+		serviceMock.On("GetUserLogin", mock.Anything, "123").Return("user", adminScopes, nil)
+		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock}
+
+		app := fiber.New()
+		app.Use(authMiddleware.Auth)
+		app.Get("/dummy", nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+		req.Header.Set(simpletoken.AuthHeader, "123")
+		req.Header.Set(simpletoken.OrgHeader, "abc")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		serviceMock.AssertNotCalled(t, "UserOrg", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("forbidden if user is not a member", func(t *testing.T) {
+		serviceMock := new(MockedUserService)
+		serviceMock.On("GetUserLogin", mock.Anything, "123").Return("user", adminScopes, nil)
+		serviceMock.On("UserOrg", mock.Anything, "user", uint64(5)).Return(entities.Organization{}, entities.ErrNoOrg)
+		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock}
+
+		app := fiber.New()
+		app.Use(authMiddleware.Auth)
+		app.Get("/dummy", nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+		req.Header.Set(simpletoken.AuthHeader, "123")
+		req.Header.Set(simpletoken.OrgHeader, "5")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		serviceMock.AssertExpectations(t)
+	})
+
+	t.Run("internal error if organization lookup fails", func(t *testing.T) {
+		serviceMock := new(MockedUserService)
+		serviceMock.On("GetUserLogin", mock.Anything, "123").Return("user", adminScopes, nil)
+		serviceMock.On("UserOrg", mock.Anything, "user", uint64(0)).Return(entities.Organization{}, fmt.Errorf("error"))
+		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock}
+
+		app := fiber.New()
+		app.Use(authMiddleware.Auth)
+		app.Get("/dummy", nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+		req.Header.Set(simpletoken.AuthHeader, "123")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []entities.Scope
+		required entities.Scope
+		status   int
+	}{
+		{"read-only token reads", []entities.Scope{entities.ScopeRead}, entities.ScopeRead, http.StatusOK},
+		{"read-only token can not write tasks", []entities.Scope{entities.ScopeRead}, entities.ScopeTasksWrite, http.StatusForbidden},
+		{"tasks token writes tasks", []entities.Scope{entities.ScopeTasksWrite}, entities.ScopeTasksWrite, http.StatusOK},
+		{"tasks token reads", []entities.Scope{entities.ScopeTasksWrite}, entities.ScopeRead, http.StatusOK},
+		{"tasks token can not administer", []entities.Scope{entities.ScopeTasksWrite}, entities.ScopeAdmin, http.StatusForbidden},
+		{"admin token does everything", []entities.Scope{entities.ScopeAdmin}, entities.ScopeTasksWrite, http.StatusOK},
+		{"any of the scopes is enough", []entities.Scope{entities.ScopeRead, entities.ScopeAdmin}, entities.ScopeAdmin, http.StatusOK},
+		{"no scopes", nil, entities.ScopeRead, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			if tt.scopes != nil {
+				app.Use(func(c *fiber.Ctx) error {
+					c.Locals(entities.UserScopesKey, tt.scopes)
+					return c.Next()
+				})
+			}
+			app.Post("/dummy", simpletoken.RequireScope(tt.required), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/dummy", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+type MockedAuthenticator struct {
+	mock.Mock
+}
+
+func (m *MockedAuthenticator) Accepts(header string) bool {
+	return strings.HasPrefix(header, "Bearer ")
+}
+
+func (m *MockedAuthenticator) Authenticate(ctx context.Context, header string) (string, []entities.Scope, error) {
+	args := m.Called(ctx, header)
+	return args.Get(0).(string), args.Get(1).([]entities.Scope), args.Error(2)
+}
+
+func TestAuthMiddlewareChain(t *testing.T) {
+	newApp := func(serviceMock *MockedUserService, auth *MockedAuthenticator) *fiber.App {
+		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock, Chain: []simpletoken.Authenticator{auth}}
+
+		app := fiber.New()
+		app.Use(authMiddleware.Auth)
+		app.Get("/dummy", func(c *fiber.Ctx) error {
+			assert.Equal(t, "user", c.Locals(entities.UserLoginKey))
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	t.Run("accepted header skips simple token lookup", func(t *testing.T) {
+		serviceMock := new(MockedUserService)
+		serviceMock.On("UserOrg", mock.Anything, "user", uint64(0)).Return(entities.Organization{ID: 7}, nil)
+		auth := new(MockedAuthenticator)
+		auth.On("Authenticate", mock.Anything, "Bearer jwt").Return("user", adminScopes, nil)
+		app := newApp(serviceMock, auth)
+
+		req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+		req.Header.Set(simpletoken.AuthHeader, "Bearer jwt")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		serviceMock.AssertNotCalled(t, "GetUserLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejected header is not tried as simple token", func(t *testing.T) {
+		serviceMock := new(MockedUserService)
+		auth := new(MockedAuthenticator)
+		auth.On("Authenticate", mock.Anything, "Bearer jwt").Return("", []entities.Scope(nil), entities.ErrInvalidToken)
+		app := newApp(serviceMock, auth)
+
+		req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+		req.Header.Set(simpletoken.AuthHeader, "Bearer jwt")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		serviceMock.AssertNotCalled(t, "GetUserLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("other headers are simple tokens", func(t *testing.T) {
+		serviceMock := new(MockedUserService)
+		serviceMock.On("GetUserLogin", mock.Anything, "123").Return("user", adminScopes, nil)
+		serviceMock.On("UserOrg", mock.Anything, "user", uint64(0)).Return(entities.Organization{ID: 7}, nil)
+		auth := new(MockedAuthenticator)
+		app := newApp(serviceMock, auth)
+
+		req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+		req.Header.Set(simpletoken.AuthHeader, "123")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		auth.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+	})
+}
+
+type MockedOrgAuthenticator struct {
+	MockedAuthenticator
+}
+
+func (m *MockedOrgAuthenticator) AuthenticateOrg(ctx context.Context, header string) (string, []entities.Scope, uint64, error) {
+	args := m.Called(ctx, header)
+	return args.Get(0).(string), args.Get(1).([]entities.Scope), args.Get(2).(uint64), args.Error(3)
+}
+
+func TestAuthMiddlewareTokenOrg(t *testing.T) {
+	newApp := func(serviceMock *MockedUserService, auth *MockedOrgAuthenticator) *fiber.App {
+		authMiddleware := simpletoken.AuthMiddleware{Service: serviceMock, Chain: []simpletoken.Authenticator{auth}}
+
+		app := fiber.New()
+		app.Use(authMiddleware.Auth)
+		app.Get("/dummy", func(c *fiber.Ctx) error {
+			assert.Equal(t, "user", c.Locals(entities.UserLoginKey))
+			assert.Equal(t, uint64(9), c.Locals(entities.OrgIDKey))
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"organization of the token", "", http.StatusOK},
+		{"same organization in the header", "9", http.StatusOK},
+		{"other organization in the header", "7", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceMock := new(MockedUserService)
+			auth := new(MockedOrgAuthenticator)
+			auth.On("AuthenticateOrg", mock.Anything, "Bearer jwt").Return("user", adminScopes, uint64(9), nil)
+			app := newApp(serviceMock, auth)
+
+			req := httptest.NewRequest(http.MethodGet, "/dummy", nil)
+			req.Header.Set(simpletoken.AuthHeader, "Bearer jwt")
+			if tt.header != "" {
+				req.Header.Set(simpletoken.OrgHeader, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			serviceMock.AssertNotCalled(t, "UserOrg", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
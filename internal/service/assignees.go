@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type UserStorage interface {
+	UserExists(ctx context.Context, login string) (bool, error)
+}
+
+// validatePeople checks that the assignee and the reporter of the task are registered users.
+func (s *Service) validatePeople(ctx context.Context, task entities.Task) error {
+	for _, login := range []string{task.Assignee, task.Reporter} {
+		if login == "" {
+			continue
+		}
+		exists, err := s.Storage.UserExists(ctx, login)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %q", entities.ErrNoUser, login)
+		}
+	}
+	return nil
+}
+
+// notifyAssignee tells the new assignee about the task, assigning a task to yourself is not notified.
+func (s *Service) notifyAssignee(ctx context.Context, id uint64, task entities.Task, login string) error {
+	if task.Assignee == "" || task.Assignee == login {
+		return nil
+	}
+
+	message := fmt.Sprintf("Task assigned to you by %s", login)
+	return s.TgClient.SendTask(ctx, id, task.Name, message, task.Assignee)
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) UserExists(ctx context.Context, login string) (bool, error) {
+	args := m.Called(ctx, login)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestTaskAssigning(t *testing.T) {
+	t.Run("assignee is notified about the new task", func(t *testing.T) {
+		ctx := context.Background()
+		task := entities.Task{Name: "Review", Status: entities.StatusTodo, Assignee: "friend"}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserExists", ctx, "friend").Return(true, nil)
+		storageMock.On("TaskAdd", ctx, task, "user").Return(uint64(1), nil)
+		tgClientMock := new(MockedTgClient)
+		tgClientMock.On("SendTask", ctx, uint64(1), task.Name, task.Description, "user").Return(nil)
+		tgClientMock.On("SendTask", ctx, uint64(1), task.Name, "Task assigned to you by user", "friend").Return(nil)
+		s := service.New(storageMock, tgClientMock)
+
+		_, err := s.TaskAdd(ctx, task, "user")
+		assert.NoError(t, err)
+		tgClientMock.AssertExpectations(t)
+	})
+
+	t.Run("unknown assignee", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("UserExists", ctx, "nobody").Return(false, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskAdd(ctx, entities.Task{Name: "Review", Assignee: "nobody"}, "user")
+		assert.ErrorIs(t, err, entities.ErrNoUser)
+		storageMock.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown reporter", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+		storageMock.On("UserExists", ctx, "nobody").Return(false, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskUpdate(ctx, entities.Task{ID: 1, Name: "Review", Reporter: "nobody"}, entities.TaskUpdateFields, "user")
+		assert.ErrorIs(t, err, entities.ErrNoUser)
+		storageMock.AssertNotCalled(t, "TaskUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("only the new assignee is notified", func(t *testing.T) {
+		ctx := context.Background()
+		current := entities.Task{ID: 1, Name: "Review", Owner: "user", Access: entities.AccessOwner, Assignee: "friend"}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(current, nil)
+		storageMock.On("UserExists", ctx, mock.Anything).Return(true, nil)
+		storageMock.On("TaskUpdate", ctx, mock.Anything, "user").Return(nil)
+		tgClientMock := new(MockedTgClient)
+		tgClientMock.On("SendTask", ctx, uint64(1), "Review", "Task assigned to you by user", "other").Return(nil)
+		s := service.New(storageMock, tgClientMock)
+
+		err := s.TaskUpdate(ctx, entities.Task{ID: 1, Name: "Review", Assignee: "friend"}, entities.TaskUpdateFields, "user")
+		assert.NoError(t, err)
+		tgClientMock.AssertNotCalled(t, "SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		err = s.TaskUpdate(ctx, entities.Task{ID: 1, Name: "Review", Assignee: "other"}, entities.TaskUpdateFields, "user")
+		assert.NoError(t, err)
+		tgClientMock.AssertExpectations(t)
+	})
+}
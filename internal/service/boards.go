@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service/rank"
+)
+
+type BoardStorage interface {
+	Boards(ctx context.Context, login string) ([]entities.Board, error)
+	Board(ctx context.Context, id uint64, login string) (entities.Board, error)
+	BoardAdd(ctx context.Context, board entities.Board, login string) (uint64, error)
+	BoardRemove(ctx context.Context, id uint64, login string) error
+	BoardColumn(ctx context.Context, id uint64, login string) (entities.BoardColumn, error)
+	BoardColumnUpdate(ctx context.Context, column entities.BoardColumn, login string) error
+	BoardTasks(ctx context.Context, board entities.Board, login string) ([]entities.Task, error)
+	TaskRanksUpdate(ctx context.Context, ranks map[uint64]string, login string) error
+}
+
+func (s *Service) Boards(ctx context.Context, login string) ([]entities.Board, error) {
+	boards, err := s.Storage.Boards(ctx, login)
+	if err != nil {
+		return boards, fmt.Errorf("could not get boards: %w", err)
+	}
+	return boards, nil
+}
+
+// Board returns the board with tasks placed into columns by their status.
+func (s *Service) Board(ctx context.Context, id uint64, login string) (entities.Board, error) {
+	board, err := s.Storage.Board(ctx, id, login)
+	if err != nil {
+		return board, fmt.Errorf("could not get board: %w", err)
+	}
+
+	tasks, err := s.Storage.BoardTasks(ctx, board, login)
+	if err != nil {
+		return board, fmt.Errorf("could not get board: %w", err)
+	}
+	for i := range board.Columns {
+		board.Columns[i].Tasks = columnTasks(tasks, board.Columns[i].Status)
+	}
+
+	return board, nil
+}
+
+func (s *Service) BoardAdd(ctx context.Context, board entities.Board, login string) (uint64, error) {
+	board.Name = strings.TrimSpace(board.Name)
+	if board.Name == "" || len(board.Columns) == 0 {
+		return 0, fmt.Errorf("unable to add board: %w: name and columns are required", entities.ErrInvalidBoard)
+	}
+
+	var err error
+	seen := make(map[entities.TaskStatus]bool)
+	for i, column := range board.Columns {
+		if !column.Status.Valid() || seen[column.Status] {
+			return 0, fmt.Errorf("unable to add board: %w: column %d needs a unique status", entities.ErrInvalidBoard, i)
+		}
+		seen[column.Status] = true
+
+		if board.Columns[i], err = normalizeColumn(column); err != nil {
+			return 0, fmt.Errorf("unable to add board: %w", err)
+		}
+	}
+
+	id, err := s.Storage.BoardAdd(ctx, board, login)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add board: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Service) BoardRemove(ctx context.Context, id uint64, login string) error {
+	if err := s.Storage.BoardRemove(ctx, id, login); err != nil {
+		return fmt.Errorf("could not remove board: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) BoardColumnUpdate(ctx context.Context, column entities.BoardColumn, login string) error {
+	column, err := normalizeColumn(column)
+	if err != nil {
+		return fmt.Errorf("unable to update board column: %w", err)
+	}
+
+	if err := s.Storage.BoardColumnUpdate(ctx, column, login); err != nil {
+		return fmt.Errorf("unable to update board column: %w", err)
+	}
+	return nil
+}
+
+// TaskMove places the task into the board column between its new neighbours. Moving to another column
+// is a status transition, so it follows the transition rules and WIP limits. Only the moved task gets
+// a new rank, except for tasks of the column which were never ranked before.
+func (s *Service) TaskMove(ctx context.Context, id uint64, move entities.TaskMove, login string) (entities.Task, error) {
+	column, err := s.Storage.BoardColumn(ctx, move.ColumnID, login)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("unable to move task: %w", err)
+	}
+	board, err := s.Storage.Board(ctx, column.BoardID, login)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("unable to move task: %w", err)
+	}
+	task, err := s.Storage.Task(ctx, id, login)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("unable to move task: %w", err)
+	}
+	if board.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *board.ProjectID) {
+		return entities.Task{}, fmt.Errorf("unable to move task: %w: task is not on the board", entities.ErrInvalidMove)
+	}
+
+	tasks, err := s.Storage.BoardTasks(ctx, board, login)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("unable to move task: %w", err)
+	}
+	ordered := slices.DeleteFunc(columnTasks(tasks, column.Status), func(t entities.Task) bool { return t.ID == id })
+
+	ranks, err := moveRanks(ordered, id, move)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("unable to move task: %w", err)
+	}
+
+	// Status and ranks change together, so a rejected transition leaves the column as it was
+	if task.Status != column.Status {
+		if task, err = s.transition(ctx, task, column.Status, ranks); err != nil {
+			return entities.Task{}, fmt.Errorf("unable to move task: %w", err)
+		}
+	} else if err := s.Storage.TaskRanksUpdate(ctx, ranks, login); err != nil {
+		return entities.Task{}, fmt.Errorf("unable to move task: %w", err)
+	}
+
+	task.Rank = ranks[id]
+	return task, nil
+}
+
+// moveRanks returns new ranks for the task placed into the ordered column and for column tasks
+// without rank, which follow the ranked ones.
+func moveRanks(ordered []entities.Task, id uint64, move entities.TaskMove) (map[uint64]string, error) {
+	pos := len(ordered)
+	if move.After != nil {
+		i := slices.IndexFunc(ordered, func(t entities.Task) bool { return t.ID == *move.After })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: task %d is not in the column", entities.ErrInvalidMove, *move.After)
+		}
+		pos = i + 1
+	}
+	if move.Before != nil {
+		i := slices.IndexFunc(ordered, func(t entities.Task) bool { return t.ID == *move.Before })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: task %d is not in the column", entities.ErrInvalidMove, *move.Before)
+		}
+		if move.After != nil && i != pos {
+			return nil, fmt.Errorf("%w: tasks %d and %d are not neighbours", entities.ErrInvalidMove, *move.After, *move.Before)
+		}
+		pos = i
+	}
+
+	ranks := make(map[uint64]string)
+	keys := make([]string, len(ordered))
+	for i, t := range ordered {
+		keys[i] = t.Rank
+	}
+
+	// Unranked tasks are a tail of the column
+	if first := slices.Index(keys, ""); first >= 0 {
+		var after string
+		if first > 0 {
+			after = keys[first-1]
+		}
+		spread, err := rank.Spread(after, "", len(keys)-first)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range spread {
+			keys[first+i] = key
+			ranks[ordered[first+i].ID] = key
+		}
+	}
+
+	var prev, next string
+	if pos > 0 {
+		prev = keys[pos-1]
+	}
+	if pos < len(keys) {
+		next = keys[pos]
+	}
+	key, err := rank.Between(prev, next)
+	if err != nil {
+		// Concurrent moves may leave equal ranks, the whole column is ranked again then
+		spread, err := rank.Spread("", "", len(keys)+1)
+		if err != nil {
+			return nil, err
+		}
+		for i, t := range ordered {
+			j := i
+			if i >= pos {
+				j++
+			}
+			ranks[t.ID] = spread[j]
+		}
+		key = spread[pos]
+	}
+	ranks[id] = key
+
+	return ranks, nil
+}
+
+func columnTasks(tasks []entities.Task, status entities.TaskStatus) []entities.Task {
+	var res []entities.Task
+	for _, task := range tasks {
+		if task.Status == status {
+			res = append(res, task)
+		}
+	}
+	return res
+}
+
+func normalizeColumn(column entities.BoardColumn) (entities.BoardColumn, error) {
+	column.Name = strings.TrimSpace(column.Name)
+	if column.Name == "" || len(column.Name) > 64 {
+		return column, fmt.Errorf("%w: column name must be 1-64 characters", entities.ErrInvalidBoard)
+	}
+	if column.WIPLimit != nil && *column.WIPLimit <= 0 {
+		return column, fmt.Errorf("%w: WIP limit must be positive", entities.ErrInvalidBoard)
+	}
+	return column, nil
+}
@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Boards(ctx context.Context, login string) ([]entities.Board, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Board), args.Error(1)
+}
+
+func (m *MockedStorage) Board(ctx context.Context, id uint64, login string) (entities.Board, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.Board), args.Error(1)
+}
+
+func (m *MockedStorage) BoardAdd(ctx context.Context, board entities.Board, login string) (uint64, error) {
+	args := m.Called(ctx, board, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) BoardRemove(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) BoardColumn(ctx context.Context, id uint64, login string) (entities.BoardColumn, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.BoardColumn), args.Error(1)
+}
+
+func (m *MockedStorage) BoardColumnUpdate(ctx context.Context, column entities.BoardColumn, login string) error {
+	args := m.Called(ctx, column, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) BoardTasks(ctx context.Context, board entities.Board, login string) ([]entities.Task, error) {
+	args := m.Called(ctx, board, login)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedStorage) TaskRanksUpdate(ctx context.Context, ranks map[uint64]string, login string) error {
+	args := m.Called(ctx, ranks, login)
+	return args.Error(0)
+}
+
+func TestBoardAdding(t *testing.T) {
+	t.Run("success board adding", func(t *testing.T) {
+		ctx := context.Background()
+		board := entities.Board{Name: "Sprint", Columns: []entities.BoardColumn{
+			{Name: "To do", Status: entities.StatusTodo},
+			{Name: "Doing", Status: entities.StatusInProgress, WIPLimit: intPtr(3)},
+		}}
+		storageMock := new(MockedStorage)
+		storageMock.On("BoardAdd", ctx, board, "user").Return(uint64(1), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		board.Name = " Sprint "
+		id, err := s.BoardAdd(ctx, board, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+	})
+
+	t.Run("invalid boards", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, board := range []entities.Board{
+			{Name: " ", Columns: []entities.BoardColumn{{Name: "To do", Status: entities.StatusTodo}}},
+			{Name: "Sprint"},
+			{Name: "Sprint", Columns: []entities.BoardColumn{{Name: "To do", Status: "unknown"}}},
+			{Name: "Sprint", Columns: []entities.BoardColumn{{Name: "A", Status: entities.StatusTodo}, {Name: "B", Status: entities.StatusTodo}}},
+			{Name: "Sprint", Columns: []entities.BoardColumn{{Name: "", Status: entities.StatusTodo}}},
+			{Name: "Sprint", Columns: []entities.BoardColumn{{Name: "To do", Status: entities.StatusTodo, WIPLimit: intPtr(0)}}},
+		} {
+			_, err := s.BoardAdd(context.Background(), board, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidBoard)
+		}
+		storageMock.AssertNotCalled(t, "BoardAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestBoard(t *testing.T) {
+	ctx := context.Background()
+	board := entities.Board{ID: 1, Columns: []entities.BoardColumn{
+		{ID: 1, Status: entities.StatusTodo},
+		{ID: 2, Status: entities.StatusDone},
+	}}
+	tasks := []entities.Task{
+		{ID: 1, Status: entities.StatusTodo, Rank: "i"},
+		{ID: 2, Status: entities.StatusInProgress},
+		{ID: 3, Status: entities.StatusTodo},
+	}
+	storageMock := new(MockedStorage)
+	storageMock.On("Board", ctx, uint64(1), "user").Return(board, nil)
+	storageMock.On("BoardTasks", ctx, board, "user").Return(tasks, nil)
+	s := service.New(storageMock, new(MockedTgClient))
+
+	res, err := s.Board(ctx, 1, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Task{tasks[0], tasks[2]}, res.Columns[0].Tasks)
+	assert.Empty(t, res.Columns[1].Tasks)
+}
+
+func TestTaskMove(t *testing.T) {
+	ctx := context.Background()
+	column := entities.BoardColumn{ID: 2, BoardID: 1, Name: "Doing", Status: entities.StatusInProgress}
+	board := entities.Board{ID: 1}
+	tasks := []entities.Task{
+		{ID: 1, Status: entities.StatusInProgress, Rank: "c"},
+		{ID: 2, Status: entities.StatusInProgress, Rank: "m"},
+		{ID: 3, Owner: "user", Access: entities.AccessOwner, Status: entities.StatusTodo},
+	}
+	newStorage := func() *MockedStorage {
+		storageMock := new(MockedStorage)
+		storageMock.On("BoardColumn", ctx, uint64(2), "user").Return(column, nil)
+		storageMock.On("Board", ctx, uint64(1), "user").Return(board, nil)
+		storageMock.On("BoardTasks", ctx, board, "user").Return(tasks, nil)
+		return storageMock
+	}
+
+	t.Run("move within the column", func(t *testing.T) {
+		storageMock := newStorage()
+		storageMock.On("Task", ctx, uint64(2), "user").Return(tasks[1], nil)
+		storageMock.On("TaskRanksUpdate", ctx, map[uint64]string{2: "6"}, "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		task, err := s.TaskMove(ctx, 2, entities.TaskMove{ColumnID: 2, Before: uint64Ptr(1)}, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, "6", task.Rank)
+		storageMock.AssertNotCalled(t, "TaskStatusUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("move to another column changes status", func(t *testing.T) {
+		storageMock := newStorage()
+		storageMock.On("Task", ctx, uint64(3), "user").Return(tasks[2], nil)
+		change := entities.StatusChange{ID: 3, From: entities.StatusTodo, To: entities.StatusInProgress, Ranks: map[uint64]string{3: "h"}}
+		storageMock.On("TaskStatusUpdate", ctx, change, "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		task, err := s.TaskMove(ctx, 3, entities.TaskMove{ColumnID: 2, After: uint64Ptr(1), Before: uint64Ptr(2)}, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, entities.StatusInProgress, task.Status)
+		assert.Equal(t, "h", task.Rank)
+		storageMock.AssertNotCalled(t, "TaskRanksUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WIP limit reached", func(t *testing.T) {
+		storageMock := newStorage()
+		storageMock.On("Task", ctx, uint64(3), "user").Return(tasks[2], nil)
+		storageMock.On("TaskStatusUpdate", ctx, mock.Anything, "user").Return(entities.ErrWIPLimit)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskMove(ctx, 3, entities.TaskMove{ColumnID: 2}, "user")
+		assert.ErrorIs(t, err, entities.ErrWIPLimit)
+		storageMock.AssertNotCalled(t, "TaskRanksUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("neighbours are not adjacent", func(t *testing.T) {
+		storageMock := newStorage()
+		storageMock.On("Task", ctx, uint64(3), "user").Return(tasks[2], nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskMove(ctx, 3, entities.TaskMove{ColumnID: 2, After: uint64Ptr(2), Before: uint64Ptr(1)}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidMove)
+
+		_, err = s.TaskMove(ctx, 3, entities.TaskMove{ColumnID: 2, After: uint64Ptr(7)}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidMove)
+	})
+
+	t.Run("task outside of the board project", func(t *testing.T) {
+		projectID := uint64(5)
+		storageMock := new(MockedStorage)
+		storageMock.On("BoardColumn", ctx, uint64(2), "user").Return(column, nil)
+		storageMock.On("Board", ctx, uint64(1), "user").Return(entities.Board{ID: 1, ProjectID: &projectID}, nil)
+		storageMock.On("Task", ctx, uint64(3), "user").Return(tasks[2], nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskMove(ctx, 3, entities.TaskMove{ColumnID: 2}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidMove)
+	})
+}
+
+func TestTaskMoveUnrankedColumn(t *testing.T) {
+	ctx := context.Background()
+	column := entities.BoardColumn{ID: 1, BoardID: 1, Status: entities.StatusTodo}
+	board := entities.Board{ID: 1}
+	tasks := []entities.Task{
+		{ID: 1, Status: entities.StatusTodo},
+		{ID: 2, Status: entities.StatusTodo},
+		{ID: 3, Status: entities.StatusTodo},
+	}
+	storageMock := new(MockedStorage)
+	storageMock.On("BoardColumn", ctx, uint64(1), "user").Return(column, nil)
+	storageMock.On("Board", ctx, uint64(1), "user").Return(board, nil)
+	storageMock.On("BoardTasks", ctx, board, "user").Return(tasks, nil)
+	storageMock.On("Task", ctx, uint64(3), "user").Return(tasks[2], nil)
+	storageMock.On("TaskRanksUpdate", ctx, mock.Anything, "user").Return(nil)
+	s := service.New(storageMock, new(MockedTgClient))
+
+	_, err := s.TaskMove(ctx, 3, entities.TaskMove{ColumnID: 1, Before: uint64Ptr(1)}, "user")
+	assert.NoError(t, err)
+
+	// Remaining tasks are ranked, the moved one goes first
+	ranks := storageMock.Calls[len(storageMock.Calls)-1].Arguments.Get(1).(map[uint64]string)
+	assert.Len(t, ranks, 3)
+	assert.Less(t, ranks[3], ranks[1])
+	assert.Less(t, ranks[1], ranks[2])
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
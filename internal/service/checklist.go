@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type ChecklistStorage interface {
+	Checklist(ctx context.Context, taskID uint64, owner string) ([]entities.ChecklistItem, error)
+	ChecklistItemAdd(ctx context.Context, item entities.ChecklistItem, owner string) (uint64, error)
+	ChecklistItemToggle(ctx context.Context, taskID uint64, id uint64, owner string) (entities.ChecklistItem, error)
+	ChecklistReorder(ctx context.Context, taskID uint64, ids []uint64, owner string) error
+	ChecklistItemRemove(ctx context.Context, taskID uint64, id uint64, owner string) error
+}
+
+func (s *Service) Checklist(ctx context.Context, taskID uint64, login string) ([]entities.ChecklistItem, error) {
+	// Check that the task exists, otherwise empty checklist is ambiguous
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessViewer)
+	if err != nil {
+		return nil, fmt.Errorf("could not get checklist: %w", err)
+	}
+
+	items, err := s.Storage.Checklist(ctx, taskID, task.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("could not get checklist: %w", err)
+	}
+	return items, nil
+}
+
+func (s *Service) ChecklistItemAdd(ctx context.Context, item entities.ChecklistItem, login string) (uint64, error) {
+	title := strings.TrimSpace(item.Title)
+	if title == "" || utf8.RuneCountInString(title) > 256 {
+		return 0, fmt.Errorf("unable to add checklist item: %w", entities.ErrInvalidChecklistItem)
+	}
+	item.Title = title
+
+	task, err := s.authorizeTask(ctx, item.TaskID, login, entities.AccessEditor)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add checklist item: %w", err)
+	}
+
+	id, err := s.Storage.ChecklistItemAdd(ctx, item, task.Owner)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add checklist item: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Service) ChecklistItemToggle(ctx context.Context, taskID uint64, id uint64, login string) (entities.ChecklistItem, error) {
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return entities.ChecklistItem{}, fmt.Errorf("unable to toggle checklist item: %w", err)
+	}
+
+	item, err := s.Storage.ChecklistItemToggle(ctx, taskID, id, task.Owner)
+	if err != nil {
+		return item, fmt.Errorf("unable to toggle checklist item: %w", err)
+	}
+	return item, nil
+}
+
+func (s *Service) ChecklistReorder(ctx context.Context, taskID uint64, ids []uint64, login string) error {
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return fmt.Errorf("unable to reorder checklist: %w", err)
+	}
+
+	if err := s.Storage.ChecklistReorder(ctx, taskID, ids, task.Owner); err != nil {
+		return fmt.Errorf("unable to reorder checklist: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ChecklistItemRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return fmt.Errorf("unable to remove checklist item: %w", err)
+	}
+
+	if err := s.Storage.ChecklistItemRemove(ctx, taskID, id, task.Owner); err != nil {
+		return fmt.Errorf("unable to remove checklist item: %w", err)
+	}
+	return nil
+}
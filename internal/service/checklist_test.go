@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Checklist(ctx context.Context, taskID uint64, login string) ([]entities.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, login)
+	return args.Get(0).([]entities.ChecklistItem), args.Error(1)
+}
+
+func (m *MockedStorage) ChecklistItemAdd(ctx context.Context, item entities.ChecklistItem, login string) (uint64, error) {
+	args := m.Called(ctx, item, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) ChecklistItemToggle(ctx context.Context, taskID uint64, id uint64, login string) (entities.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Get(0).(entities.ChecklistItem), args.Error(1)
+}
+
+func (m *MockedStorage) ChecklistReorder(ctx context.Context, taskID uint64, ids []uint64, login string) error {
+	args := m.Called(ctx, taskID, ids, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) ChecklistItemRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Error(0)
+}
+
+func TestChecklistItemAdding(t *testing.T) {
+	t.Run("success item adding", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "editor").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessEditor}, nil)
+		storageMock.On("ChecklistItemAdd", ctx, entities.ChecklistItem{TaskID: 1, Title: "write tests"}, "owner").Return(uint64(3), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		id, err := s.ChecklistItemAdd(ctx, entities.ChecklistItem{TaskID: 1, Title: " write tests "}, "editor")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), id)
+	})
+
+	t.Run("item adding by viewer", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "viewer").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessViewer}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.ChecklistItemAdd(ctx, entities.ChecklistItem{TaskID: 1, Title: "write tests"}, "viewer")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "ChecklistItemAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("item adding with invalid title", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, title := range []string{"", "   ", strings.Repeat("a", 257)} {
+			_, err := s.ChecklistItemAdd(context.Background(), entities.ChecklistItem{TaskID: 1, Title: title}, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidChecklistItem)
+		}
+		storageMock.AssertNotCalled(t, "ChecklistItemAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestChecklistReordering(t *testing.T) {
+	t.Run("success reordering", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+		storageMock.On("ChecklistReorder", ctx, uint64(1), []uint64{3, 1, 2}, "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.ChecklistReorder(ctx, 1, []uint64{3, 1, 2}, "user")
+		assert.NoError(t, err)
+	})
+
+	t.Run("reordering with missing items", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+		storageMock.On("ChecklistReorder", ctx, uint64(1), []uint64{3}, "user").Return(entities.ErrInvalidChecklistOrder)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.ChecklistReorder(ctx, 1, []uint64{3}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidChecklistOrder)
+	})
+
+	t.Run("reordering checklist of unknown task", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{}, entities.ErrNoTask)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.ChecklistReorder(ctx, 1, []uint64{3}, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+		storageMock.AssertNotCalled(t, "ChecklistReorder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestChecklistItemToggling(t *testing.T) {
+	ctx := context.Background()
+	item := entities.ChecklistItem{ID: 3, TaskID: 1, Title: "write tests", Done: true, Position: 1}
+	storageMock := new(MockedStorage)
+	storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+	storageMock.On("ChecklistItemToggle", ctx, uint64(1), uint64(3), "user").Return(item, nil)
+	s := service.New(storageMock, new(MockedTgClient))
+
+	result, err := s.ChecklistItemToggle(ctx, 1, 3, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, item, result)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type CommentStorage interface {
+	Comments(ctx context.Context, taskID uint64, owner string, page entities.PageRequest) (entities.CommentPage, error)
+	CommentAdd(ctx context.Context, comment entities.Comment, owner string) (uint64, error)
+	CommentUpdate(ctx context.Context, comment entities.Comment, login string) error
+	CommentRemove(ctx context.Context, taskID uint64, id uint64, login string) error
+}
+
+func (s *Service) Comments(ctx context.Context, taskID uint64, login string, page entities.PageRequest) (entities.CommentPage, error) {
+	// Check that the task exists, otherwise empty page is ambiguous
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessViewer)
+	if err != nil {
+		return entities.CommentPage{}, fmt.Errorf("could not get comments: %w", err)
+	}
+
+	comments, err := s.Storage.Comments(ctx, taskID, task.Owner, page)
+	if err != nil {
+		return comments, fmt.Errorf("could not get comments: %w", err)
+	}
+	return comments, nil
+}
+
+// CommentAdd adds comment to the task and notifies the task owner, commenting requires editor access.
+func (s *Service) CommentAdd(ctx context.Context, comment entities.Comment, login string) (uint64, error) {
+	if err := validateComment(comment); err != nil {
+		return 0, fmt.Errorf("unable to add comment: %w", err)
+	}
+
+	task, err := s.authorizeTask(ctx, comment.TaskID, login, entities.AccessEditor)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add comment: %w", err)
+	}
+
+	comment.Author = login
+	id, err := s.Storage.CommentAdd(ctx, comment, task.Owner)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add comment: %w", err)
+	}
+
+	message := fmt.Sprintf("New comment from %s: %s", comment.Author, comment.Body)
+	if err := s.TgClient.SendTask(ctx, task.ID, task.Name, message, task.Owner); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+
+	return id, nil
+}
+
+func (s *Service) CommentUpdate(ctx context.Context, comment entities.Comment, login string) error {
+	if err := validateComment(comment); err != nil {
+		return fmt.Errorf("unable to update comment: %w", err)
+	}
+	if _, err := s.authorizeTask(ctx, comment.TaskID, login, entities.AccessEditor); err != nil {
+		return fmt.Errorf("unable to update comment: %w", err)
+	}
+
+	if err := s.Storage.CommentUpdate(ctx, comment, login); err != nil {
+		return fmt.Errorf("unable to update comment: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) CommentRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	if _, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor); err != nil {
+		return fmt.Errorf("unable to remove comment: %w", err)
+	}
+
+	if err := s.Storage.CommentRemove(ctx, taskID, id, login); err != nil {
+		return fmt.Errorf("unable to remove comment: %w", err)
+	}
+	return nil
+}
+
+func validateComment(comment entities.Comment) error {
+	if strings.TrimSpace(comment.Body) == "" || utf8.RuneCountInString(comment.Body) > entities.MaxCommentLength {
+		return entities.ErrInvalidComment
+	}
+	return nil
+}
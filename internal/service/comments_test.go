@@ -0,0 +1,170 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Comments(ctx context.Context, taskID uint64, login string, page entities.PageRequest) (entities.CommentPage, error) {
+	args := m.Called(ctx, taskID, login, page)
+	return args.Get(0).(entities.CommentPage), args.Error(1)
+}
+
+func (m *MockedStorage) CommentAdd(ctx context.Context, comment entities.Comment, login string) (uint64, error) {
+	args := m.Called(ctx, comment, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) CommentUpdate(ctx context.Context, comment entities.Comment, login string) error {
+	args := m.Called(ctx, comment, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) CommentRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Error(0)
+}
+
+func TestCommentAdding(t *testing.T) {
+	task := entities.Task{ID: 1, Name: "Test task", Owner: "user", Access: entities.AccessOwner}
+
+	t.Run("success comment adding", func(t *testing.T) {
+		ctx := context.Background()
+		comment := entities.Comment{TaskID: task.ID, Body: "looks good"}
+		stored := entities.Comment{TaskID: task.ID, Body: "looks good", Author: "user"}
+
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, "user").Return(task, nil)
+		storageMock.On("CommentAdd", ctx, stored, "user").Return(uint64(5), nil)
+		tgClientMock := new(MockedTgClient)
+		tgClientMock.On("SendTask", ctx, task.ID, task.Name, "New comment from user: looks good", task.Owner).Return(nil)
+		s := service.New(storageMock, tgClientMock)
+
+		id, err := s.CommentAdd(ctx, comment, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), id)
+		tgClientMock.AssertExpectations(t)
+	})
+
+	t.Run("comment adding with invalid body", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, body := range []string{"", "  ", strings.Repeat("a", entities.MaxCommentLength+1)} {
+			_, err := s.CommentAdd(context.Background(), entities.Comment{TaskID: 1, Body: body}, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidComment)
+		}
+		storageMock.AssertNotCalled(t, "CommentAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("comment adding to unknown task", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{}, entities.ErrNoTask)
+		tgClientMock := new(MockedTgClient)
+		s := service.New(storageMock, tgClientMock)
+
+		_, err := s.CommentAdd(ctx, entities.Comment{TaskID: 1, Body: "hello"}, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+		tgClientMock.AssertNotCalled(t, "SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("comment adding with notification error", func(t *testing.T) {
+		ctx := context.Background()
+		stored := entities.Comment{TaskID: task.ID, Body: "hello", Author: "user"}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, "user").Return(task, nil)
+		storageMock.On("CommentAdd", ctx, stored, "user").Return(uint64(5), nil)
+		tgClientMock := new(MockedTgClient)
+		tgClientMock.On("SendTask", ctx, task.ID, task.Name, mock.Anything, task.Owner).Return(fmt.Errorf("error"))
+		s := service.New(storageMock, tgClientMock)
+
+		_, err := s.CommentAdd(ctx, entities.Comment{TaskID: task.ID, Body: "hello"}, "user")
+		assert.Error(t, err)
+	})
+
+	t.Run("comment adding to shared task", func(t *testing.T) {
+		ctx := context.Background()
+		shared := entities.Task{ID: 1, Name: "Test task", Owner: "owner", Access: entities.AccessEditor}
+		stored := entities.Comment{TaskID: shared.ID, Body: "hello", Author: "editor"}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, shared.ID, "editor").Return(shared, nil)
+		storageMock.On("CommentAdd", ctx, stored, "owner").Return(uint64(5), nil)
+		tgClientMock := new(MockedTgClient)
+		tgClientMock.On("SendTask", ctx, shared.ID, shared.Name, mock.Anything, "owner").Return(nil)
+		s := service.New(storageMock, tgClientMock)
+
+		id, err := s.CommentAdd(ctx, entities.Comment{TaskID: shared.ID, Body: "hello"}, "editor")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), id)
+	})
+
+	t.Run("comment adding by viewer", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "viewer").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessViewer}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.CommentAdd(ctx, entities.Comment{TaskID: 1, Body: "hello"}, "viewer")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "CommentAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCommentsGetting(t *testing.T) {
+	page := entities.PageRequest{Limit: 10}
+
+	t.Run("success comments getting", func(t *testing.T) {
+		ctx := context.Background()
+		comments := entities.CommentPage{Comments: []entities.Comment{{ID: 1, TaskID: 1, Body: "hello"}}, NextCursor: "next"}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "viewer").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessViewer}, nil)
+		storageMock.On("Comments", ctx, uint64(1), "owner", page).Return(comments, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.Comments(ctx, 1, "viewer", page)
+		assert.NoError(t, err)
+		assert.Equal(t, comments, result)
+	})
+
+	t.Run("comments of unknown task", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{}, entities.ErrNoTask)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.Comments(ctx, 1, "user", page)
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+		storageMock.AssertNotCalled(t, "Comments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCommentUpdating(t *testing.T) {
+	t.Run("success comment updating", func(t *testing.T) {
+		ctx := context.Background()
+		comment := entities.Comment{ID: 5, TaskID: 1, Body: "edited"}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+		storageMock.On("CommentUpdate", ctx, comment, "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.CommentUpdate(ctx, comment, "user")
+		assert.NoError(t, err)
+	})
+
+	t.Run("comment updating with empty body", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.CommentUpdate(context.Background(), entities.Comment{ID: 5, TaskID: 1}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidComment)
+	})
+}
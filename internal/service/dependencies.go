@@ -0,0 +1,137 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type DependencyStorage interface {
+	TaskDependencies(ctx context.Context, login string) ([]entities.Dependency, error)
+	TaskDependencyAdd(ctx context.Context, taskID uint64, blockerID uint64, owner string) error
+	TaskDependencyRemove(ctx context.Context, taskID uint64, blockerID uint64, owner string) error
+	TaskBlockers(ctx context.Context, id uint64, login string) ([]entities.Task, error)
+	TaskIDs(ctx context.Context, login string) ([]uint64, error)
+}
+
+// TaskDependencyAdd marks the task as blocked by the blocker, edges which create a cycle are rejected.
+// Editor access to the task is required, the blocker must belong to the same owner.
+func (s *Service) TaskDependencyAdd(ctx context.Context, taskID uint64, blockerID uint64, login string) error {
+	if taskID == blockerID {
+		return fmt.Errorf("unable to add task dependency: %w", entities.ErrDependencyCycle)
+	}
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return fmt.Errorf("unable to add task dependency: %w", err)
+	}
+	blocker, err := s.authorizeTask(ctx, blockerID, login, entities.AccessViewer)
+	if err != nil {
+		return fmt.Errorf("unable to add task dependency: %w", err)
+	}
+	if blocker.Owner != task.Owner {
+		return fmt.Errorf("unable to add task dependency: %w: blocker %d belongs to another owner", entities.ErrForbidden, blockerID)
+	}
+
+	// Storage rejects the edge closing a cycle in the same transaction it is added in
+	if err := s.Storage.TaskDependencyAdd(ctx, taskID, blockerID, task.Owner); err != nil {
+		return fmt.Errorf("unable to add task dependency: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) TaskDependencyRemove(ctx context.Context, taskID uint64, blockerID uint64, login string) error {
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return fmt.Errorf("unable to remove task dependency: %w", err)
+	}
+
+	if err := s.Storage.TaskDependencyRemove(ctx, taskID, blockerID, task.Owner); err != nil {
+		return fmt.Errorf("unable to remove task dependency: %w", err)
+	}
+	return nil
+}
+
+// TasksOrder returns identifiers of all user tasks ordered so that every blocker goes before
+// the tasks it blocks. Independent tasks keep ascending id order.
+func (s *Service) TasksOrder(ctx context.Context, login string) ([]uint64, error) {
+	ids, err := s.Storage.TaskIDs(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("unable to order tasks: %w", err)
+	}
+	dependencies, err := s.Storage.TaskDependencies(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("unable to order tasks: %w", err)
+	}
+
+	// Kahn's algorithm, edges go from blocker to blocked task
+	dependents := make(map[uint64][]uint64)
+	inDegree := make(map[uint64]int, len(ids))
+	for _, id := range ids {
+		inDegree[id] = 0
+	}
+	for _, d := range dependencies {
+		dependents[d.BlockerID] = append(dependents[d.BlockerID], d.TaskID)
+		inDegree[d.TaskID]++
+	}
+
+	ready := &idHeap{}
+	for _, id := range ids {
+		if inDegree[id] == 0 {
+			*ready = append(*ready, id)
+		}
+	}
+	heap.Init(ready)
+
+	order := make([]uint64, 0, len(ids))
+	for ready.Len() > 0 {
+		id := heap.Pop(ready).(uint64)
+		order = append(order, id)
+
+		for _, dependent := range dependents[id] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				heap.Push(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(inDegree) {
+		return nil, fmt.Errorf("unable to order tasks: %w", entities.ErrDependencyCycle)
+	}
+
+	return order, nil
+}
+
+// checkBlockers returns error if any blocker of the task is not completed yet.
+func (s *Service) checkBlockers(ctx context.Context, id uint64, login string) error {
+	blockers, err := s.Storage.TaskBlockers(ctx, id, login)
+	if err != nil {
+		return err
+	}
+	for _, blocker := range blockers {
+		if blocker.Status != entities.StatusDone && blocker.Status != entities.StatusCancelled {
+			return fmt.Errorf("task %d is not completed: %w", blocker.ID, entities.ErrOpenBlockers)
+		}
+	}
+	return nil
+}
+
+// idHeap is a min-heap of task ids, tasks ready to be ordered are taken in ascending id order.
+type idHeap []uint64
+
+func (h idHeap) Len() int           { return len(h) }
+func (h idHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h idHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *idHeap) Push(x any) {
+	*h = append(*h, x.(uint64))
+}
+
+func (h *idHeap) Pop() any {
+	old := *h
+	id := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return id
+}
@@ -0,0 +1,179 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) TaskDependencies(ctx context.Context, login string) ([]entities.Dependency, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Dependency), args.Error(1)
+}
+
+func (m *MockedStorage) TaskDependencyAdd(ctx context.Context, taskID uint64, blockerID uint64, login string) error {
+	args := m.Called(ctx, taskID, blockerID, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TaskDependencyRemove(ctx context.Context, taskID uint64, blockerID uint64, login string) error {
+	args := m.Called(ctx, taskID, blockerID, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TaskBlockers(ctx context.Context, id uint64, login string) ([]entities.Task, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedStorage) TaskIDs(ctx context.Context, login string) ([]uint64, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+func TestTaskDependencyAdding(t *testing.T) {
+	login := "user"
+
+	t.Run("success dependency adding", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "editor").Return(entities.Task{ID: 1, Owner: login, Access: entities.AccessEditor}, nil)
+		storageMock.On("Task", ctx, uint64(2), "editor").Return(entities.Task{ID: 2, Owner: login, Access: entities.AccessViewer}, nil)
+		storageMock.On("TaskDependencyAdd", ctx, uint64(1), uint64(2), login).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskDependencyAdd(ctx, 1, 2, "editor")
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("dependency adding by viewer", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "viewer").Return(entities.Task{ID: 1, Owner: login, Access: entities.AccessViewer}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskDependencyAdd(ctx, 1, 2, "viewer")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "TaskDependencyAdd", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("blocker of another owner", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), login).Return(entities.Task{ID: 1, Owner: login, Access: entities.AccessOwner}, nil)
+		storageMock.On("Task", ctx, uint64(2), login).Return(entities.Task{ID: 2, Owner: "friend", Access: entities.AccessEditor}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskDependencyAdd(ctx, 1, 2, login)
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "TaskDependencyAdd", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("dependency adding creates a cycle", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), login).Return(entities.Task{ID: 1, Owner: login, Access: entities.AccessOwner}, nil)
+		storageMock.On("Task", ctx, uint64(3), login).Return(entities.Task{ID: 3, Owner: login, Access: entities.AccessOwner}, nil)
+		storageMock.On("TaskDependencyAdd", ctx, uint64(1), uint64(3), login).Return(entities.ErrDependencyCycle)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskDependencyAdd(ctx, 1, 3, login)
+		assert.ErrorIs(t, err, entities.ErrDependencyCycle)
+	})
+
+	t.Run("task blocked by itself", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskDependencyAdd(context.Background(), 1, 1, login)
+		assert.ErrorIs(t, err, entities.ErrDependencyCycle)
+		storageMock.AssertNotCalled(t, "Task", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("blocker not found", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), login).Return(entities.Task{ID: 1, Owner: login, Access: entities.AccessOwner}, nil)
+		storageMock.On("Task", ctx, uint64(2), login).Return(entities.Task{}, entities.ErrNoTask)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskDependencyAdd(ctx, 1, 2, login)
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+	})
+}
+
+func TestTasksOrder(t *testing.T) {
+	login := "user"
+
+	t.Run("blockers go first", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskIDs", ctx, login).Return([]uint64{1, 2, 3, 4}, nil)
+		// 1 is blocked by 3, 3 is blocked by 4
+		storageMock.On("TaskDependencies", ctx, login).Return([]entities.Dependency{{TaskID: 1, BlockerID: 3}, {TaskID: 3, BlockerID: 4}}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		order, err := s.TasksOrder(ctx, login)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{2, 4, 3, 1}, order)
+	})
+
+	t.Run("ready tasks are taken in id order", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskIDs", ctx, login).Return([]uint64{1, 2, 3, 4, 5}, nil)
+		// 5 blocks 1 and 3, 4 blocks 2
+		storageMock.On("TaskDependencies", ctx, login).Return([]entities.Dependency{{TaskID: 1, BlockerID: 5}, {TaskID: 3, BlockerID: 5}, {TaskID: 2, BlockerID: 4}}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		order, err := s.TasksOrder(ctx, login)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{4, 2, 5, 1, 3}, order)
+	})
+
+	t.Run("cycle in stored dependencies", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskIDs", ctx, login).Return([]uint64{1, 2}, nil)
+		storageMock.On("TaskDependencies", ctx, login).Return([]entities.Dependency{{TaskID: 1, BlockerID: 2}, {TaskID: 2, BlockerID: 1}}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TasksOrder(ctx, login)
+		assert.ErrorIs(t, err, entities.ErrDependencyCycle)
+	})
+}
+
+func TestTaskTransitionWithBlockers(t *testing.T) {
+	task := entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner, Status: entities.StatusInProgress}
+
+	t.Run("open blocker", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("TaskBlockers", ctx, task.ID, task.Owner).Return([]entities.Task{{ID: 2, Status: entities.StatusBlocked}}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskTransition(ctx, task.ID, entities.StatusDone, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrOpenBlockers)
+		storageMock.AssertNotCalled(t, "TaskStatusUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("completed blockers", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("TaskBlockers", ctx, task.ID, task.Owner).Return([]entities.Task{{ID: 2, Status: entities.StatusDone}, {ID: 3, Status: entities.StatusCancelled}}, nil)
+		storageMock.On("TaskStatusUpdate", ctx, entities.StatusChange{ID: task.ID, From: task.Status, To: entities.StatusDone}, task.Owner).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.TaskTransition(ctx, task.ID, entities.StatusDone, task.Owner)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.StatusDone, result.Status)
+	})
+}
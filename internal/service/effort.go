@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type EffortStorage interface {
+	TaskEffort(ctx context.Context, id uint64, login string, now time.Time) (entities.TaskEffort, error)
+	EstimateTotals(ctx context.Context, from time.Time, to time.Time, now time.Time) ([]entities.EstimateAccuracy, error)
+}
+
+func (s *Service) TaskEffort(ctx context.Context, id uint64, login string) (entities.TaskEffort, error) {
+	effort, err := s.Storage.TaskEffort(ctx, id, login, time.Now())
+	if err != nil {
+		return effort, fmt.Errorf("could not get task effort: %w", err)
+	}
+
+	if effort.EstimateUnit == entities.EstimateMinutes && effort.OriginalEstimate != nil {
+		variance := effort.LoggedMinutes - *effort.OriginalEstimate
+		effort.Variance = &variance
+		effort.Accuracy = accuracy(*effort.OriginalEstimate, effort.LoggedMinutes)
+	}
+	return effort, nil
+}
+
+// EstimateAccuracy compares estimates of the organization tasks completed within [from, to) with time logged on them
+// per assignee, unassigned tasks count for their owners. The report is available to admins of the organization.
+func (s *Service) EstimateAccuracy(ctx context.Context, login string, from time.Time, to time.Time) ([]entities.EstimateAccuracy, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("could not get estimate accuracy: %w", entities.ErrInvalidPeriod)
+	}
+	if _, err := s.authorizeOrg(ctx, activeOrg(ctx), login, entities.OrgAdmin); err != nil {
+		return nil, fmt.Errorf("could not get estimate accuracy: %w", err)
+	}
+
+	totals, err := s.Storage.EstimateTotals(ctx, from, to, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("could not get estimate accuracy: %w", err)
+	}
+
+	for i := range totals {
+		totals[i].From = from
+		totals[i].To = to
+		totals[i].Accuracy = accuracy(totals[i].EstimatedMinutes, totals[i].LoggedMinutes)
+	}
+	return totals, nil
+}
+
+// accuracy is unknown until some time is logged.
+func accuracy(estimated int, logged int) *float64 {
+	if logged == 0 {
+		return nil
+	}
+	a := float64(estimated) / float64(logged)
+	return &a
+}
+
+func validateEstimate(task entities.Task) error {
+	if task.EstimateUnit != "" && !task.EstimateUnit.Valid() {
+		return entities.ErrInvalidEstimate
+	}
+	if task.OriginalEstimate != nil && *task.OriginalEstimate < 0 {
+		return entities.ErrInvalidEstimate
+	}
+	if task.RemainingEstimate != nil && *task.RemainingEstimate < 0 {
+		return entities.ErrInvalidEstimate
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) TaskEffort(ctx context.Context, id uint64, login string, now time.Time) (entities.TaskEffort, error) {
+	args := m.Called(ctx, id, login, now)
+	return args.Get(0).(entities.TaskEffort), args.Error(1)
+}
+
+func (m *MockedStorage) EstimateTotals(ctx context.Context, from time.Time, to time.Time, now time.Time) ([]entities.EstimateAccuracy, error) {
+	args := m.Called(ctx, from, to, now)
+	return args.Get(0).([]entities.EstimateAccuracy), args.Error(1)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestTaskEffort(t *testing.T) {
+	t.Run("estimate in minutes", func(t *testing.T) {
+		ctx := context.Background()
+		effort := entities.TaskEffort{TaskID: 1, EstimateUnit: entities.EstimateMinutes, OriginalEstimate: intPtr(60), LoggedMinutes: 80}
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskEffort", ctx, uint64(1), "user", mock.AnythingOfType("time.Time")).Return(effort, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		res, err := s.TaskEffort(ctx, 1, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, intPtr(20), res.Variance)
+		if assert.NotNil(t, res.Accuracy) {
+			assert.InDelta(t, 0.75, *res.Accuracy, 0.001)
+		}
+	})
+
+	t.Run("estimate in points is not comparable", func(t *testing.T) {
+		ctx := context.Background()
+		effort := entities.TaskEffort{TaskID: 1, EstimateUnit: entities.EstimatePoints, OriginalEstimate: intPtr(3), LoggedMinutes: 80}
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskEffort", ctx, uint64(1), "user", mock.Anything).Return(effort, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		res, err := s.TaskEffort(ctx, 1, "user")
+		assert.NoError(t, err)
+		assert.Nil(t, res.Variance)
+		assert.Nil(t, res.Accuracy)
+	})
+
+	t.Run("nothing logged yet", func(t *testing.T) {
+		ctx := context.Background()
+		effort := entities.TaskEffort{TaskID: 1, EstimateUnit: entities.EstimateMinutes, OriginalEstimate: intPtr(60)}
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskEffort", ctx, uint64(1), "user", mock.Anything).Return(effort, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		res, err := s.TaskEffort(ctx, 1, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, intPtr(-60), res.Variance)
+		assert.Nil(t, res.Accuracy)
+	})
+}
+
+func TestEstimateAccuracy(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("success aggregation", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), entities.OrgIDKey, uint64(7))
+		totals := []entities.EstimateAccuracy{
+			{Login: "friend", Tasks: 1, EstimatedMinutes: 60, LoggedMinutes: 0},
+			{Login: "user", Tasks: 2, EstimatedMinutes: 150, LoggedMinutes: 150},
+		}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(7)).Return(entities.Organization{ID: 7, Role: entities.OrgAdmin}, nil)
+		storageMock.On("EstimateTotals", ctx, from, to, mock.AnythingOfType("time.Time")).Return(totals, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		res, err := s.EstimateAccuracy(ctx, "user", from, to)
+		assert.NoError(t, err)
+		if assert.Equal(t, 2, len(res)) {
+			assert.Nil(t, res[0].Accuracy)
+			assert.Equal(t, from, res[1].From)
+			assert.Equal(t, to, res[1].To)
+			if assert.NotNil(t, res[1].Accuracy) {
+				assert.InDelta(t, 1.0, *res[1].Accuracy, 0.001)
+			}
+		}
+	})
+
+	t.Run("member of the organization", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), entities.OrgIDKey, uint64(7))
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(7)).Return(entities.Organization{ID: 7, Role: entities.OrgMember}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.EstimateAccuracy(ctx, "user", from, to)
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "EstimateTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.EstimateAccuracy(context.Background(), "user", from, from)
+		assert.ErrorIs(t, err, entities.ErrInvalidPeriod)
+	})
+}
+
+func TestTaskUpdatingEstimates(t *testing.T) {
+	storageMock := new(MockedStorage)
+	s := service.New(storageMock, new(MockedTgClient))
+
+	for _, task := range []entities.Task{
+		{ID: 1, Name: "task", EstimateUnit: "days"},
+		{ID: 1, Name: "task", OriginalEstimate: intPtr(-1)},
+		{ID: 1, Name: "task", RemainingEstimate: intPtr(-5)},
+	} {
+		err := s.TaskUpdate(context.Background(), task, entities.TaskUpdateFields, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidEstimate)
+	}
+	storageMock.AssertNotCalled(t, "TaskUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
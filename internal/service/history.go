@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type HistoryStorage interface {
+	TaskHistory(ctx context.Context, id uint64, login string) ([]entities.TaskEvent, error)
+	TaskRevert(ctx context.Context, id uint64, eventID uint64, login string) error
+}
+
+// TaskHistory returns changes of the task, it is visible to everyone who can see the task.
+func (s *Service) TaskHistory(ctx context.Context, id uint64, login string) ([]entities.TaskEvent, error) {
+	task, err := s.authorizeTask(ctx, id, login, entities.AccessViewer)
+	if err != nil {
+		return nil, fmt.Errorf("could not get task history: %w", err)
+	}
+
+	events, err := s.Storage.TaskHistory(ctx, id, task.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("could not get task history: %w", err)
+	}
+	return events, nil
+}
+
+// TaskRevert restores the task as it was before the event, editors of shared tasks are allowed to do it.
+func (s *Service) TaskRevert(ctx context.Context, id uint64, eventID uint64, login string) (entities.Task, error) {
+	current, err := s.authorizeTask(ctx, id, login, entities.AccessEditor)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("unable to revert task: %w", err)
+	}
+
+	if err := s.Storage.TaskRevert(ctx, id, eventID, current.Owner); err != nil {
+		return entities.Task{}, fmt.Errorf("unable to revert task: %w", err)
+	}
+
+	task, err := s.Storage.Task(ctx, id, login)
+	if err != nil {
+		return task, fmt.Errorf("unable to revert task: %w", err)
+	}
+	return task, nil
+}
@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) TaskHistory(ctx context.Context, id uint64, login string) ([]entities.TaskEvent, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).([]entities.TaskEvent), args.Error(1)
+}
+
+func (m *MockedStorage) TaskRevert(ctx context.Context, id uint64, eventID uint64, login string) error {
+	args := m.Called(ctx, id, eventID, login)
+	return args.Error(0)
+}
+
+func TestTaskHistory(t *testing.T) {
+	t.Run("viewer gets history of the shared task", func(t *testing.T) {
+		ctx := context.Background()
+		events := []entities.TaskEvent{{ID: 1, TaskID: 1, Author: "owner", Field: entities.TaskFieldName, OldValue: "a", NewValue: "b"}}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "viewer").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessViewer}, nil)
+		storageMock.On("TaskHistory", ctx, uint64(1), "owner").Return(events, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.TaskHistory(ctx, 1, "viewer")
+		assert.NoError(t, err)
+		assert.Equal(t, events, result)
+	})
+
+	t.Run("history of unknown task", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{}, entities.ErrNoTask)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskHistory(ctx, 1, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+		storageMock.AssertNotCalled(t, "TaskHistory", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskRevert(t *testing.T) {
+	t.Run("editor reverts the shared task", func(t *testing.T) {
+		ctx := context.Background()
+		reverted := entities.Task{ID: 1, Name: "a", Owner: "owner", Access: entities.AccessEditor}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "editor").Return(entities.Task{ID: 1, Name: "b", Owner: "owner", Access: entities.AccessEditor}, nil).Once()
+		storageMock.On("TaskRevert", ctx, uint64(1), uint64(5), "owner").Return(nil)
+		storageMock.On("Task", ctx, uint64(1), "editor").Return(reverted, nil).Once()
+		s := service.New(storageMock, new(MockedTgClient))
+
+		task, err := s.TaskRevert(ctx, 1, 5, "editor")
+		assert.NoError(t, err)
+		assert.Equal(t, reverted, task)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("viewer can not revert", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "viewer").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessViewer}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskRevert(ctx, 1, 5, "viewer")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "TaskRevert", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+		storageMock.On("TaskRevert", ctx, uint64(1), uint64(5), "user").Return(entities.ErrNoTaskEvent)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskRevert(ctx, 1, 5, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTaskEvent)
+	})
+}
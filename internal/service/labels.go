@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type LabelStorage interface {
+	Labels(ctx context.Context, login string) ([]entities.Label, error)
+	Label(ctx context.Context, id uint64, login string) (entities.Label, error)
+	LabelAdd(ctx context.Context, label entities.Label, login string) (uint64, error)
+	LabelUpdate(ctx context.Context, label entities.Label, login string) error
+	LabelRemove(ctx context.Context, id uint64, login string) error
+	TaskLabelAttach(ctx context.Context, taskID uint64, labelID uint64, owner string) error
+	TaskLabelDetach(ctx context.Context, taskID uint64, labelID uint64, owner string) error
+}
+
+func (s *Service) Labels(ctx context.Context, login string) ([]entities.Label, error) {
+	labels, err := s.Storage.Labels(ctx, login)
+	if err != nil {
+		return labels, fmt.Errorf("could not get labels: %w", err)
+	}
+	return labels, nil
+}
+
+func (s *Service) Label(ctx context.Context, id uint64, login string) (entities.Label, error) {
+	label, err := s.Storage.Label(ctx, id, login)
+	if err != nil {
+		return label, fmt.Errorf("could not get label: %w", err)
+	}
+	return label, nil
+}
+
+func (s *Service) LabelAdd(ctx context.Context, label entities.Label, login string) (uint64, error) {
+	if err := validateLabel(label); err != nil {
+		return 0, fmt.Errorf("unable to add label: %w", err)
+	}
+
+	id, err := s.Storage.LabelAdd(ctx, label, login)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add label: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Service) LabelUpdate(ctx context.Context, label entities.Label, login string) error {
+	if err := validateLabel(label); err != nil {
+		return fmt.Errorf("unable to update label: %w", err)
+	}
+
+	if err := s.Storage.LabelUpdate(ctx, label, login); err != nil {
+		return fmt.Errorf("unable to update label: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) LabelRemove(ctx context.Context, id uint64, login string) error {
+	if err := s.Storage.LabelRemove(ctx, id, login); err != nil {
+		return fmt.Errorf("could not remove label: %w", err)
+	}
+	return nil
+}
+
+// TaskLabelAttach attaches label of the task owner to the task, it requires editor access.
+func (s *Service) TaskLabelAttach(ctx context.Context, taskID uint64, labelID uint64, login string) error {
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return fmt.Errorf("unable to attach label: %w", err)
+	}
+	if _, err := s.Storage.Label(ctx, labelID, task.Owner); err != nil {
+		return fmt.Errorf("unable to attach label: %w", err)
+	}
+
+	if err := s.Storage.TaskLabelAttach(ctx, taskID, labelID, task.Owner); err != nil {
+		return fmt.Errorf("unable to attach label: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) TaskLabelDetach(ctx context.Context, taskID uint64, labelID uint64, login string) error {
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return fmt.Errorf("unable to detach label: %w", err)
+	}
+
+	if err := s.Storage.TaskLabelDetach(ctx, taskID, labelID, task.Owner); err != nil {
+		return fmt.Errorf("unable to detach label: %w", err)
+	}
+	return nil
+}
+
+// validateLabel checks label name, comma is reserved as a separator in task filters.
+func validateLabel(label entities.Label) error {
+	name := strings.TrimSpace(label.Name)
+	if name == "" || name != label.Name || len(name) > 64 || strings.Contains(name, ",") {
+		return entities.ErrInvalidLabel
+	}
+	return nil
+}
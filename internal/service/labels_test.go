@@ -0,0 +1,181 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Labels(ctx context.Context, login string) ([]entities.Label, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Label), args.Error(1)
+}
+
+func (m *MockedStorage) Label(ctx context.Context, id uint64, login string) (entities.Label, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.Label), args.Error(1)
+}
+
+func (m *MockedStorage) LabelAdd(ctx context.Context, label entities.Label, login string) (uint64, error) {
+	args := m.Called(ctx, label, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) LabelUpdate(ctx context.Context, label entities.Label, login string) error {
+	args := m.Called(ctx, label, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) LabelRemove(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TaskLabelAttach(ctx context.Context, taskID uint64, labelID uint64, login string) error {
+	args := m.Called(ctx, taskID, labelID, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TaskLabelDetach(ctx context.Context, taskID uint64, labelID uint64, login string) error {
+	args := m.Called(ctx, taskID, labelID, login)
+	return args.Error(0)
+}
+
+func TestLabelAdding(t *testing.T) {
+	t.Run("success label adding", func(t *testing.T) {
+		label := entities.Label{Name: "bug", Color: "#ff0000", Owner: "user"}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("LabelAdd", ctx, label, label.Owner).Return(uint64(1), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		id, err := s.LabelAdd(ctx, label, label.Owner)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+	})
+
+	t.Run("label adding with invalid name", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, name := range []string{"", " bug", "bug,ui"} {
+			_, err := s.LabelAdd(ctx, entities.Label{Name: name}, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidLabel)
+		}
+		storageMock.AssertNotCalled(t, "LabelAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("label adding with storage error", func(t *testing.T) {
+		label := entities.Label{Name: "bug"}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("LabelAdd", ctx, label, "user").Return(uint64(0), entities.ErrLabelExists)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.LabelAdd(ctx, label, "user")
+		assert.ErrorIs(t, err, entities.ErrLabelExists)
+	})
+}
+
+func TestLabelUpdating(t *testing.T) {
+	t.Run("success label updating", func(t *testing.T) {
+		label := entities.Label{ID: 1, Name: "bug"}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("LabelUpdate", ctx, label, "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.LabelUpdate(ctx, label, "user")
+		assert.NoError(t, err)
+	})
+
+	t.Run("label updating with invalid name", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.LabelUpdate(ctx, entities.Label{ID: 1}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidLabel)
+		storageMock.AssertNotCalled(t, "LabelUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLabelsGetting(t *testing.T) {
+	t.Run("success labels getting", func(t *testing.T) {
+		labels := []entities.Label{{ID: 1, Name: "bug", Owner: "user"}}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Labels", ctx, "user").Return(labels, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.Labels(ctx, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, labels, result)
+	})
+
+	t.Run("labels getting with error", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Labels", ctx, "user").Return([]entities.Label{}, fmt.Errorf("error"))
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.Labels(ctx, "user")
+		assert.Error(t, err)
+	})
+}
+
+func TestTaskLabelAttaching(t *testing.T) {
+	t.Run("success label attaching", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "editor").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessEditor}, nil)
+		storageMock.On("Label", ctx, uint64(2), "owner").Return(entities.Label{ID: 2}, nil)
+		storageMock.On("TaskLabelAttach", ctx, uint64(1), uint64(2), "owner").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskLabelAttach(ctx, 1, 2, "editor")
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("label attaching by viewer", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "viewer").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessViewer}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskLabelAttach(ctx, 1, 2, "viewer")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "TaskLabelAttach", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("label attaching to unexisted task", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{}, entities.ErrNoTask)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskLabelAttach(ctx, 1, 2, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+		storageMock.AssertNotCalled(t, "TaskLabelAttach", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("attaching unexisted label", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+		storageMock.On("Label", ctx, uint64(2), "user").Return(entities.Label{}, entities.ErrNoLabel)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskLabelAttach(ctx, 1, 2, "user")
+		assert.ErrorIs(t, err, entities.ErrNoLabel)
+		storageMock.AssertNotCalled(t, "TaskLabelAttach", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
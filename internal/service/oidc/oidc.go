@@ -0,0 +1,267 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/middleware/jwtauth"
+)
+
+const (
+	// StateMaxAge is how long the user may stay on the provider login page
+	StateMaxAge = 10 * time.Minute
+
+	// DefaultLoginClaim is the ID token claim the login of a new user is taken from, the subject is used without it
+	DefaultLoginClaim = "preferred_username"
+
+	// SessionName names access tokens issued on login
+	SessionName = "oidc session"
+	// SessionScope is the scope of access tokens issued on login, sessions work with tasks and can not
+	// manage tokens, organizations nor shares
+	SessionScope = entities.ScopeTasksWrite
+
+	randomBytes = 32
+)
+
+type Storage interface {
+	OIDCStateAdd(ctx context.Context, state entities.OIDCState, maxAge time.Duration) error
+	OIDCStateTake(ctx context.Context, state string, maxAge time.Duration) (entities.OIDCState, error)
+	IdentityUser(ctx context.Context, issuer string, subject string, login string) (string, error)
+}
+
+// TokenIssuer issues session tokens, they are ordinary access tokens of the user.
+type TokenIssuer interface {
+	TokenAdd(ctx context.Context, token entities.AccessToken, login string) (uint64, string, error)
+}
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	LoginClaim   string
+	// SessionLifetime is how long the session token issued on login is valid
+	SessionLifetime time.Duration
+}
+
+// discovery is the part of the provider metadata the login flow needs.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Service runs the authorization code flow with PKCE against the issuer.
+type Service struct {
+	Storage Storage
+	Tokens  TokenIssuer
+	Client  *http.Client
+
+	cfg      Config
+	provider discovery
+}
+
+// New reads the provider metadata from the issuer discovery document.
+func New(ctx context.Context, cfg Config, storage Storage, tokens TokenIssuer, client *http.Client) (*Service, error) {
+	if cfg.LoginClaim == "" {
+		cfg.LoginClaim = DefaultLoginClaim
+	}
+
+	s := &Service{
+		Storage: storage,
+		Tokens:  tokens,
+		Client:  client,
+		cfg:     cfg,
+	}
+
+	discoveryURL := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := s.getJSON(ctx, discoveryURL, &s.provider); err != nil {
+		return nil, fmt.Errorf("could not discover oidc provider: %w", err)
+	}
+	if s.provider.Issuer != cfg.Issuer {
+		return nil, fmt.Errorf("could not discover oidc provider: issuer %q does not match %q", s.provider.Issuer, cfg.Issuer)
+	}
+
+	return s, nil
+}
+
+// LoginURL starts a login and returns the provider page the browser is sent to and the binding of the login.
+// The binding is kept by the browser in a cookie, the callback is accepted only with it.
+func (s *Service) LoginURL(ctx context.Context) (string, string, error) {
+	binding := randomString()
+	state := entities.OIDCState{
+		State:       randomString(),
+		Verifier:    randomString(),
+		Nonce:       randomString(),
+		BindingHash: bindingHash(binding),
+	}
+	if err := s.Storage.OIDCStateAdd(ctx, state, StateMaxAge); err != nil {
+		return "", "", fmt.Errorf("could not start login: %w", err)
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.cfg.ClientID},
+		"redirect_uri":          {s.cfg.RedirectURL},
+		"scope":                 {"openid profile email"},
+		"state":                 {state.State},
+		"nonce":                 {state.Nonce},
+		"code_challenge":        {codeChallenge(state.Verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	return s.provider.AuthorizationEndpoint + "?" + query.Encode(), binding, nil
+}
+
+// Callback finishes the login the provider redirected back with: the code is exchanged for the ID token,
+// the user of the identity is created on the first login and a session token is issued.
+// Callback brought by another browser than the one which started the login is rejected by the binding.
+func (s *Service) Callback(ctx context.Context, code string, state string, binding string) (entities.Session, error) {
+	pending, err := s.Storage.OIDCStateTake(ctx, state, StateMaxAge)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("could not finish login: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(bindingHash(binding)), []byte(pending.BindingHash)) != 1 {
+		return entities.Session{}, fmt.Errorf("could not finish login: %w: login was started by another browser", entities.ErrOIDCLogin)
+	}
+
+	claims, err := s.exchange(ctx, code, pending)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("could not finish login: %w: %w", entities.ErrOIDCLogin, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return entities.Session{}, fmt.Errorf("could not finish login: %w: no subject", entities.ErrOIDCLogin)
+	}
+	login, _ := claims[s.cfg.LoginClaim].(string)
+	if login = strings.TrimSpace(login); login == "" {
+		login = subject
+	}
+
+	login, err = s.Storage.IdentityUser(ctx, s.cfg.Issuer, subject, login)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("could not finish login: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.cfg.SessionLifetime)
+	token := entities.AccessToken{Name: SessionName, Scopes: []entities.Scope{SessionScope}, ExpiresAt: &expiresAt}
+	_, secret, err := s.Tokens.TokenAdd(ctx, token, login)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("could not issue session token: %w", err)
+	}
+
+	return entities.Session{Login: login, Token: secret, ExpiresAt: expiresAt}, nil
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+// exchange redeems the code with the PKCE verifier and returns claims of the verified ID token.
+func (s *Service) exchange(ctx context.Context, code string, pending entities.OIDCState) (map[string]any, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.cfg.RedirectURL},
+		"client_id":     {s.cfg.ClientID},
+		"code_verifier": {pending.Verifier},
+	}
+	if s.cfg.ClientSecret != "" {
+		form.Set("client_secret", s.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tokens tokenResponse
+	if err := s.doJSON(req, &tokens); err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+
+	// Keys are fetched on every login, logins are rare and the provider may rotate keys at any time
+	var jwks json.RawMessage
+	if err := s.getJSON(ctx, s.provider.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("keys request failed: %w", err)
+	}
+	keys, err := jwtauth.ParseJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := jwtauth.New(keys, s.cfg.ClientID, "sub")
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := verifier.Claims(tokens.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims["iss"] != s.cfg.Issuer {
+		return nil, fmt.Errorf("issuer mismatch")
+	}
+	if claims["nonce"] != pending.Nonce {
+		return nil, fmt.Errorf("nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+func (s *Service) getJSON(ctx context.Context, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	return s.doJSON(req, v)
+}
+
+func (s *Service) doJSON(req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// codeChallenge derives the S256 PKCE challenge sent with the login from the verifier kept for the exchange.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// bindingHash is kept with the pending login instead of the binding itself.
+func bindingHash(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString() string {
+	buf := make([]byte, randomBytes)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,306 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service/oidc"
+)
+
+const (
+	clientID    = "wp-task"
+	redirectURL = "https://app.example/callback"
+	code        = "code-1"
+)
+
+type MockedStorage struct {
+	mock.Mock
+}
+
+func (m *MockedStorage) OIDCStateAdd(ctx context.Context, state entities.OIDCState, maxAge time.Duration) error {
+	args := m.Called(ctx, state, maxAge)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) OIDCStateTake(ctx context.Context, state string, maxAge time.Duration) (entities.OIDCState, error) {
+	args := m.Called(ctx, state, maxAge)
+	return args.Get(0).(entities.OIDCState), args.Error(1)
+}
+
+func (m *MockedStorage) IdentityUser(ctx context.Context, issuer string, subject string, login string) (string, error) {
+	args := m.Called(ctx, issuer, subject, login)
+	return args.String(0), args.Error(1)
+}
+
+type MockedTokenIssuer struct {
+	mock.Mock
+}
+
+func (m *MockedTokenIssuer) TokenAdd(ctx context.Context, token entities.AccessToken, login string) (uint64, string, error) {
+	args := m.Called(ctx, token, login)
+	return args.Get(0).(uint64), args.String(1), args.Error(2)
+}
+
+// mockIssuer is a local identity provider, it authorizes the code for the challenge and the nonce
+// the browser brought from the login page.
+type mockIssuer struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	issuer    string
+	challenge string
+	nonce     string
+	claims    map[string]any
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockIssuer{key: key, claims: map[string]any{"sub": "subject-1", "preferred_username": "alice"}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 m.issuer,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		b64 := base64.RawURLEncoding.EncodeToString
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig",
+			"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("code") != code ||
+			r.PostFormValue("client_id") != clientID || r.PostFormValue("redirect_uri") != redirectURL ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]string{"access_token": "opaque", "id_token": m.idToken(t)})
+	})
+	m.server = httptest.NewServer(mux)
+	m.issuer = m.server.URL
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+// authorize plays the browser on the provider login page.
+func (m *mockIssuer) authorize(t *testing.T, loginURL string) url.Values {
+	u, err := url.Parse(loginURL)
+	require.NoError(t, err)
+	assert.Equal(t, m.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+
+	query := u.Query()
+	m.challenge = query.Get("code_challenge")
+	m.nonce = query.Get("nonce")
+	return query
+}
+
+func (m *mockIssuer) idToken(t *testing.T) string {
+	claims := map[string]any{
+		"iss":   m.issuer,
+		"aud":   clientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": m.nonce,
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(map[string]string{"alg": "RS256", "kid": "k1"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func config(issuer string) oidc.Config {
+	return oidc.Config{Issuer: issuer, ClientID: clientID, RedirectURL: redirectURL, SessionLifetime: time.Hour}
+}
+
+// login runs the flow up to the callback in the browser which started it and returns its result.
+func login(t *testing.T, issuer *mockIssuer, storageMock *MockedStorage, tokens *MockedTokenIssuer) (entities.Session, error) {
+	return loginFrom(t, issuer, storageMock, tokens, "")
+}
+
+// loginFrom runs the flow up to the callback, the callback brings the other binding unless it is empty.
+func loginFrom(t *testing.T, issuer *mockIssuer, storageMock *MockedStorage, tokens *MockedTokenIssuer, other string) (entities.Session, error) {
+	ctx := context.Background()
+	var pending entities.OIDCState
+	storageMock.On("OIDCStateAdd", ctx, mock.Anything, oidc.StateMaxAge).Run(func(args mock.Arguments) {
+		pending = args.Get(1).(entities.OIDCState)
+	}).Return(nil)
+
+	s, err := oidc.New(ctx, config(issuer.issuer), storageMock, tokens, issuer.server.Client())
+	require.NoError(t, err)
+
+	loginURL, binding, err := s.LoginURL(ctx)
+	require.NoError(t, err)
+	query := issuer.authorize(t, loginURL)
+	assert.Equal(t, pending.State, query.Get("state"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, clientID, query.Get("client_id"))
+	assert.NotContains(t, loginURL, pending.Verifier)
+	assert.NotContains(t, loginURL, binding)
+	assert.NotEmpty(t, binding)
+	assert.NotEqual(t, binding, pending.BindingHash)
+
+	if other != "" {
+		binding = other
+	}
+	storageMock.On("OIDCStateTake", ctx, pending.State, oidc.StateMaxAge).Return(pending, nil)
+	return s.Callback(ctx, code, pending.State, binding)
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("user signs in and gets a session token", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		storageMock := new(MockedStorage)
+		storageMock.On("IdentityUser", mock.Anything, issuer.issuer, "subject-1", "alice").Return("alice", nil)
+		tokens := new(MockedTokenIssuer)
+		tokens.On("TokenAdd", mock.Anything, mock.MatchedBy(func(token entities.AccessToken) bool {
+			return token.Name == oidc.SessionName && token.ExpiresAt != nil &&
+				assert.ObjectsAreEqual([]entities.Scope{entities.ScopeTasksWrite}, token.Scopes)
+		}), "alice").Return(uint64(1), "wpt_session", nil)
+
+		session, err := login(t, issuer, storageMock, tokens)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", session.Login)
+		assert.Equal(t, "wpt_session", session.Token)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), session.ExpiresAt, time.Minute)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("subject is the login without the login claim", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		issuer.claims = map[string]any{"sub": "subject-1"}
+		storageMock := new(MockedStorage)
+		storageMock.On("IdentityUser", mock.Anything, issuer.issuer, "subject-1", "subject-1").Return("subject-1", nil)
+		tokens := new(MockedTokenIssuer)
+		tokens.On("TokenAdd", mock.Anything, mock.Anything, "subject-1").Return(uint64(1), "wpt_session", nil)
+
+		session, err := login(t, issuer, storageMock, tokens)
+		assert.NoError(t, err)
+		assert.Equal(t, "subject-1", session.Login)
+	})
+
+	t.Run("code is not redeemed without the verifier", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		storageMock := new(MockedStorage)
+		tokens := new(MockedTokenIssuer)
+
+		ctx := context.Background()
+		s, err := oidc.New(ctx, config(issuer.issuer), storageMock, tokens, issuer.server.Client())
+		require.NoError(t, err)
+		var pending entities.OIDCState
+		storageMock.On("OIDCStateAdd", ctx, mock.Anything, oidc.StateMaxAge).Run(func(args mock.Arguments) {
+			pending = args.Get(1).(entities.OIDCState)
+		}).Return(nil)
+		loginURL, binding, err := s.LoginURL(ctx)
+		require.NoError(t, err)
+		issuer.authorize(t, loginURL)
+
+		// State of another login carries another verifier
+		pending.Verifier = "other"
+		storageMock.On("OIDCStateTake", ctx, "state", oidc.StateMaxAge).Return(pending, nil)
+		_, err = s.Callback(ctx, code, "state", binding)
+		assert.ErrorIs(t, err, entities.ErrOIDCLogin)
+		storageMock.AssertNotCalled(t, "IdentityUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("callback of another browser is rejected", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		storageMock := new(MockedStorage)
+		tokens := new(MockedTokenIssuer)
+
+		_, err := loginFrom(t, issuer, storageMock, tokens, "binding-of-the-attacker")
+		assert.ErrorIs(t, err, entities.ErrOIDCLogin)
+		storageMock.AssertNotCalled(t, "IdentityUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		tokens.AssertNotCalled(t, "TokenAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("replayed ID token is rejected by nonce", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		issuer.claims["nonce"] = "replayed"
+		storageMock := new(MockedStorage)
+		tokens := new(MockedTokenIssuer)
+
+		_, err := login(t, issuer, storageMock, tokens)
+		assert.ErrorIs(t, err, entities.ErrOIDCLogin)
+		storageMock.AssertNotCalled(t, "IdentityUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ID token of another audience is rejected", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		issuer.claims["aud"] = "other-client"
+		storageMock := new(MockedStorage)
+		tokens := new(MockedTokenIssuer)
+
+		_, err := login(t, issuer, storageMock, tokens)
+		assert.ErrorIs(t, err, entities.ErrOIDCLogin)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("OIDCStateTake", ctx, "state", oidc.StateMaxAge).Return(entities.OIDCState{}, entities.ErrNoOIDCState)
+		s, err := oidc.New(ctx, config(issuer.issuer), storageMock, new(MockedTokenIssuer), issuer.server.Client())
+		require.NoError(t, err)
+
+		_, err = s.Callback(ctx, code, "state", "binding")
+		assert.ErrorIs(t, err, entities.ErrNoOIDCState)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("issuer of the discovery document must match", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		issuer.issuer = "https://evil.example"
+
+		_, err := oidc.New(context.Background(), config(issuer.server.URL), new(MockedStorage), new(MockedTokenIssuer), issuer.server.Client())
+		assert.Error(t, err)
+	})
+
+	t.Run("unreachable issuer", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		issuer.server.Close()
+
+		_, err := oidc.New(context.Background(), config(issuer.server.URL), new(MockedStorage), new(MockedTokenIssuer), http.DefaultClient)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+// OrgStorage keeps organizations and their members, the storage keeps at least one owner in every organization.
+type OrgStorage interface {
+	UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error)
+	Orgs(ctx context.Context, login string) ([]entities.Organization, error)
+	OrgAdd(ctx context.Context, org entities.Organization, login string) (uint64, error)
+	Members(ctx context.Context, id uint64) ([]entities.Membership, error)
+	MemberSet(ctx context.Context, member entities.Membership) error
+	MemberRemove(ctx context.Context, id uint64, login string) error
+}
+
+const maxOrgNameLength = 128
+
+func (s *Service) Orgs(ctx context.Context, login string) ([]entities.Organization, error) {
+	orgs, err := s.Storage.Orgs(ctx, login)
+	if err != nil {
+		return orgs, fmt.Errorf("could not get organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+// OrgAdd creates the organization owned by the user.
+func (s *Service) OrgAdd(ctx context.Context, org entities.Organization, login string) (uint64, error) {
+	org.Name = strings.TrimSpace(org.Name)
+	if org.Name == "" || utf8.RuneCountInString(org.Name) > maxOrgNameLength {
+		return 0, fmt.Errorf("unable to add organization: %w: name must be 1-%d characters", entities.ErrInvalidOrg, maxOrgNameLength)
+	}
+
+	id, err := s.Storage.OrgAdd(ctx, org, login)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add organization: %w", err)
+	}
+	return id, nil
+}
+
+// Members returns members of the organization, they are visible to members only.
+func (s *Service) Members(ctx context.Context, id uint64, login string) ([]entities.Membership, error) {
+	if _, err := s.authorizeOrg(ctx, id, login, entities.OrgMember); err != nil {
+		return nil, fmt.Errorf("could not get members: %w", err)
+	}
+
+	members, err := s.Storage.Members(ctx, id)
+	if err != nil {
+		return members, fmt.Errorf("could not get members: %w", err)
+	}
+	return members, nil
+}
+
+// MemberSet adds the registered user to the organization or changes the member role.
+// Admins manage members, owners are granted and changed by owners only.
+func (s *Service) MemberSet(ctx context.Context, member entities.Membership, login string) error {
+	member.Login = strings.TrimSpace(member.Login)
+	if member.Login == "" {
+		return fmt.Errorf("unable to set member: %w: login is required", entities.ErrInvalidOrg)
+	}
+	if !member.Role.Valid() {
+		return fmt.Errorf("unable to set member: %w: unknown role %q", entities.ErrInvalidOrg, member.Role)
+	}
+
+	if err := s.authorizeMember(ctx, member, login); err != nil {
+		return fmt.Errorf("unable to set member: %w", err)
+	}
+	exists, err := s.Storage.UserExists(ctx, member.Login)
+	if err != nil {
+		return fmt.Errorf("unable to set member: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("unable to set member: %w: %q", entities.ErrNoUser, member.Login)
+	}
+
+	if err := s.Storage.MemberSet(ctx, member); err != nil {
+		return fmt.Errorf("unable to set member: %w", err)
+	}
+	return nil
+}
+
+// MemberRemove removes the member from the organization, every member may leave it.
+func (s *Service) MemberRemove(ctx context.Context, id uint64, member string, login string) error {
+	if member == login {
+		if _, err := s.authorizeOrg(ctx, id, login, entities.OrgMember); err != nil {
+			return fmt.Errorf("could not remove member: %w", err)
+		}
+	} else if err := s.authorizeMember(ctx, entities.Membership{OrgID: id, Login: member}, login); err != nil {
+		return fmt.Errorf("could not remove member: %w", err)
+	}
+
+	if err := s.Storage.MemberRemove(ctx, id, member); err != nil {
+		return fmt.Errorf("could not remove member: %w", err)
+	}
+	return nil
+}
+
+// activeOrg returns the organization of the request set by the auth middleware, zero outside of requests.
+func activeOrg(ctx context.Context) uint64 {
+	id, _ := ctx.Value(entities.OrgIDKey).(uint64)
+	return id
+}
+
+// authorizeOrg returns the organization when the user is its member with at least the required role.
+func (s *Service) authorizeOrg(ctx context.Context, id uint64, login string, required entities.OrgRole) (entities.Organization, error) {
+	// Zero id selects the personal organization in storage, it is never a valid reference
+	if id == 0 {
+		return entities.Organization{}, entities.ErrNoOrg
+	}
+
+	org, err := s.Storage.UserOrg(ctx, login, id)
+	if err != nil {
+		return org, err
+	}
+	if !org.Role.Includes(required) {
+		return org, fmt.Errorf("%w: %s role in organization %d is required", entities.ErrForbidden, required, id)
+	}
+	return org, nil
+}
+
+// authorizeMember checks that the user may change the member: admins manage admins and members,
+// owners manage other owners.
+func (s *Service) authorizeMember(ctx context.Context, member entities.Membership, login string) error {
+	org, err := s.authorizeOrg(ctx, member.OrgID, login, entities.OrgAdmin)
+	if err != nil {
+		return err
+	}
+	if org.Role == entities.OrgOwner {
+		return nil
+	}
+
+	if member.Role == entities.OrgOwner {
+		return fmt.Errorf("%w: only owners grant the owner role", entities.ErrForbidden)
+	}
+	current, err := s.Storage.UserOrg(ctx, member.Login, member.OrgID)
+	if err != nil && !errors.Is(err, entities.ErrNoOrg) {
+		return err
+	}
+	if err == nil && current.Role == entities.OrgOwner {
+		return fmt.Errorf("%w: only owners change other owners", entities.ErrForbidden)
+	}
+	return nil
+}
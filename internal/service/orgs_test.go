@@ -0,0 +1,228 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error) {
+	args := m.Called(ctx, login, id)
+	return args.Get(0).(entities.Organization), args.Error(1)
+}
+
+func (m *MockedStorage) Orgs(ctx context.Context, login string) ([]entities.Organization, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Organization), args.Error(1)
+}
+
+func (m *MockedStorage) OrgAdd(ctx context.Context, org entities.Organization, login string) (uint64, error) {
+	args := m.Called(ctx, org, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) Members(ctx context.Context, id uint64) ([]entities.Membership, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]entities.Membership), args.Error(1)
+}
+
+func (m *MockedStorage) MemberSet(ctx context.Context, member entities.Membership) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) MemberRemove(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func TestOrgAdd(t *testing.T) {
+	t.Run("organization added with trimmed name", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("OrgAdd", ctx, entities.Organization{Name: "team"}, "user").Return(uint64(3), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		id, err := s.OrgAdd(ctx, entities.Organization{Name: "  team "}, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), id)
+	})
+
+	t.Run("empty name is rejected", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.OrgAdd(context.Background(), entities.Organization{Name: " "}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidOrg)
+		storageMock.AssertNotCalled(t, "OrgAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMembers(t *testing.T) {
+	t.Run("members are visible to members", func(t *testing.T) {
+		ctx := context.Background()
+		members := []entities.Membership{{OrgID: 1, Login: "user", Role: entities.OrgMember}}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(1)).Return(entities.Organization{ID: 1, Role: entities.OrgMember}, nil)
+		storageMock.On("Members", ctx, uint64(1)).Return(members, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.Members(ctx, 1, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, members, result)
+	})
+
+	t.Run("members are hidden from other users", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "stranger", uint64(1)).Return(entities.Organization{}, entities.ErrNoOrg)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.Members(ctx, 1, "stranger")
+		assert.ErrorIs(t, err, entities.ErrNoOrg)
+		storageMock.AssertNotCalled(t, "Members", mock.Anything, mock.Anything)
+	})
+
+	t.Run("zero organization is never found", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.Members(context.Background(), 0, "user")
+		assert.ErrorIs(t, err, entities.ErrNoOrg)
+		storageMock.AssertNotCalled(t, "UserOrg", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemberSet(t *testing.T) {
+	admin := entities.Organization{ID: 1, Role: entities.OrgAdmin}
+	owner := entities.Organization{ID: 1, Role: entities.OrgOwner}
+
+	t.Run("admin adds a member", func(t *testing.T) {
+		ctx := context.Background()
+		member := entities.Membership{OrgID: 1, Login: "new", Role: entities.OrgMember}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "admin", uint64(1)).Return(admin, nil)
+		storageMock.On("UserOrg", ctx, "new", uint64(1)).Return(entities.Organization{}, entities.ErrNoOrg)
+		storageMock.On("UserExists", ctx, "new").Return(true, nil)
+		storageMock.On("MemberSet", ctx, member).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberSet(ctx, member, "admin")
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("member can not manage members", func(t *testing.T) {
+		ctx := context.Background()
+		member := entities.Membership{OrgID: 1, Login: "new", Role: entities.OrgMember}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(1)).Return(entities.Organization{ID: 1, Role: entities.OrgMember}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberSet(ctx, member, "user")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "MemberSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin can not grant owner role", func(t *testing.T) {
+		ctx := context.Background()
+		member := entities.Membership{OrgID: 1, Login: "new", Role: entities.OrgOwner}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "admin", uint64(1)).Return(admin, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberSet(ctx, member, "admin")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "MemberSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin can not demote an owner", func(t *testing.T) {
+		ctx := context.Background()
+		member := entities.Membership{OrgID: 1, Login: "boss", Role: entities.OrgMember}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "admin", uint64(1)).Return(admin, nil)
+		storageMock.On("UserOrg", ctx, "boss", uint64(1)).Return(owner, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberSet(ctx, member, "admin")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "MemberSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("owner grants owner role", func(t *testing.T) {
+		ctx := context.Background()
+		member := entities.Membership{OrgID: 1, Login: "admin", Role: entities.OrgOwner}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "boss", uint64(1)).Return(owner, nil)
+		storageMock.On("UserExists", ctx, "admin").Return(true, nil)
+		storageMock.On("MemberSet", ctx, member).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberSet(ctx, member, "boss")
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("unknown user is rejected", func(t *testing.T) {
+		ctx := context.Background()
+		member := entities.Membership{OrgID: 1, Login: "ghost", Role: entities.OrgMember}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "boss", uint64(1)).Return(owner, nil)
+		storageMock.On("UserExists", ctx, "ghost").Return(false, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberSet(ctx, member, "boss")
+		assert.ErrorIs(t, err, entities.ErrNoUser)
+		storageMock.AssertNotCalled(t, "MemberSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown role is rejected", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberSet(context.Background(), entities.Membership{OrgID: 1, Login: "new", Role: "guest"}, "boss")
+		assert.ErrorIs(t, err, entities.ErrInvalidOrg)
+		storageMock.AssertNotCalled(t, "UserOrg", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMemberRemove(t *testing.T) {
+	t.Run("member leaves the organization", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(1)).Return(entities.Organization{ID: 1, Role: entities.OrgMember}, nil)
+		storageMock.On("MemberRemove", ctx, uint64(1), "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberRemove(ctx, 1, "user", "user")
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("member can not remove others", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(1)).Return(entities.Organization{ID: 1, Role: entities.OrgMember}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberRemove(ctx, 1, "other", "user")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "MemberRemove", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("last owner can not leave", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "boss", uint64(1)).Return(entities.Organization{ID: 1, Role: entities.OrgOwner}, nil)
+		storageMock.On("MemberRemove", ctx, uint64(1), "boss").Return(entities.ErrLastOwner)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.MemberRemove(ctx, 1, "boss", "boss")
+		assert.ErrorIs(t, err, entities.ErrLastOwner)
+	})
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type ProjectStorage interface {
+	Projects(ctx context.Context, login string, archived bool) ([]entities.Project, error)
+	Project(ctx context.Context, id uint64, login string) (entities.Project, error)
+	ProjectAdd(ctx context.Context, project entities.Project, login string) (uint64, error)
+	ProjectUpdate(ctx context.Context, project entities.Project, login string) error
+	ProjectRemove(ctx context.Context, id uint64, login string) error
+}
+
+// projectKeyRe matches keys like "WEB" or "API2", keys prefix task numbers so they are kept short.
+var projectKeyRe = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+func (s *Service) Projects(ctx context.Context, login string, archived bool) ([]entities.Project, error) {
+	projects, err := s.Storage.Projects(ctx, login, archived)
+	if err != nil {
+		return projects, fmt.Errorf("could not get projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (s *Service) Project(ctx context.Context, id uint64, login string) (entities.Project, error) {
+	project, err := s.Storage.Project(ctx, id, login)
+	if err != nil {
+		return project, fmt.Errorf("could not get project: %w", err)
+	}
+	return project, nil
+}
+
+func (s *Service) ProjectAdd(ctx context.Context, project entities.Project, login string) (uint64, error) {
+	if !projectKeyRe.MatchString(project.Key) {
+		return 0, fmt.Errorf("unable to add project: %w: key must be 2-10 uppercase letters or digits", entities.ErrInvalidProject)
+	}
+	project, err := normalizeProject(project)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add project: %w", err)
+	}
+
+	id, err := s.Storage.ProjectAdd(ctx, project, login)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add project: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Service) ProjectUpdate(ctx context.Context, project entities.Project, login string) error {
+	project, err := normalizeProject(project)
+	if err != nil {
+		return fmt.Errorf("unable to update project: %w", err)
+	}
+
+	if err := s.Storage.ProjectUpdate(ctx, project, login); err != nil {
+		return fmt.Errorf("unable to update project: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ProjectRemove(ctx context.Context, id uint64, login string) error {
+	if err := s.Storage.ProjectRemove(ctx, id, login); err != nil {
+		return fmt.Errorf("could not remove project: %w", err)
+	}
+	return nil
+}
+
+// ProjectTasks returns one page of the project tasks, the rest of filter applies as for all tasks.
+func (s *Service) ProjectTasks(ctx context.Context, id uint64, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error) {
+	// Check that the project exists, otherwise empty page is ambiguous
+	if _, err := s.Storage.Project(ctx, id, login); err != nil {
+		return entities.TaskPage{}, fmt.Errorf("could not get project tasks: %w", err)
+	}
+
+	filter.ProjectID = &id
+	tasks, err := s.Storage.Tasks(ctx, login, filter, page)
+	if err != nil {
+		return tasks, fmt.Errorf("could not get project tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func normalizeProject(project entities.Project) (entities.Project, error) {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" || utf8.RuneCountInString(project.Name) > 128 {
+		return project, fmt.Errorf("%w: name must be 1-128 characters", entities.ErrInvalidProject)
+	}
+	return project, nil
+}
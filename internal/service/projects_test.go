@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Projects(ctx context.Context, login string, archived bool) ([]entities.Project, error) {
+	args := m.Called(ctx, login, archived)
+	return args.Get(0).([]entities.Project), args.Error(1)
+}
+
+func (m *MockedStorage) Project(ctx context.Context, id uint64, login string) (entities.Project, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.Project), args.Error(1)
+}
+
+func (m *MockedStorage) ProjectAdd(ctx context.Context, project entities.Project, login string) (uint64, error) {
+	args := m.Called(ctx, project, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) ProjectUpdate(ctx context.Context, project entities.Project, login string) error {
+	args := m.Called(ctx, project, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) ProjectRemove(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func TestProjectAdding(t *testing.T) {
+	t.Run("success project adding", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("ProjectAdd", ctx, entities.Project{Key: "WEB2", Name: "Website"}, "user").Return(uint64(1), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		id, err := s.ProjectAdd(ctx, entities.Project{Key: "WEB2", Name: " Website "}, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+	})
+
+	t.Run("invalid projects", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, project := range []entities.Project{
+			{Key: "", Name: "Website"},
+			{Key: "W", Name: "Website"},
+			{Key: "web", Name: "Website"},
+			{Key: "2WEB", Name: "Website"},
+			{Key: "WEB-1", Name: "Website"},
+			{Key: "WEBSITEPROJ", Name: "Website"},
+			{Key: "WEB", Name: "  "},
+		} {
+			_, err := s.ProjectAdd(context.Background(), project, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidProject, project.Key)
+		}
+		storageMock.AssertNotCalled(t, "ProjectAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProjectTasks(t *testing.T) {
+	t.Run("filter is limited to the project", func(t *testing.T) {
+		ctx := context.Background()
+		projectID := uint64(3)
+		page := entities.PageRequest{Limit: 10}
+		storageMock := new(MockedStorage)
+		storageMock.On("Project", ctx, projectID, "user").Return(entities.Project{ID: projectID}, nil)
+		storageMock.On("Tasks", ctx, "user", entities.TaskFilter{ProjectID: &projectID}, page).Return(entities.TaskPage{}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.ProjectTasks(ctx, projectID, "user", entities.TaskFilter{}, page)
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("unexisted project", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Project", ctx, uint64(3), "user").Return(entities.Project{}, entities.ErrNoProject)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.ProjectTasks(ctx, 3, "user", entities.TaskFilter{}, entities.PageRequest{})
+		assert.ErrorIs(t, err, entities.ErrNoProject)
+		storageMock.AssertNotCalled(t, "Tasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
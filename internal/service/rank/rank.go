@@ -0,0 +1,113 @@
+// Package rank generates fractional ordering keys. A key can always be placed between two
+// other keys, so moving an item changes only the item itself.
+//
+// Keys are strings of base 36 digits compared byte by byte and never end with zero digit,
+// which keeps free space before any key.
+package rank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid rank key")
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Between returns a key ordered after a and before b. Empty a means the beginning
+// and empty b means the end of the list.
+func Between(a string, b string) (string, error) {
+	if err := validate(a); err != nil {
+		return "", err
+	}
+	if err := validate(b); err != nil {
+		return "", err
+	}
+	if a != "" && b != "" && a >= b {
+		return "", fmt.Errorf("%w: %q is not before %q", ErrInvalidKey, a, b)
+	}
+	return midpoint(a, b), nil
+}
+
+// Spread returns n ascending keys between a and b, used to rank items which had no key yet.
+// Keys are placed by bisection so their length grows only logarithmically.
+func Spread(a string, b string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	mid, err := Between(a, b)
+	if err != nil {
+		return nil, err
+	}
+	left, err := Spread(a, mid, n/2)
+	if err != nil {
+		return nil, err
+	}
+	right, err := Spread(mid, b, n-n/2-1)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := append(left, mid)
+	return append(keys, right...), nil
+}
+
+// midpoint works like a division of fractions: a is treated as padded with zeros
+// and empty b as the infinity.
+func midpoint(a string, b string) string {
+	if b != "" {
+		// Common prefix is kept as is
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+	// Adjacent digits: the first digit of longer b is already between
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[digitA]) + midpoint(suffix(a, 1), "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+func suffix(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
+
+func validate(key string) error {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	if strings.HasSuffix(key, digits[:1]) {
+		return fmt.Errorf("%w: %q ends with zero", ErrInvalidKey, key)
+	}
+	return nil
+}
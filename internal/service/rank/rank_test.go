@@ -0,0 +1,86 @@
+package rank_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/service/rank"
+)
+
+func TestBetween(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "i"},
+		{"i", "", "r"},
+		{"", "i", "9"},
+		{"a", "b", "ai"},
+		{"a", "c", "b"},
+		{"az", "b", "azi"},
+		{"a1", "a2", "a1i"},
+		{"a", "a1", "a0i"},
+		{"z", "", "zi"},
+		{"", "1", "0i"},
+	}
+	for _, tc := range cases {
+		got, err := rank.Between(tc.a, tc.b)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, got, "between %q and %q", tc.a, tc.b)
+	}
+}
+
+func TestBetweenInvalid(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"b", "a"},
+		{"a", "a"},
+		{"a0", ""},
+		{"A", ""},
+		{"", "a-"},
+	} {
+		_, err := rank.Between(tc[0], tc[1])
+		assert.ErrorIs(t, err, rank.ErrInvalidKey, tc)
+	}
+}
+
+func TestRandomInsertions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	keys := []string{}
+
+	for range 2000 {
+		i := r.Intn(len(keys) + 1)
+		var a, b string
+		if i > 0 {
+			a = keys[i-1]
+		}
+		if i < len(keys) {
+			b = keys[i]
+		}
+
+		key, err := rank.Between(a, b)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, a == "" || a < key, "%q < %q", a, key)
+		assert.True(t, b == "" || key < b, "%q < %q", key, b)
+		keys = slices.Insert(keys, i, key)
+	}
+	assert.True(t, slices.IsSorted(keys))
+}
+
+func TestSpread(t *testing.T) {
+	keys, err := rank.Spread("i", "", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n", "r", "w"}, keys)
+
+	keys, err = rank.Spread("", "", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, len(keys))
+	assert.True(t, slices.IsSorted(keys))
+	for _, key := range keys {
+		assert.LessOrEqual(t, len(key), 4)
+	}
+}
@@ -0,0 +1,321 @@
+// Package recurrence implements a subset of RFC 5545 recurrence rules.
+//
+// Supported parts are FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL,
+// BYDAY for weekly rules (plain weekdays without ordinals) and BYMONTHDAY for monthly rules.
+// Time of day and location of occurrences are taken from the series start.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+const untilLayout = "20060102T150405Z"
+
+// maxEmptyPeriods stops iteration of rules which never produce an occurrence, e.g. BYMONTHDAY=30
+// with an interval that only visits February.
+const maxEmptyPeriods = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// Parse parses rule like "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", optional "RRULE:" prefix is allowed.
+func Parse(s string) (Rule, error) {
+	r := Rule{Interval: 1}
+	s = strings.TrimPrefix(s, "RRULE:")
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if r.Freq != Daily && r.Freq != Weekly && r.Freq != Monthly && r.Freq != Yearly {
+				err = fmt.Errorf("unsupported frequency %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parsePositive(value)
+		case "COUNT":
+			r.Count, err = parsePositive(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value)
+			r.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					err = fmt.Errorf("unsupported weekday %q", day)
+					break
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				d, convErr := strconv.Atoi(day)
+				if convErr != nil || d == 0 || d < -31 || d > 31 {
+					err = fmt.Errorf("invalid month day %q", day)
+					break
+				}
+				r.ByMonthDay = append(r.ByMonthDay, d)
+			}
+		default:
+			err = fmt.Errorf("unsupported part %q", name)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return Rule{}, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+	}
+
+	return r, nil
+}
+
+func (r Rule) validate() error {
+	if r.Freq == "" {
+		return errors.New("frequency is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("count and until are mutually exclusive")
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return errors.New("weekdays are supported only by weekly rules")
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != Monthly {
+		return errors.New("month days are supported only by monthly rules")
+	}
+	return nil
+}
+
+// String returns the rule in canonical form without "RRULE:" prefix.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, weekday := range r.ByDay {
+			for code, d := range weekdays {
+				if d == weekday {
+					days[i] = code
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// All iterates over occurrences of the series started at start in chronological order.
+func (r Rule) All(start time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		interval := max(r.Interval, 1)
+		count := 0
+		empty := 0
+
+		for period := 0; empty < maxEmptyPeriods; period++ {
+			candidates := r.period(start, period*interval)
+			if len(candidates) == 0 {
+				empty++
+				continue
+			}
+			empty = 0
+
+			for _, t := range candidates {
+				if t.Before(start) {
+					continue
+				}
+				if r.Until != nil && t.After(*r.Until) {
+					return
+				}
+				if !yield(t) {
+					return
+				}
+				count++
+				if r.Count > 0 && count >= r.Count {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Next returns the first occurrence after the given time.
+func (r Rule) Next(start time.Time, after time.Time) (time.Time, bool) {
+	for t := range r.All(start) {
+		if t.After(after) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// After returns up to n occurrences following the given time.
+func (r Rule) After(start time.Time, after time.Time, n int) []time.Time {
+	var res []time.Time
+	for t := range r.All(start) {
+		if len(res) >= n {
+			break
+		}
+		if t.After(after) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Split divides the series at the given occurrence: the first rule ends right before it and
+// the second one continues from it keeping the remaining number of occurrences.
+func (r Rule) Split(start time.Time, at time.Time) (Rule, Rule) {
+	before := r
+	until := at.Add(-time.Second)
+	before.Until = &until
+	before.Count = 0
+
+	after := r
+	if r.Count > 0 {
+		passed := 0
+		for t := range r.All(start) {
+			if !t.Before(at) {
+				break
+			}
+			passed++
+		}
+		after.Count = max(r.Count-passed, 1)
+	}
+
+	return before, after
+}
+
+// period returns sorted candidate occurrences of the period with the given offset from start.
+func (r Rule) period(start time.Time, offset int) []time.Time {
+	year, month, day := start.Date()
+	hour, minute, sec := start.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, sec, start.Nanosecond(), start.Location())
+	}
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{at(year, month, day+offset)}
+
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(year, month, day+7*offset)}
+		}
+		// Weeks start on Monday
+		monday := day - (int(start.Weekday())+6)%7 + 7*offset
+		var res []time.Time
+		for _, weekday := range r.ByDay {
+			res = append(res, at(year, month, monday+(int(weekday)+6)%7))
+		}
+		slices.SortFunc(res, time.Time.Compare)
+		return slices.Compact(res)
+
+	case Monthly:
+		first := at(year, month+time.Month(offset), 1)
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{day}
+		}
+		last := daysIn(first.Year(), first.Month())
+		var res []time.Time
+		for _, d := range days {
+			if d < 0 {
+				d = last + 1 + d
+			}
+			// Months without such day are skipped
+			if d < 1 || d > last {
+				continue
+			}
+			res = append(res, at(first.Year(), first.Month(), d))
+		}
+		slices.SortFunc(res, time.Time.Compare)
+		return slices.Compact(res)
+
+	case Yearly:
+		if day > daysIn(year+offset, month) {
+			return nil
+		}
+		return []time.Time{at(year+offset, month, day)}
+	}
+
+	return nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func parsePositive(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid positive number %q", s)
+	}
+	return n, nil
+}
+
+func parseUntil(s string) (time.Time, error) {
+	if t, err := time.Parse(untilLayout, s); err == nil {
+		return t, nil
+	}
+	// Date only value includes the whole day
+	t, err := time.Parse("20060102", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid until %q", s)
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
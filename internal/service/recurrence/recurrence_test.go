@@ -0,0 +1,153 @@
+package recurrence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/service/recurrence"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 9, 30, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("valid rules", func(t *testing.T) {
+		for _, s := range []string{
+			"FREQ=DAILY",
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			"FREQ=MONTHLY;COUNT=3;BYMONTHDAY=1,-1",
+			"FREQ=YEARLY;UNTIL=20300101T000000Z",
+		} {
+			r, err := recurrence.Parse(s)
+			assert.NoError(t, err, s)
+			assert.Equal(t, s, r.String())
+		}
+	})
+
+	t.Run("prefix and date only until", func(t *testing.T) {
+		r, err := recurrence.Parse("RRULE:FREQ=DAILY;UNTIL=20250102")
+		assert.NoError(t, err)
+		assert.Equal(t, "FREQ=DAILY;UNTIL=20250102T235959Z", r.String())
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		for _, s := range []string{
+			"",
+			"INTERVAL=2",
+			"FREQ=HOURLY",
+			"FREQ=DAILY;INTERVAL=0",
+			"FREQ=DAILY;COUNT=2;UNTIL=20300101",
+			"FREQ=DAILY;BYDAY=MO",
+			"FREQ=WEEKLY;BYDAY=1MO",
+			"FREQ=MONTHLY;BYMONTHDAY=32",
+			"FREQ=WEEKLY;WKST=SU",
+		} {
+			_, err := recurrence.Parse(s)
+			assert.ErrorIs(t, err, recurrence.ErrInvalidRule, s)
+		}
+	})
+}
+
+func TestOccurrences(t *testing.T) {
+	cases := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: date(2025, 1, 30),
+			want:  []time.Time{date(2025, 1, 30), date(2025, 2, 2), date(2025, 2, 5)},
+		},
+		{
+			name:  "weekly on several days",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE",
+			start: date(2025, 5, 7), // Wednesday
+			want:  []time.Time{date(2025, 5, 7), date(2025, 5, 12), date(2025, 5, 14)},
+		},
+		{
+			name:  "monthly skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2025, 1, 31),
+			want:  []time.Time{date(2025, 1, 31), date(2025, 3, 31), date(2025, 5, 31)},
+		},
+		{
+			name:  "monthly on last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: date(2025, 1, 31),
+			want:  []time.Time{date(2025, 1, 31), date(2025, 2, 28), date(2025, 3, 31)},
+		},
+		{
+			name:  "yearly on leap day",
+			rule:  "FREQ=YEARLY",
+			start: date(2024, 2, 29),
+			want:  []time.Time{date(2024, 2, 29), date(2028, 2, 29), date(2032, 2, 29)},
+		},
+		{
+			name:  "count limit",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: date(2025, 1, 1),
+			want:  []time.Time{date(2025, 1, 1), date(2025, 1, 2)},
+		},
+		{
+			name:  "until limit",
+			rule:  "FREQ=WEEKLY;UNTIL=20250115T000000Z",
+			start: date(2025, 1, 1),
+			want:  []time.Time{date(2025, 1, 1), date(2025, 1, 8)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := recurrence.Parse(tc.rule)
+			assert.NoError(t, err)
+
+			var got []time.Time
+			for occurrence := range r.All(tc.start) {
+				if len(got) == 3 {
+					break
+				}
+				got = append(got, occurrence)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("rule without occurrences stops", func(t *testing.T) {
+		r, err := recurrence.Parse("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30")
+		assert.NoError(t, err)
+
+		_, ok := r.Next(date(2025, 2, 1), date(2025, 2, 1))
+		assert.False(t, ok)
+	})
+}
+
+func TestNextAndAfter(t *testing.T) {
+	r, err := recurrence.Parse("FREQ=WEEKLY")
+	assert.NoError(t, err)
+	start := date(2025, 1, 1)
+
+	next, ok := r.Next(start, start)
+	assert.True(t, ok)
+	assert.Equal(t, date(2025, 1, 8), next)
+
+	assert.Equal(t, []time.Time{date(2025, 1, 15), date(2025, 1, 22)}, r.After(start, date(2025, 1, 10), 2))
+}
+
+func TestSplit(t *testing.T) {
+	r, err := recurrence.Parse("FREQ=DAILY;COUNT=5")
+	assert.NoError(t, err)
+	start := date(2025, 1, 1)
+
+	before, after := r.Split(start, date(2025, 1, 3))
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20250103T092959Z", before.String())
+	assert.Equal(t, "FREQ=DAILY;COUNT=3", after.String())
+
+	assert.Equal(t, []time.Time{date(2025, 1, 2)}, before.After(start, start, 10))
+	assert.Equal(t, []time.Time{date(2025, 1, 4), date(2025, 1, 5)}, after.After(date(2025, 1, 3), date(2025, 1, 3), 10))
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service/recurrence"
+)
+
+type SeriesStorage interface {
+	Series(ctx context.Context, id uint64, login string) (entities.Series, error)
+	SeriesSplit(ctx context.Context, id uint64, at time.Time, before string, after entities.Series, update entities.SeriesUpdate, login string) (uint64, error)
+}
+
+// TaskOccurrences returns up to count occurrences of the task series following the task.
+func (s *Service) TaskOccurrences(ctx context.Context, id uint64, login string, count int) ([]time.Time, error) {
+	task, series, err := s.taskSeries(ctx, id, login, entities.AccessViewer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get task occurrences: %w", err)
+	}
+
+	rule, err := recurrence.Parse(series.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get task occurrences: %w: %w", entities.ErrInvalidRecurrence, err)
+	}
+
+	return rule.After(series.Start, *task.DueAt, count), nil
+}
+
+// TaskSeriesUpdate applies the update to the task occurrence and all the following ones,
+// they are moved to a new series while the old one ends before the task. Editors update the series
+// on behalf of the owner.
+func (s *Service) TaskSeriesUpdate(ctx context.Context, id uint64, update entities.SeriesUpdate, login string) (uint64, error) {
+	if update.Priority != "" && !update.Priority.Valid() {
+		return 0, fmt.Errorf("unable to update task series: %w", entities.ErrUnknownPriority)
+	}
+
+	task, series, err := s.taskSeries(ctx, id, login, entities.AccessEditor)
+	if err != nil {
+		return 0, fmt.Errorf("unable to update task series: %w", err)
+	}
+
+	rule, err := recurrence.Parse(series.Rule)
+	if err != nil {
+		return 0, fmt.Errorf("unable to update task series: %w: %w", entities.ErrInvalidRecurrence, err)
+	}
+	before, after := rule.Split(series.Start, *task.DueAt)
+
+	if update.Rule != "" {
+		if after, err = recurrence.Parse(update.Rule); err != nil {
+			return 0, fmt.Errorf("unable to update task series: %w: %w", entities.ErrInvalidRecurrence, err)
+		}
+	}
+	update.Rule = after.String()
+
+	next := entities.Series{Owner: task.Owner, Rule: update.Rule, Start: *task.DueAt}
+	seriesID, err := s.Storage.SeriesSplit(ctx, series.ID, *task.DueAt, before.String(), next, update, task.Owner)
+	if err != nil {
+		return 0, fmt.Errorf("unable to update task series: %w", err)
+	}
+	return seriesID, nil
+}
+
+// taskSeries returns the task with its series when the user has at least the required access to the task,
+// occurrences without due date are not scheduled. Series belongs to the owner of the task.
+func (s *Service) taskSeries(ctx context.Context, id uint64, login string, required entities.Access) (entities.Task, entities.Series, error) {
+	task, err := s.authorizeTask(ctx, id, login, required)
+	if err != nil {
+		return task, entities.Series{}, err
+	}
+	if task.SeriesID == nil || task.DueAt == nil {
+		return task, entities.Series{}, entities.ErrNoSeries
+	}
+
+	series, err := s.Storage.Series(ctx, *task.SeriesID, task.Owner)
+	if err != nil {
+		return task, series, err
+	}
+	return task, series, nil
+}
+
+// nextOccurrence returns the occurrence following the completed task or nil when the series is over.
+// Start date keeps its offset from the due date, labels, people and the original estimate are copied,
+// the project gives it a new number.
+func (s *Service) nextOccurrence(ctx context.Context, task entities.Task, login string) (*entities.Task, error) {
+	if task.DueAt == nil {
+		return nil, nil
+	}
+
+	series, err := s.Storage.Series(ctx, *task.SeriesID, login)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := recurrence.Parse(series.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrInvalidRecurrence, err)
+	}
+
+	dueAt, ok := rule.Next(series.Start, *task.DueAt)
+	if !ok {
+		return nil, nil
+	}
+
+	next := entities.Task{
+		Name:        task.Name,
+		Description: task.Description,
+		Owner:       task.Owner,
+		Status:      entities.StatusTodo,
+		Priority:    task.Priority,
+		DueAt:       &dueAt,
+		ParentID:    task.ParentID,
+		SeriesID:    task.SeriesID,
+		ProjectID:   task.ProjectID,
+
+		EstimateUnit:      task.EstimateUnit,
+		OriginalEstimate:  task.OriginalEstimate,
+		RemainingEstimate: task.OriginalEstimate,
+
+		Assignee: task.Assignee,
+		Reporter: task.Reporter,
+		Labels:   task.Labels,
+	}
+	if task.StartAt != nil {
+		startAt := dueAt.Add(-task.DueAt.Sub(*task.StartAt))
+		next.StartAt = &startAt
+	}
+
+	return &next, nil
+}
+
+// parseRecurrence checks the task recurrence rule, series are anchored at the due date so it is required.
+func parseRecurrence(task entities.Task) (recurrence.Rule, error) {
+	rule, err := recurrence.Parse(task.Recurrence)
+	if err != nil {
+		return rule, fmt.Errorf("%w: %w", entities.ErrInvalidRecurrence, err)
+	}
+	if task.DueAt == nil {
+		return rule, fmt.Errorf("%w: due date is required", entities.ErrInvalidRecurrence)
+	}
+	return rule, nil
+}
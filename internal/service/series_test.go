@@ -0,0 +1,261 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Series(ctx context.Context, id uint64, login string) (entities.Series, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.Series), args.Error(1)
+}
+
+func (m *MockedStorage) SeriesSplit(ctx context.Context, id uint64, at time.Time, before string, after entities.Series, update entities.SeriesUpdate, login string) (uint64, error) {
+	args := m.Called(ctx, id, at, before, after, update, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func TestRecurringTaskAdding(t *testing.T) {
+	dueAt := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success recurring task adding", func(t *testing.T) {
+		task := entities.Task{Name: "Weekly report", Owner: "user", DueAt: &dueAt, Recurrence: "RRULE:FREQ=WEEKLY;INTERVAL=1"}
+		stored := task
+		stored.Status = entities.StatusTodo
+		stored.Recurrence = "FREQ=WEEKLY"
+
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskAdd", ctx, stored, task.Owner).Return(uint64(1), nil)
+		tgClientMock := new(MockedTgClient)
+		tgClientMock.On("SendTask", ctx, uint64(1), task.Name, task.Description, task.Owner).Return(nil)
+		s := service.New(storageMock, tgClientMock)
+
+		_, err := s.TaskAdd(ctx, task, task.Owner)
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("recurring task adding with invalid rule", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, task := range []entities.Task{
+			{Name: "no due date", Recurrence: "FREQ=DAILY"},
+			{Name: "unknown frequency", DueAt: &dueAt, Recurrence: "FREQ=SECONDLY"},
+		} {
+			_, err := s.TaskAdd(context.Background(), task, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidRecurrence)
+		}
+		storageMock.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRecurringTaskCompletion(t *testing.T) {
+	seriesID := uint64(7)
+	startAt := time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC)
+	series := entities.Series{ID: seriesID, Owner: "user", Rule: "FREQ=WEEKLY", Start: dueAt}
+	task := entities.Task{
+		ID:       1,
+		Name:     "Weekly report",
+		Owner:    "user",
+		Access:   entities.AccessOwner,
+		Status:   entities.StatusInProgress,
+		Priority: entities.PriorityP1,
+		StartAt:  &startAt,
+		DueAt:    &dueAt,
+		SeriesID: &seriesID,
+		Labels:   []entities.Label{{ID: 3, Name: "chore"}},
+	}
+
+	nextStartAt := startAt.AddDate(0, 0, 7)
+	nextDueAt := dueAt.AddDate(0, 0, 7)
+	next := entities.Task{
+		Name:     task.Name,
+		Owner:    task.Owner,
+		Status:   entities.StatusTodo,
+		Priority: task.Priority,
+		StartAt:  &nextStartAt,
+		DueAt:    &nextDueAt,
+		SeriesID: &seriesID,
+		Labels:   task.Labels,
+	}
+
+	t.Run("next occurrence is created", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("TaskBlockers", ctx, task.ID, task.Owner).Return([]entities.Task{}, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(series, nil)
+		change := entities.StatusChange{ID: task.ID, From: task.Status, To: entities.StatusDone, Next: &next}
+		storageMock.On("TaskStatusUpdate", ctx, change, task.Owner).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskTransition(ctx, task.ID, entities.StatusDone, task.Owner)
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+		storageMock.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejected completion creates no occurrence", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("TaskBlockers", ctx, task.ID, task.Owner).Return([]entities.Task{}, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(series, nil)
+		storageMock.On("TaskStatusUpdate", ctx, mock.Anything, task.Owner).Return(entities.ErrInvalidTransition)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskTransition(ctx, task.ID, entities.StatusDone, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+		storageMock.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("series is over", func(t *testing.T) {
+		ctx := context.Background()
+		ended := series
+		ended.Rule = "FREQ=WEEKLY;COUNT=1"
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("TaskBlockers", ctx, task.ID, task.Owner).Return([]entities.Task{}, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(ended, nil)
+		storageMock.On("TaskStatusUpdate", ctx, entities.StatusChange{ID: task.ID, From: task.Status, To: entities.StatusDone}, task.Owner).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskTransition(ctx, task.ID, entities.StatusDone, task.Owner)
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+}
+
+func TestTaskOccurrences(t *testing.T) {
+	seriesID := uint64(7)
+	dueAt := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC)
+	task := entities.Task{ID: 1, Owner: "user", DueAt: &dueAt, SeriesID: &seriesID, Access: entities.AccessOwner}
+
+	t.Run("success occurrences preview", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(entities.Series{ID: seriesID, Rule: "FREQ=DAILY", Start: dueAt}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		occurrences, err := s.TaskOccurrences(ctx, task.ID, task.Owner, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{dueAt.AddDate(0, 0, 1), dueAt.AddDate(0, 0, 2)}, occurrences)
+	})
+
+	t.Run("task is not recurring", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(entities.Task{ID: 1, DueAt: &dueAt, Access: entities.AccessOwner}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskOccurrences(ctx, task.ID, task.Owner, 2)
+		assert.ErrorIs(t, err, entities.ErrNoSeries)
+	})
+
+	t.Run("viewer previews the series of the owner", func(t *testing.T) {
+		ctx := context.Background()
+		viewed := task
+		viewed.Access = entities.AccessViewer
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, "friend").Return(viewed, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(entities.Series{ID: seriesID, Rule: "FREQ=DAILY", Start: dueAt}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		occurrences, err := s.TaskOccurrences(ctx, task.ID, "friend", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{dueAt.AddDate(0, 0, 1)}, occurrences)
+	})
+}
+
+func TestTaskSeriesUpdate(t *testing.T) {
+	seriesID := uint64(7)
+	start := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2025, 5, 3, 10, 0, 0, 0, time.UTC)
+	task := entities.Task{ID: 3, Owner: "user", DueAt: &dueAt, SeriesID: &seriesID, Access: entities.AccessOwner}
+	series := entities.Series{ID: seriesID, Owner: "user", Rule: "FREQ=DAILY;COUNT=5", Start: start}
+
+	t.Run("this and future occurrences keep the rule", func(t *testing.T) {
+		ctx := context.Background()
+		update := entities.SeriesUpdate{Name: "renamed"}
+		expected := entities.SeriesUpdate{Name: "renamed", Rule: "FREQ=DAILY;COUNT=3"}
+		after := entities.Series{Owner: "user", Rule: expected.Rule, Start: dueAt}
+
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(series, nil)
+		storageMock.On("SeriesSplit", ctx, seriesID, dueAt, "FREQ=DAILY;UNTIL=20250503T095959Z", after, expected, task.Owner).Return(uint64(8), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		id, err := s.TaskSeriesUpdate(ctx, task.ID, update, task.Owner)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(8), id)
+	})
+
+	t.Run("this and future occurrences with a new rule", func(t *testing.T) {
+		ctx := context.Background()
+		update := entities.SeriesUpdate{Rule: "FREQ=WEEKLY"}
+		after := entities.Series{Owner: "user", Rule: "FREQ=WEEKLY", Start: dueAt}
+
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(series, nil)
+		storageMock.On("SeriesSplit", ctx, seriesID, dueAt, "FREQ=DAILY;UNTIL=20250503T095959Z", after, update, task.Owner).Return(uint64(8), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskSeriesUpdate(ctx, task.ID, update, task.Owner)
+		assert.NoError(t, err)
+	})
+
+	t.Run("editor updates the series on behalf of the owner", func(t *testing.T) {
+		ctx := context.Background()
+		edited := task
+		edited.Access = entities.AccessEditor
+		update := entities.SeriesUpdate{Rule: "FREQ=WEEKLY"}
+		after := entities.Series{Owner: "user", Rule: "FREQ=WEEKLY", Start: dueAt}
+
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, "friend").Return(edited, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(series, nil)
+		storageMock.On("SeriesSplit", ctx, seriesID, dueAt, "FREQ=DAILY;UNTIL=20250503T095959Z", after, update, task.Owner).Return(uint64(8), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskSeriesUpdate(ctx, task.ID, update, "friend")
+		assert.NoError(t, err)
+	})
+
+	t.Run("viewer can not update the series", func(t *testing.T) {
+		ctx := context.Background()
+		viewed := task
+		viewed.Access = entities.AccessViewer
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, "friend").Return(viewed, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskSeriesUpdate(ctx, task.ID, entities.SeriesUpdate{Name: "renamed"}, "friend")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "SeriesSplit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid new rule", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("Series", ctx, seriesID, task.Owner).Return(series, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskSeriesUpdate(ctx, task.ID, entities.SeriesUpdate{Rule: "FREQ=NEVER"}, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrInvalidRecurrence)
+		storageMock.AssertNotCalled(t, "SeriesSplit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
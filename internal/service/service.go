@@ -3,20 +3,41 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-code-mentor/wp-task/internal/entities"
 )
 
 type Storage interface {
 	TaskStorage
+	LabelStorage
+	DependencyStorage
+	SeriesStorage
+	CommentStorage
+	ChecklistStorage
+	TimeStorage
+	EffortStorage
+	ProjectStorage
+	BoardStorage
+	ShareStorage
+	UserStorage
+	OrgStorage
+	HistoryStorage
+	TrashStorage
 }
 
 type TaskStorage interface {
 	Task(ctx context.Context, id uint64, login string) (entities.Task, error)
-	Tasks(ctx context.Context, login string) ([]entities.Task, error)
-	TaskRemove(ctx context.Context, id uint64, login string) error
+	Tasks(ctx context.Context, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error)
+	TasksOverdue(ctx context.Context, login string, now time.Time) ([]entities.Task, error)
+	TasksUpcoming(ctx context.Context, login string, from time.Time, to time.Time) ([]entities.Task, error)
+	TaskStatusUpdate(ctx context.Context, change entities.StatusChange, login string) error
+	TaskRemove(ctx context.Context, id uint64, login string, mode entities.RemoveMode) error
 	TaskUpdate(ctx context.Context, task entities.Task, login string) error
 	TaskAdd(ctx context.Context, task entities.Task, login string) (uint64, error)
+	TaskChildren(ctx context.Context, id uint64, owner string) ([]entities.Task, error)
+	TaskTree(ctx context.Context, id uint64, owner string) (entities.TaskNode, error)
 }
 
 type TgTaskSender interface {
@@ -25,14 +46,16 @@ type TgTaskSender interface {
 
 func New(storage Storage, tgClient TgTaskSender) *Service {
 	return &Service{
-		Storage:  storage,
-		TgClient: tgClient,
+		Storage:     storage,
+		TgClient:    tgClient,
+		Transitions: DefaultTransitions,
 	}
 }
 
 type Service struct {
-	Storage  Storage
-	TgClient TgTaskSender
+	Storage     Storage
+	TgClient    TgTaskSender
+	Transitions TransitionGraph
 }
 
 func (s *Service) Task(ctx context.Context, id uint64, login string) (entities.Task, error) {
@@ -40,34 +63,139 @@ func (s *Service) Task(ctx context.Context, id uint64, login string) (entities.T
 	if err != nil {
 		return task, fmt.Errorf("could not get task: %w", err)
 	}
+
+	if task.SeriesID != nil {
+		series, err := s.Storage.Series(ctx, *task.SeriesID, task.Owner)
+		if err != nil {
+			return task, fmt.Errorf("could not get task series: %w", err)
+		}
+		task.Recurrence = series.Rule
+	}
+
 	return task, nil
 }
 
-func (s *Service) Tasks(ctx context.Context, login string) ([]entities.Task, error) {
-	tasks, err := s.Storage.Tasks(ctx, login)
+func (s *Service) Tasks(ctx context.Context, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error) {
+	tasks, err := s.Storage.Tasks(ctx, login, filter, page)
 	if err != nil {
 		return tasks, fmt.Errorf("could not get tasks: %w", err)
 	}
 	return tasks, nil
 }
 
-func (s *Service) TaskRemove(ctx context.Context, id uint64, login string) error {
-	err := s.Storage.TaskRemove(ctx, id, login)
+func (s *Service) TasksOverdue(ctx context.Context, login string) ([]entities.Task, error) {
+	tasks, err := s.Storage.TasksOverdue(ctx, login, time.Now())
+	if err != nil {
+		return tasks, fmt.Errorf("could not get overdue tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (s *Service) TasksUpcoming(ctx context.Context, login string, within time.Duration) ([]entities.Task, error) {
+	now := time.Now()
+	tasks, err := s.Storage.TasksUpcoming(ctx, login, now, now.Add(within))
+	if err != nil {
+		return tasks, fmt.Errorf("could not get upcoming tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// TaskRemove moves the task to trash, mode defines what happens with its subtasks and defaults to reject.
+// Only the owner can remove the task.
+func (s *Service) TaskRemove(ctx context.Context, id uint64, login string, mode entities.RemoveMode) error {
+	if mode == "" {
+		mode = entities.RemoveReject
+	}
+	if !mode.Valid() {
+		return fmt.Errorf("could not remove task: %w", entities.ErrUnknownRemoveMode)
+	}
+
+	if _, err := s.authorizeTask(ctx, id, login, entities.AccessOwner); err != nil {
+		return fmt.Errorf("could not remove task: %w", err)
+	}
+
+	err := s.Storage.TaskRemove(ctx, id, login, mode)
 	if err != nil {
 		return fmt.Errorf("could not remove task: %w", err)
 	}
 	return nil
 }
 
-func (s *Service) TaskUpdate(ctx context.Context, task entities.Task, login string) error {
-	err := s.Storage.TaskUpdate(ctx, task, login)
+// TaskUpdate changes the listed fields of the task on behalf of its owner, other fields keep their current values.
+// Editors of shared tasks are allowed to do it.
+func (s *Service) TaskUpdate(ctx context.Context, task entities.Task, fields []entities.TaskField, login string) error {
+	if err := validatePriority(task); err != nil {
+		return fmt.Errorf("unable to update task: %w", err)
+	}
+	if err := validateDates(task); err != nil {
+		return fmt.Errorf("unable to update task: %w", err)
+	}
+	if err := validateEstimate(task); err != nil {
+		return fmt.Errorf("unable to update task: %w", err)
+	}
+	if task.ParentID != nil && *task.ParentID == task.ID {
+		return fmt.Errorf("unable to update task: %w", entities.ErrTaskCycle)
+	}
+
+	current, err := s.authorizeTask(ctx, task.ID, login, entities.AccessEditor)
+	if err != nil {
+		return fmt.Errorf("unable to update task: %w", err)
+	}
+
+	// A single new date is checked against the kept one
+	task = keepOmitted(task, current, fields)
+	if err := validateDates(task); err != nil {
+		return fmt.Errorf("unable to update task: %w", err)
+	}
+	if err := s.validatePeople(ctx, task); err != nil {
+		return fmt.Errorf("unable to update task: %w", err)
+	}
+
+	err = s.Storage.TaskUpdate(ctx, task, current.Owner)
 	if err != nil {
 		return fmt.Errorf("unable to update task: %w", err)
 	}
+
+	if task.Assignee != current.Assignee {
+		if err := s.notifyAssignee(ctx, task.ID, task, login); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
 	return nil
 }
 
 func (s *Service) TaskAdd(ctx context.Context, task entities.Task, login string) (uint64, error) {
+	if task.Status == "" {
+		task.Status = entities.StatusTodo
+	}
+	if !task.Status.Valid() {
+		return 0, fmt.Errorf("unable to add task: %w", entities.ErrUnknownStatus)
+	}
+	if err := validatePriority(task); err != nil {
+		return 0, fmt.Errorf("unable to add task: %w", err)
+	}
+	if err := validateDates(task); err != nil {
+		return 0, fmt.Errorf("unable to add task: %w", err)
+	}
+	if err := validateEstimate(task); err != nil {
+		return 0, fmt.Errorf("unable to add task: %w", err)
+	}
+	if task.Recurrence != "" {
+		rule, err := parseRecurrence(task)
+		if err != nil {
+			return 0, fmt.Errorf("unable to add task: %w", err)
+		}
+		task.Recurrence = rule.String()
+	}
+	if err := s.validatePeople(ctx, task); err != nil {
+		return 0, fmt.Errorf("unable to add task: %w", err)
+	}
+
+	// Task created in another status is treated as moved there from the initial one
+	if task.Status != entities.StatusTodo && !s.Transitions.Allowed(entities.StatusTodo, task.Status) {
+		return 0, fmt.Errorf("unable to add task in %q: %w", task.Status, entities.ErrInvalidTransition)
+	}
+
 	id, err := s.Storage.TaskAdd(ctx, task, login)
 	if err != nil {
 		return 0, fmt.Errorf("unable to add task: %w", err)
@@ -76,6 +204,143 @@ func (s *Service) TaskAdd(ctx context.Context, task entities.Task, login string)
 	if err := s.TgClient.SendTask(ctx, id, task.Name, task.Description, login); err != nil {
 		return 0, fmt.Errorf("%w", err)
 	}
+	if err := s.notifyAssignee(ctx, id, task, login); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
 
 	return id, nil
 }
+
+// TaskTransition changes the task status on behalf of its owner, editors of shared tasks are allowed to do it.
+func (s *Service) TaskTransition(ctx context.Context, id uint64, to entities.TaskStatus, login string) (entities.Task, error) {
+	if !to.Valid() {
+		return entities.Task{}, fmt.Errorf("unable to change task status: %w", entities.ErrUnknownStatus)
+	}
+
+	task, err := s.authorizeTask(ctx, id, login, entities.AccessEditor)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("unable to change task status: %w", err)
+	}
+
+	return s.transition(ctx, task, to, nil)
+}
+
+// transition moves the task of its owner to the status, ranks are set along with the status.
+func (s *Service) transition(ctx context.Context, task entities.Task, to entities.TaskStatus, ranks map[uint64]string) (entities.Task, error) {
+	owner := task.Owner
+
+	if !s.Transitions.Allowed(task.Status, to) {
+		return entities.Task{}, fmt.Errorf("unable to move task from %q to %q: %w", task.Status, to, entities.ErrInvalidTransition)
+	}
+
+	// Task can be completed only when all its blockers are
+	if to == entities.StatusDone {
+		if err := s.checkBlockers(ctx, task.ID, owner); err != nil {
+			return entities.Task{}, fmt.Errorf("unable to change task status: %w", err)
+		}
+	}
+
+	change := entities.StatusChange{ID: task.ID, From: task.Status, To: to, Ranks: ranks}
+
+	// Completed occurrence of a recurring task produces the next one along with the status
+	if to == entities.StatusDone && task.SeriesID != nil {
+		next, err := s.nextOccurrence(ctx, task, owner)
+		if err != nil {
+			return entities.Task{}, fmt.Errorf("unable to create next task occurrence: %w", err)
+		}
+		change.Next = next
+	}
+
+	// Storage rejects the status when a board column of it is full
+	if err := s.Storage.TaskStatusUpdate(ctx, change, owner); err != nil {
+		return entities.Task{}, fmt.Errorf("unable to change task status: %w", err)
+	}
+
+	task.Status = to
+	return task, nil
+}
+
+func (s *Service) TaskChildren(ctx context.Context, id uint64, login string) ([]entities.Task, error) {
+	// Check that the parent exists, otherwise empty list is ambiguous
+	task, err := s.authorizeTask(ctx, id, login, entities.AccessViewer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get subtasks: %w", err)
+	}
+
+	tasks, err := s.Storage.TaskChildren(ctx, id, task.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get subtasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (s *Service) TaskTree(ctx context.Context, id uint64, login string) (entities.TaskNode, error) {
+	task, err := s.authorizeTask(ctx, id, login, entities.AccessViewer)
+	if err != nil {
+		return entities.TaskNode{}, fmt.Errorf("unable to get task tree: %w", err)
+	}
+
+	tree, err := s.Storage.TaskTree(ctx, id, task.Owner)
+	if err != nil {
+		return entities.TaskNode{}, fmt.Errorf("unable to get task tree: %w", err)
+	}
+	return tree, nil
+}
+
+// keepOmitted copies fields which are not listed from the current task, so that update never clears them implicitly.
+func keepOmitted(task entities.Task, current entities.Task, fields []entities.TaskField) entities.Task {
+	omitted := func(field entities.TaskField) bool {
+		return !slices.Contains(fields, field)
+	}
+	if omitted(entities.TaskFieldName) {
+		task.Name = current.Name
+	}
+	if omitted(entities.TaskFieldDescription) {
+		task.Description = current.Description
+	}
+	if omitted(entities.TaskFieldPriority) {
+		task.Priority = current.Priority
+	}
+	if omitted(entities.TaskFieldStartAt) {
+		task.StartAt = current.StartAt
+	}
+	if omitted(entities.TaskFieldDueAt) {
+		task.DueAt = current.DueAt
+	}
+	if omitted(entities.TaskFieldParent) {
+		task.ParentID = current.ParentID
+	}
+	if omitted(entities.TaskFieldProject) {
+		task.ProjectID = current.ProjectID
+	}
+	if omitted(entities.TaskFieldEstimateUnit) {
+		task.EstimateUnit = current.EstimateUnit
+	}
+	if omitted(entities.TaskFieldOriginalEstimate) {
+		task.OriginalEstimate = current.OriginalEstimate
+	}
+	if omitted(entities.TaskFieldRemainingEstimate) {
+		task.RemainingEstimate = current.RemainingEstimate
+	}
+	if omitted(entities.TaskFieldAssignee) {
+		task.Assignee = current.Assignee
+	}
+	if omitted(entities.TaskFieldReporter) {
+		task.Reporter = current.Reporter
+	}
+	return task
+}
+
+func validatePriority(task entities.Task) error {
+	if task.Priority != "" && !task.Priority.Valid() {
+		return entities.ErrUnknownPriority
+	}
+	return nil
+}
+
+func validateDates(task entities.Task) error {
+	if task.StartAt != nil && task.DueAt != nil && task.DueAt.Before(*task.StartAt) {
+		return entities.ErrInvalidDates
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,13 +22,28 @@ func (m *MockedStorage) Task(ctx context.Context, id uint64, login string) (enti
 	return args.Get(0).(entities.Task), args.Error(1)
 }
 
-func (m *MockedStorage) Tasks(ctx context.Context, login string) ([]entities.Task, error) {
-	args := m.Called(ctx, login)
+func (m *MockedStorage) Tasks(ctx context.Context, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error) {
+	args := m.Called(ctx, login, filter, page)
+	return args.Get(0).(entities.TaskPage), args.Error(1)
+}
+
+func (m *MockedStorage) TasksOverdue(ctx context.Context, login string, now time.Time) ([]entities.Task, error) {
+	args := m.Called(ctx, login, now)
 	return args.Get(0).([]entities.Task), args.Error(1)
 }
 
-func (m *MockedStorage) TaskRemove(ctx context.Context, id uint64, login string) error {
-	args := m.Called(ctx, id, login)
+func (m *MockedStorage) TasksUpcoming(ctx context.Context, login string, from time.Time, to time.Time) ([]entities.Task, error) {
+	args := m.Called(ctx, login, from, to)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedStorage) TaskStatusUpdate(ctx context.Context, change entities.StatusChange, login string) error {
+	args := m.Called(ctx, change, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TaskRemove(ctx context.Context, id uint64, login string, mode entities.RemoveMode) error {
+	args := m.Called(ctx, id, login, mode)
 	return args.Error(0)
 }
 
@@ -42,6 +58,16 @@ func (m *MockedStorage) TaskAdd(ctx context.Context, task entities.Task, login s
 	return args.Get(0).(uint64), args.Error(1)
 }
 
+func (m *MockedStorage) TaskChildren(ctx context.Context, id uint64, login string) ([]entities.Task, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedStorage) TaskTree(ctx context.Context, id uint64, login string) (entities.TaskNode, error) {
+	args := m.Called(ctx, id, login)
+	return args.Get(0).(entities.TaskNode), args.Error(1)
+}
+
 type MockedTgClient struct {
 	mock.Mock
 }
@@ -69,6 +95,20 @@ func TestTaskGetting(t *testing.T) {
 		assert.Equal(t, task, result)
 	})
 
+	t.Run("shared recurring task getting", func(t *testing.T) {
+		seriesID := uint64(7)
+		task := entities.Task{ID: 1, Name: "Weekly report", Owner: "owner", Access: entities.AccessViewer, SeriesID: &seriesID}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, "viewer").Return(task, nil)
+		storageMock.On("Series", ctx, seriesID, "owner").Return(entities.Series{ID: seriesID, Owner: "owner", Rule: "FREQ=WEEKLY"}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.Task(ctx, task.ID, "viewer")
+		assert.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY", result.Recurrence)
+	})
+
 	t.Run("task getting with error", func(t *testing.T) {
 		taskId := uint64(1)
 		login := "user"
@@ -92,23 +132,24 @@ func TestTasksGetting(t *testing.T) {
 		}
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
-		storageMock.On("Tasks", ctx, task.Owner).Return([]entities.Task{task}, nil)
+		page := entities.PageRequest{Limit: 10}
+		storageMock.On("Tasks", ctx, task.Owner, entities.TaskFilter{}, page).Return(entities.TaskPage{Tasks: []entities.Task{task}}, nil)
 		s := service.New(storageMock, new(MockedTgClient))
 
-		result, err := s.Tasks(ctx, task.Owner)
+		result, err := s.Tasks(ctx, task.Owner, entities.TaskFilter{}, page)
 		assert.NoError(t, err)
-		assert.Equal(t, 1, len(result))
-		assert.Equal(t, task, result[0])
+		assert.Equal(t, 1, len(result.Tasks))
+		assert.Equal(t, task, result.Tasks[0])
 	})
 
 	t.Run("tasks getting with error", func(t *testing.T) {
 		login := "user"
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
-		storageMock.On("Tasks", ctx, login).Return([]entities.Task{}, fmt.Errorf("error"))
+		storageMock.On("Tasks", ctx, login, entities.TaskFilter{}, entities.PageRequest{}).Return(entities.TaskPage{}, fmt.Errorf("error"))
 		s := service.New(storageMock, new(MockedTgClient))
 
-		_, err := s.Tasks(ctx, login)
+		_, err := s.Tasks(ctx, login, entities.TaskFilter{}, entities.PageRequest{})
 		assert.Error(t, err)
 	})
 
@@ -120,10 +161,11 @@ func TestTaskRemoving(t *testing.T) {
 		login := "user"
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
-		storageMock.On("TaskRemove", ctx, taskId, login).Return(nil)
+		storageMock.On("Task", ctx, taskId, login).Return(entities.Task{ID: taskId, Owner: login, Access: entities.AccessOwner}, nil)
+		storageMock.On("TaskRemove", ctx, taskId, login, entities.RemoveReject).Return(nil)
 		s := service.New(storageMock, new(MockedTgClient))
 
-		err := s.TaskRemove(ctx, taskId, login)
+		err := s.TaskRemove(ctx, taskId, login, "")
 		assert.NoError(t, err)
 	})
 
@@ -132,12 +174,78 @@ func TestTaskRemoving(t *testing.T) {
 		login := "user"
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
-		storageMock.On("TaskRemove", ctx, taskId, login).Return(fmt.Errorf("error"))
+		storageMock.On("Task", ctx, taskId, login).Return(entities.Task{ID: taskId, Owner: login, Access: entities.AccessOwner}, nil)
+		storageMock.On("TaskRemove", ctx, taskId, login, entities.RemoveReject).Return(fmt.Errorf("error"))
 		s := service.New(storageMock, new(MockedTgClient))
 
-		err := s.TaskRemove(ctx, taskId, login)
+		err := s.TaskRemove(ctx, taskId, login, "")
 		assert.Error(t, err)
 	})
+
+	t.Run("task removing with cascade mode", func(t *testing.T) {
+		taskId := uint64(1)
+		login := "user"
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, taskId, login).Return(entities.Task{ID: taskId, Owner: login, Access: entities.AccessOwner}, nil)
+		storageMock.On("TaskRemove", ctx, taskId, login, entities.RemoveCascade).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskRemove(ctx, taskId, login, entities.RemoveCascade)
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("task removing with unknown mode", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskRemove(context.Background(), 1, "user", "purge")
+		assert.ErrorIs(t, err, entities.ErrUnknownRemoveMode)
+		storageMock.AssertNotCalled(t, "TaskRemove")
+	})
+}
+
+func TestTaskChildren(t *testing.T) {
+	t.Run("success getting children", func(t *testing.T) {
+		parentID := uint64(1)
+		ctx := context.Background()
+		children := []entities.Task{{ID: 2, ParentID: &parentID, Owner: "owner"}}
+
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, parentID, "viewer").Return(entities.Task{ID: parentID, Owner: "owner", Access: entities.AccessViewer}, nil)
+		storageMock.On("TaskChildren", ctx, parentID, "owner").Return(children, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		tasks, err := s.TaskChildren(ctx, parentID, "viewer")
+		assert.NoError(t, err)
+		assert.Equal(t, children, tasks)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{}, entities.ErrNoTask)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskChildren(ctx, 1, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+		storageMock.AssertNotCalled(t, "TaskChildren")
+	})
+}
+
+func TestTaskTree(t *testing.T) {
+	ctx := context.Background()
+	tree := entities.TaskNode{Task: entities.Task{ID: 1}, Children: []entities.TaskNode{{Task: entities.Task{ID: 2}}}}
+
+	storageMock := new(MockedStorage)
+	storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner}, nil)
+	storageMock.On("TaskTree", ctx, uint64(1), "user").Return(tree, nil)
+	s := service.New(storageMock, new(MockedTgClient))
+
+	res, err := s.TaskTree(ctx, 1, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, tree, res)
 }
 
 func TestTaskAdding(t *testing.T) {
@@ -146,6 +254,7 @@ func TestTaskAdding(t *testing.T) {
 			Name:        "Test task",
 			Description: "test task description",
 			Owner:       "user",
+			Status:      entities.StatusTodo,
 		}
 		taskID := uint64(1)
 		ctx := context.Background()
@@ -166,6 +275,7 @@ func TestTaskAdding(t *testing.T) {
 			Name:        "Test task",
 			Description: "test task description",
 			Owner:       "user",
+			Status:      entities.StatusTodo,
 		}
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
@@ -186,6 +296,7 @@ func TestTaskAdding(t *testing.T) {
 			Name:        "Test task",
 			Description: "test task description",
 			Owner:       "user",
+			Status:      entities.StatusTodo,
 		}
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
@@ -199,6 +310,30 @@ func TestTaskAdding(t *testing.T) {
 		assert.Equal(t, uint64(0), id)
 	})
 
+	t.Run("status not reachable from the initial one", func(t *testing.T) {
+		task := entities.Task{Name: "Test task", Owner: "user", Status: entities.StatusDone}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+		s.Transitions = service.TransitionGraph{entities.StatusTodo: {entities.StatusInProgress}}
+
+		_, err := s.TaskAdd(ctx, task, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+		storageMock.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("column of the status is full", func(t *testing.T) {
+		task := entities.Task{Name: "Test task", Owner: "user", Status: entities.StatusInProgress}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskAdd", ctx, task, task.Owner).Return(uint64(0), entities.ErrWIPLimit)
+		tgClientMock := new(MockedTgClient)
+		s := service.New(storageMock, tgClientMock)
+
+		_, err := s.TaskAdd(ctx, task, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrWIPLimit)
+		tgClientMock.AssertNotCalled(t, "SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestTaskUpdating(t *testing.T) {
@@ -211,10 +346,11 @@ func TestTaskUpdating(t *testing.T) {
 		}
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(entities.Task{ID: task.ID, Owner: task.Owner, Access: entities.AccessOwner}, nil)
 		storageMock.On("TaskUpdate", ctx, task, task.Owner).Return(nil)
 		s := service.New(storageMock, new(MockedTgClient))
 
-		err := s.TaskUpdate(ctx, task, task.Owner)
+		err := s.TaskUpdate(ctx, task, entities.TaskUpdateFields, task.Owner)
 		assert.NoError(t, err)
 	})
 
@@ -227,10 +363,259 @@ func TestTaskUpdating(t *testing.T) {
 		}
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(entities.Task{ID: task.ID, Owner: task.Owner, Access: entities.AccessOwner}, nil)
 		storageMock.On("TaskUpdate", ctx, task, task.Owner).Return(fmt.Errorf("error"))
 		s := service.New(storageMock, new(MockedTgClient))
 
-		err := s.TaskUpdate(ctx, task, task.Owner)
+		err := s.TaskUpdate(ctx, task, entities.TaskUpdateFields, task.Owner)
+		assert.Error(t, err)
+	})
+
+	t.Run("name only update keeps other fields", func(t *testing.T) {
+		projectID, parentID := uint64(5), uint64(7)
+		dueAt := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC)
+		current := entities.Task{
+			ID:        1,
+			Name:      "Test task",
+			Owner:     "user",
+			Access:    entities.AccessOwner,
+			Priority:  entities.PriorityP1,
+			DueAt:     &dueAt,
+			ParentID:  &parentID,
+			ProjectID: &projectID,
+			Key:       "WEB-3",
+			Assignee:  "friend",
+			Reporter:  "user",
+		}
+		updated := entities.Task{
+			ID:        1,
+			Name:      "Renamed",
+			Priority:  entities.PriorityP1,
+			DueAt:     &dueAt,
+			ParentID:  &parentID,
+			ProjectID: &projectID,
+			Assignee:  "friend",
+			Reporter:  "user",
+		}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, current.ID, current.Owner).Return(current, nil)
+		storageMock.On("UserExists", ctx, mock.Anything).Return(true, nil)
+		storageMock.On("TaskUpdate", ctx, updated, current.Owner).Return(nil)
+		tgClientMock := new(MockedTgClient)
+		s := service.New(storageMock, tgClientMock)
+
+		err := s.TaskUpdate(ctx, entities.Task{ID: 1, Name: "Renamed"}, []entities.TaskField{entities.TaskFieldName}, current.Owner)
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+		tgClientMock.AssertNotCalled(t, "SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cleared field is not kept", func(t *testing.T) {
+		parentID := uint64(7)
+		current := entities.Task{ID: 1, Name: "Test task", Owner: "user", Access: entities.AccessOwner, ParentID: &parentID, Assignee: "friend"}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, current.ID, current.Owner).Return(current, nil)
+		storageMock.On("TaskUpdate", ctx, entities.Task{ID: 1, Name: "Test task"}, current.Owner).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		fields := []entities.TaskField{entities.TaskFieldParent, entities.TaskFieldAssignee}
+		err := s.TaskUpdate(ctx, entities.Task{ID: 1}, fields, current.Owner)
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("new due date before kept start date", func(t *testing.T) {
+		startAt := time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)
+		dueAt := startAt.Add(-time.Hour)
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{ID: 1, Owner: "user", Access: entities.AccessOwner, StartAt: &startAt}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskUpdate(ctx, entities.Task{ID: 1, DueAt: &dueAt}, []entities.TaskField{entities.TaskFieldDueAt}, "user")
+		assert.ErrorIs(t, err, entities.ErrInvalidDates)
+		storageMock.AssertNotCalled(t, "TaskUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskTransition(t *testing.T) {
+	t.Run("success task transition", func(t *testing.T) {
+		task := entities.Task{
+			ID:     1,
+			Name:   "Test task",
+			Owner:  "user",
+			Access: entities.AccessOwner,
+			Status: entities.StatusTodo,
+		}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		storageMock.On("TaskStatusUpdate", ctx, entities.StatusChange{ID: task.ID, From: entities.StatusTodo, To: entities.StatusInProgress}, task.Owner).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.TaskTransition(ctx, task.ID, entities.StatusInProgress, task.Owner)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.StatusInProgress, result.Status)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("task transition not allowed by graph", func(t *testing.T) {
+		task := entities.Task{
+			ID:     1,
+			Name:   "Test task",
+			Owner:  "user",
+			Access: entities.AccessOwner,
+			Status: entities.StatusDone,
+		}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskTransition(ctx, task.ID, entities.StatusInProgress, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+		storageMock.AssertNotCalled(t, "TaskStatusUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("task transition to unknown status", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskTransition(ctx, 1, entities.TaskStatus("unknown"), "user")
+		assert.ErrorIs(t, err, entities.ErrUnknownStatus)
+		storageMock.AssertNotCalled(t, "Task", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("task transition with custom graph", func(t *testing.T) {
+		task := entities.Task{
+			ID:     1,
+			Name:   "Test task",
+			Owner:  "user",
+			Access: entities.AccessOwner,
+			Status: entities.StatusTodo,
+		}
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, task.ID, task.Owner).Return(task, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+		s.Transitions = service.TransitionGraph{entities.StatusTodo: {entities.StatusInProgress}}
+
+		_, err := s.TaskTransition(ctx, task.ID, entities.StatusDone, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+	})
+
+	t.Run("task transition with storage error", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "user").Return(entities.Task{}, fmt.Errorf("error"))
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskTransition(ctx, 1, entities.StatusDone, "user")
+		assert.Error(t, err)
+	})
+}
+
+func TestTaskDatesValidation(t *testing.T) {
+	startAt := time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2025, 5, 1, 18, 0, 0, 0, time.UTC)
+	task := entities.Task{
+		ID:      1,
+		Name:    "Test task",
+		Owner:   "user",
+		StartAt: &startAt,
+		DueAt:   &dueAt,
+	}
+
+	t.Run("task adding with due date before start date", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskAdd(ctx, task, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrInvalidDates)
+		storageMock.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("task updating with due date before start date", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskUpdate(ctx, task, entities.TaskUpdateFields, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrInvalidDates)
+		storageMock.AssertNotCalled(t, "TaskUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTasksByDueDate(t *testing.T) {
+	t.Run("success overdue tasks getting", func(t *testing.T) {
+		login := "user"
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TasksOverdue", ctx, login, mock.AnythingOfType("time.Time")).Return([]entities.Task{{ID: 1}}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		result, err := s.TasksOverdue(ctx, login)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result))
+	})
+
+	t.Run("success upcoming tasks getting", func(t *testing.T) {
+		login := "user"
+		within := 72 * time.Hour
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TasksUpcoming", ctx, login, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]entities.Task{}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TasksUpcoming(ctx, login, within)
+		assert.NoError(t, err)
+
+		from := storageMock.Calls[0].Arguments.Get(2).(time.Time)
+		to := storageMock.Calls[0].Arguments.Get(3).(time.Time)
+		assert.Equal(t, within, to.Sub(from))
+	})
+
+	t.Run("upcoming tasks getting with error", func(t *testing.T) {
+		login := "user"
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TasksUpcoming", ctx, login, mock.Anything, mock.Anything).Return([]entities.Task{}, fmt.Errorf("error"))
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TasksUpcoming(ctx, login, time.Hour)
 		assert.Error(t, err)
 	})
 }
+
+func TestTaskPriorityValidation(t *testing.T) {
+	task := entities.Task{
+		ID:       1,
+		Name:     "Test task",
+		Owner:    "user",
+		Priority: entities.TaskPriority("P9"),
+	}
+
+	t.Run("task adding with unknown priority", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TaskAdd(ctx, task, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrUnknownPriority)
+		storageMock.AssertNotCalled(t, "TaskAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("task updating with unknown priority", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskUpdate(ctx, task, entities.TaskUpdateFields, task.Owner)
+		assert.ErrorIs(t, err, entities.ErrUnknownPriority)
+		storageMock.AssertNotCalled(t, "TaskUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
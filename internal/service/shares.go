@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+// ShareStorage keeps shares of tasks and projects, the service checks that the user owns them.
+type ShareStorage interface {
+	Shares(ctx context.Context, target entities.Share) ([]entities.Share, error)
+	ShareSet(ctx context.Context, share entities.Share) error
+	ShareRemove(ctx context.Context, share entities.Share) error
+}
+
+// Shares returns shares of the task or the project set in the target, they are visible to the owner only.
+func (s *Service) Shares(ctx context.Context, target entities.Share, login string) ([]entities.Share, error) {
+	if err := s.authorizeShare(ctx, target, login); err != nil {
+		return nil, fmt.Errorf("could not get shares: %w", err)
+	}
+
+	shares, err := s.Storage.Shares(ctx, target)
+	if err != nil {
+		return shares, fmt.Errorf("could not get shares: %w", err)
+	}
+	return shares, nil
+}
+
+// ShareSet grants the share login viewer or editor access, the login must be a member of the organization
+// of the task or the project. Sharing with the owner is meaningless.
+func (s *Service) ShareSet(ctx context.Context, share entities.Share, login string) error {
+	share.Login = strings.TrimSpace(share.Login)
+	if share.Login == "" || share.Login == login {
+		return fmt.Errorf("unable to share: %w: login of another user is required", entities.ErrInvalidShare)
+	}
+	if !share.Role.Valid() {
+		return fmt.Errorf("unable to share: %w: unknown role %q", entities.ErrInvalidShare, share.Role)
+	}
+
+	if err := s.authorizeShare(ctx, share, login); err != nil {
+		return fmt.Errorf("unable to share: %w", err)
+	}
+
+	// Shared task or project is visible in the active organization only, so is the share
+	org := activeOrg(ctx)
+	if org == 0 {
+		return fmt.Errorf("unable to share: %w", entities.ErrNoOrg)
+	}
+	_, err := s.Storage.UserOrg(ctx, share.Login, org)
+	if errors.Is(err, entities.ErrNoOrg) {
+		return fmt.Errorf("unable to share: %w: %q is not a member of organization %d", entities.ErrNoUser, share.Login, org)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to share: %w", err)
+	}
+
+	if err := s.Storage.ShareSet(ctx, share); err != nil {
+		return fmt.Errorf("unable to share: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ShareRemove(ctx context.Context, share entities.Share, login string) error {
+	if err := s.authorizeShare(ctx, share, login); err != nil {
+		return fmt.Errorf("could not remove share: %w", err)
+	}
+
+	if err := s.Storage.ShareRemove(ctx, share); err != nil {
+		return fmt.Errorf("could not remove share: %w", err)
+	}
+	return nil
+}
+
+// authorizeTask returns the task when the user has at least the required access to it.
+func (s *Service) authorizeTask(ctx context.Context, id uint64, login string, required entities.Access) (entities.Task, error) {
+	task, err := s.Storage.Task(ctx, id, login)
+	if err != nil {
+		return task, err
+	}
+	if !task.Access.Includes(required) {
+		return task, fmt.Errorf("%w: %s access to task %d is required", entities.ErrForbidden, required, id)
+	}
+	return task, nil
+}
+
+// authorizeShare checks that the user owns the shared task or project.
+func (s *Service) authorizeShare(ctx context.Context, share entities.Share, login string) error {
+	if share.ProjectID != nil {
+		_, err := s.Storage.Project(ctx, *share.ProjectID, login)
+		return err
+	}
+	if share.TaskID != nil {
+		_, err := s.authorizeTask(ctx, *share.TaskID, login, entities.AccessOwner)
+		return err
+	}
+	return entities.ErrInvalidShare
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Shares(ctx context.Context, target entities.Share) ([]entities.Share, error) {
+	args := m.Called(ctx, target)
+	return args.Get(0).([]entities.Share), args.Error(1)
+}
+
+func (m *MockedStorage) ShareSet(ctx context.Context, share entities.Share) error {
+	args := m.Called(ctx, share)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) ShareRemove(ctx context.Context, share entities.Share) error {
+	args := m.Called(ctx, share)
+	return args.Error(0)
+}
+
+func TestShareSet(t *testing.T) {
+	taskID := uint64(1)
+	task := entities.Task{ID: taskID, Owner: "user", Access: entities.AccessOwner}
+	orgCtx := context.WithValue(context.Background(), entities.OrgIDKey, uint64(7))
+	member := entities.Organization{ID: 7, Role: entities.OrgMember}
+
+	t.Run("task shared by the owner", func(t *testing.T) {
+		ctx := orgCtx
+		share := entities.Share{TaskID: &taskID, Login: "friend", Role: entities.AccessEditor}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, taskID, "user").Return(task, nil)
+		storageMock.On("UserOrg", ctx, "friend", uint64(7)).Return(member, nil)
+		storageMock.On("ShareSet", ctx, share).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.ShareSet(ctx, share, "user")
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("task shared with a user outside of the organization", func(t *testing.T) {
+		ctx := orgCtx
+		share := entities.Share{TaskID: &taskID, Login: "nobody", Role: entities.AccessViewer}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, taskID, "user").Return(task, nil)
+		storageMock.On("UserOrg", ctx, "nobody", uint64(7)).Return(entities.Organization{}, entities.ErrNoOrg)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.ShareSet(ctx, share, "user")
+		assert.ErrorIs(t, err, entities.ErrNoUser)
+		storageMock.AssertNotCalled(t, "ShareSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("editor can not share the task", func(t *testing.T) {
+		ctx := orgCtx
+		share := entities.Share{TaskID: &taskID, Login: "other", Role: entities.AccessViewer}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, taskID, "friend").Return(entities.Task{ID: taskID, Owner: "user", Access: entities.AccessEditor}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.ShareSet(ctx, share, "friend")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "ShareSet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("project shared by the owner", func(t *testing.T) {
+		ctx := orgCtx
+		projectID := uint64(2)
+		share := entities.Share{ProjectID: &projectID, Login: "friend", Role: entities.AccessViewer}
+		storageMock := new(MockedStorage)
+		storageMock.On("Project", ctx, projectID, "user").Return(entities.Project{ID: projectID}, nil)
+		storageMock.On("UserOrg", ctx, "friend", uint64(7)).Return(member, nil)
+		storageMock.On("ShareSet", ctx, share).Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.ShareSet(ctx, share, "user")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid shares", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, share := range []entities.Share{
+			{TaskID: &taskID, Login: " ", Role: entities.AccessViewer},
+			{TaskID: &taskID, Login: "user", Role: entities.AccessViewer},
+			{TaskID: &taskID, Login: "friend", Role: entities.AccessOwner},
+			{TaskID: &taskID, Login: "friend", Role: "admin"},
+		} {
+			err := s.ShareSet(orgCtx, share, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidShare)
+		}
+		storageMock.AssertNotCalled(t, "ShareSet", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSharedTaskAuthorization(t *testing.T) {
+	viewed := entities.Task{ID: 1, Owner: "user", Access: entities.AccessViewer, Status: entities.StatusTodo}
+	edited := entities.Task{ID: 1, Owner: "user", Access: entities.AccessEditor, Status: entities.StatusTodo}
+
+	t.Run("viewer can not update the task", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "friend").Return(viewed, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskUpdate(ctx, entities.Task{ID: 1, Name: "Renamed"}, entities.TaskUpdateFields, "friend")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+
+		_, err = s.TaskTransition(ctx, 1, entities.StatusInProgress, "friend")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "TaskUpdate", mock.Anything, mock.Anything, mock.Anything)
+		storageMock.AssertNotCalled(t, "TaskStatusUpdate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("editor updates the task on behalf of the owner", func(t *testing.T) {
+		ctx := context.Background()
+		task := entities.Task{ID: 1, Name: "Renamed"}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "friend").Return(edited, nil)
+		storageMock.On("TaskUpdate", ctx, task, "user").Return(nil)
+		storageMock.On("TaskStatusUpdate", ctx, entities.StatusChange{ID: uint64(1), From: entities.StatusTodo, To: entities.StatusInProgress}, "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskUpdate(ctx, task, entities.TaskUpdateFields, "friend")
+		assert.NoError(t, err)
+
+		_, err = s.TaskTransition(ctx, 1, entities.StatusInProgress, "friend")
+		assert.NoError(t, err)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("editor can not remove the task", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "friend").Return(edited, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskRemove(ctx, 1, "friend", "")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "TaskRemove", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type TimeStorage interface {
+	TimerStart(ctx context.Context, taskID uint64, login string, owner string, now time.Time) (entities.TimeEntry, error)
+	TimerStop(ctx context.Context, taskID uint64, login string, now time.Time) (entities.TimeEntry, error)
+	TimeEntries(ctx context.Context, taskID uint64, login string) ([]entities.TimeEntry, error)
+	TimeEntryAdd(ctx context.Context, entry entities.TimeEntry, owner string) (uint64, error)
+	TimeEntryUpdate(ctx context.Context, entry entities.TimeEntry, login string) error
+	TimeEntryRemove(ctx context.Context, taskID uint64, id uint64, login string) error
+	TimeReport(ctx context.Context, login string, from time.Time, to time.Time, now time.Time) (entities.TimeReport, error)
+}
+
+// TimerStart starts timer of the user on the task, logging time requires editor access.
+func (s *Service) TimerStart(ctx context.Context, taskID uint64, login string) (entities.TimeEntry, error) {
+	task, err := s.authorizeTask(ctx, taskID, login, entities.AccessEditor)
+	if err != nil {
+		return entities.TimeEntry{}, fmt.Errorf("unable to start timer: %w", err)
+	}
+
+	entry, err := s.Storage.TimerStart(ctx, taskID, login, task.Owner, time.Now())
+	if err != nil {
+		return entry, fmt.Errorf("unable to start timer: %w", err)
+	}
+	return entry, nil
+}
+
+func (s *Service) TimerStop(ctx context.Context, taskID uint64, login string) (entities.TimeEntry, error) {
+	entry, err := s.Storage.TimerStop(ctx, taskID, login, time.Now())
+	if err != nil {
+		return entry, fmt.Errorf("unable to stop timer: %w", err)
+	}
+	return entry, nil
+}
+
+func (s *Service) TimeEntries(ctx context.Context, taskID uint64, login string) ([]entities.TimeEntry, error) {
+	// Check that the task exists, otherwise empty list is ambiguous
+	if _, err := s.authorizeTask(ctx, taskID, login, entities.AccessViewer); err != nil {
+		return nil, fmt.Errorf("could not get time entries: %w", err)
+	}
+
+	entries, err := s.Storage.TimeEntries(ctx, taskID, login)
+	if err != nil {
+		return nil, fmt.Errorf("could not get time entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *Service) TimeEntryAdd(ctx context.Context, entry entities.TimeEntry, login string) (uint64, error) {
+	if err := validateTimeEntry(entry); err != nil {
+		return 0, fmt.Errorf("unable to add time entry: %w", err)
+	}
+
+	task, err := s.authorizeTask(ctx, entry.TaskID, login, entities.AccessEditor)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add time entry: %w", err)
+	}
+
+	entry.Login = login
+	id, err := s.Storage.TimeEntryAdd(ctx, entry, task.Owner)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add time entry: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Service) TimeEntryUpdate(ctx context.Context, entry entities.TimeEntry, login string) error {
+	if err := validateTimeEntry(entry); err != nil {
+		return fmt.Errorf("unable to update time entry: %w", err)
+	}
+
+	if err := s.Storage.TimeEntryUpdate(ctx, entry, login); err != nil {
+		return fmt.Errorf("unable to update time entry: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) TimeEntryRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	if err := s.Storage.TimeEntryRemove(ctx, taskID, id, login); err != nil {
+		return fmt.Errorf("unable to remove time entry: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) TimeReport(ctx context.Context, login string, from time.Time, to time.Time) (entities.TimeReport, error) {
+	if !from.Before(to) {
+		return entities.TimeReport{}, fmt.Errorf("could not get time report: %w", entities.ErrInvalidPeriod)
+	}
+
+	report, err := s.Storage.TimeReport(ctx, login, from, to, time.Now())
+	if err != nil {
+		return report, fmt.Errorf("could not get time report: %w", err)
+	}
+	return report, nil
+}
+
+// validateTimeEntry checks manually logged entry, it must be finished and can not be in the future.
+func validateTimeEntry(entry entities.TimeEntry) error {
+	if entry.StartedAt.IsZero() || entry.StoppedAt == nil || !entry.StoppedAt.After(entry.StartedAt) {
+		return entities.ErrInvalidTimeEntry
+	}
+	if entry.StoppedAt.After(time.Now()) {
+		return entities.ErrInvalidTimeEntry
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) TimerStart(ctx context.Context, taskID uint64, login string, owner string, now time.Time) (entities.TimeEntry, error) {
+	args := m.Called(ctx, taskID, login, owner, now)
+	return args.Get(0).(entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockedStorage) TimerStop(ctx context.Context, taskID uint64, login string, now time.Time) (entities.TimeEntry, error) {
+	args := m.Called(ctx, taskID, login, now)
+	return args.Get(0).(entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockedStorage) TimeEntries(ctx context.Context, taskID uint64, login string) ([]entities.TimeEntry, error) {
+	args := m.Called(ctx, taskID, login)
+	return args.Get(0).([]entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockedStorage) TimeEntryAdd(ctx context.Context, entry entities.TimeEntry, owner string) (uint64, error) {
+	args := m.Called(ctx, entry, owner)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) TimeEntryUpdate(ctx context.Context, entry entities.TimeEntry, login string) error {
+	args := m.Called(ctx, entry, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TimeEntryRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	args := m.Called(ctx, taskID, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TimeReport(ctx context.Context, login string, from time.Time, to time.Time, now time.Time) (entities.TimeReport, error) {
+	args := m.Called(ctx, login, from, to, now)
+	return args.Get(0).(entities.TimeReport), args.Error(1)
+}
+
+func TestTimer(t *testing.T) {
+	t.Run("success timer starting", func(t *testing.T) {
+		ctx := context.Background()
+		entry := entities.TimeEntry{ID: 1, TaskID: 2, Login: "editor", StartedAt: time.Now()}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(2), "editor").Return(entities.Task{ID: 2, Owner: "owner", Access: entities.AccessEditor}, nil)
+		storageMock.On("TimerStart", ctx, uint64(2), "editor", "owner", mock.AnythingOfType("time.Time")).Return(entry, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		started, err := s.TimerStart(ctx, 2, "editor")
+		assert.NoError(t, err)
+		assert.Equal(t, entry, started)
+	})
+
+	t.Run("timer starting by viewer", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(2), "viewer").Return(entities.Task{ID: 2, Owner: "owner", Access: entities.AccessViewer}, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TimerStart(ctx, 2, "viewer")
+		assert.ErrorIs(t, err, entities.ErrForbidden)
+		storageMock.AssertNotCalled(t, "TimerStart", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("starting second timer", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(2), "user").Return(entities.Task{ID: 2, Owner: "user", Access: entities.AccessOwner}, nil)
+		storageMock.On("TimerStart", ctx, uint64(2), "user", "user", mock.Anything).Return(entities.TimeEntry{}, entities.ErrTimerRunning)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TimerStart(ctx, 2, "user")
+		assert.ErrorIs(t, err, entities.ErrTimerRunning)
+	})
+
+	t.Run("stopping without running timer", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TimerStop", ctx, uint64(2), "user", mock.Anything).Return(entities.TimeEntry{}, entities.ErrNoTimer)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TimerStop(ctx, 2, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTimer)
+	})
+}
+
+func TestTimeEntryAdding(t *testing.T) {
+	started := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	stopped := started.Add(90 * time.Minute)
+
+	t.Run("success entry adding", func(t *testing.T) {
+		ctx := context.Background()
+		entry := entities.TimeEntry{TaskID: 1, StartedAt: started, StoppedAt: &stopped, Note: "review"}
+		stored := entities.TimeEntry{TaskID: 1, Login: "editor", StartedAt: started, StoppedAt: &stopped, Note: "review"}
+		storageMock := new(MockedStorage)
+		storageMock.On("Task", ctx, uint64(1), "editor").Return(entities.Task{ID: 1, Owner: "owner", Access: entities.AccessEditor}, nil)
+		storageMock.On("TimeEntryAdd", ctx, stored, "owner").Return(uint64(5), nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		id, err := s.TimeEntryAdd(ctx, entry, "editor")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), id)
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		for _, entry := range []entities.TimeEntry{
+			{TaskID: 1, StartedAt: started},
+			{TaskID: 1, StartedAt: stopped, StoppedAt: &started},
+			{TaskID: 1, StartedAt: started, StoppedAt: &started},
+			{TaskID: 1, StartedAt: started, StoppedAt: &future},
+		} {
+			_, err := s.TimeEntryAdd(context.Background(), entry, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidTimeEntry)
+		}
+		storageMock.AssertNotCalled(t, "TimeEntryAdd", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTimeReport(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("success report", func(t *testing.T) {
+		ctx := context.Background()
+		report := entities.TimeReport{From: from, To: to, Total: 1.5, Tasks: []entities.TimeReportRow{{ID: 1, Name: "task", Hours: 1.5}}}
+		storageMock := new(MockedStorage)
+		storageMock.On("TimeReport", ctx, "user", from, to, mock.AnythingOfType("time.Time")).Return(report, nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		res, err := s.TimeReport(ctx, "user", from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, report, res)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		_, err := s.TimeReport(context.Background(), "user", to, from)
+		assert.ErrorIs(t, err, entities.ErrInvalidPeriod)
+		storageMock.AssertNotCalled(t, "TimeReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+// TransitionGraph describes which statuses a task may be moved to from its current status.
+type TransitionGraph map[entities.TaskStatus][]entities.TaskStatus
+
+var DefaultTransitions = TransitionGraph{
+	entities.StatusTodo:       {entities.StatusInProgress, entities.StatusBlocked, entities.StatusDone, entities.StatusCancelled},
+	entities.StatusInProgress: {entities.StatusTodo, entities.StatusBlocked, entities.StatusDone, entities.StatusCancelled},
+	entities.StatusBlocked:    {entities.StatusTodo, entities.StatusInProgress, entities.StatusCancelled},
+	entities.StatusDone:       {entities.StatusTodo},
+	entities.StatusCancelled:  {entities.StatusTodo},
+}
+
+func (g TransitionGraph) Allowed(from entities.TaskStatus, to entities.TaskStatus) bool {
+	for _, status := range g[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTransitionGraph parses graph in format "from:to1,to2;from2:to3".
+func ParseTransitionGraph(s string) (TransitionGraph, error) {
+	graph := TransitionGraph{}
+
+	for _, rule := range strings.Split(s, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		from, targets, ok := strings.Cut(rule, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid transition rule %q", rule)
+		}
+
+		fromStatus := entities.TaskStatus(strings.TrimSpace(from))
+		if !fromStatus.Valid() {
+			return nil, fmt.Errorf("invalid transition rule %q: %w", rule, entities.ErrUnknownStatus)
+		}
+
+		for _, to := range strings.Split(targets, ",") {
+			toStatus := entities.TaskStatus(strings.TrimSpace(to))
+			if !toStatus.Valid() {
+				return nil, fmt.Errorf("invalid transition rule %q: %w", rule, entities.ErrUnknownStatus)
+			}
+			graph[fromStatus] = append(graph[fromStatus], toStatus)
+		}
+	}
+
+	return graph, nil
+}
@@ -0,0 +1,37 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func TestParseTransitionGraph(t *testing.T) {
+	t.Run("success parsing", func(t *testing.T) {
+		graph, err := service.ParseTransitionGraph("todo:in_progress,done; in_progress:done")
+		assert.NoError(t, err)
+		assert.True(t, graph.Allowed(entities.StatusTodo, entities.StatusInProgress))
+		assert.True(t, graph.Allowed(entities.StatusTodo, entities.StatusDone))
+		assert.True(t, graph.Allowed(entities.StatusInProgress, entities.StatusDone))
+		assert.False(t, graph.Allowed(entities.StatusDone, entities.StatusTodo))
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		_, err := service.ParseTransitionGraph("todo:finished")
+		assert.ErrorIs(t, err, entities.ErrUnknownStatus)
+	})
+
+	t.Run("malformed rule", func(t *testing.T) {
+		_, err := service.ParseTransitionGraph("todo")
+		assert.Error(t, err)
+	})
+}
+
+func TestDefaultTransitions(t *testing.T) {
+	assert.True(t, service.DefaultTransitions.Allowed(entities.StatusTodo, entities.StatusInProgress))
+	assert.True(t, service.DefaultTransitions.Allowed(entities.StatusDone, entities.StatusTodo))
+	assert.False(t, service.DefaultTransitions.Allowed(entities.StatusCancelled, entities.StatusDone))
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+type TrashStorage interface {
+	Trash(ctx context.Context, login string) ([]entities.Task, error)
+	TaskRestore(ctx context.Context, id uint64, login string) error
+	TaskPurge(ctx context.Context, id uint64, login string) error
+	TrashPurge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Trash returns removed tasks of the user, only owners remove tasks so nothing shared is listed.
+func (s *Service) Trash(ctx context.Context, login string) ([]entities.Task, error) {
+	tasks, err := s.Storage.Trash(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("could not get trash: %w", err)
+	}
+	return tasks, nil
+}
+
+// TaskRestore takes the task out of trash unless a board column of its status is full.
+func (s *Service) TaskRestore(ctx context.Context, id uint64, login string) error {
+	if err := s.Storage.TaskRestore(ctx, id, login); err != nil {
+		return fmt.Errorf("could not restore task: %w", err)
+	}
+	return nil
+}
+
+// TaskPurge removes the trashed task permanently.
+func (s *Service) TaskPurge(ctx context.Context, id uint64, login string) error {
+	if err := s.Storage.TaskPurge(ctx, id, login); err != nil {
+		return fmt.Errorf("could not purge task: %w", err)
+	}
+	return nil
+}
+
+// TrashPurge permanently removes tasks which stay in trash longer than the retention period.
+func (s *Service) TrashPurge(ctx context.Context, retention time.Duration) (int64, error) {
+	count, err := s.Storage.TrashPurge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("could not purge trash: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/service"
+)
+
+func (m *MockedStorage) Trash(ctx context.Context, login string) ([]entities.Task, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockedStorage) TaskRestore(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TaskPurge(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TrashPurge(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestTaskRestore(t *testing.T) {
+	t.Run("task restored", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskRestore", ctx, uint64(1), "user").Return(nil)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskRestore(ctx, 1, "user")
+		assert.NoError(t, err)
+	})
+
+	t.Run("task is not in trash", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskRestore", ctx, uint64(1), "user").Return(entities.ErrNoTask)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskRestore(ctx, 1, "user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+	})
+
+	t.Run("column of the task status is full", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("TaskRestore", ctx, uint64(1), "user").Return(entities.ErrWIPLimit)
+		s := service.New(storageMock, new(MockedTgClient))
+
+		err := s.TaskRestore(ctx, 1, "user")
+		assert.ErrorIs(t, err, entities.ErrWIPLimit)
+	})
+}
+
+func TestTrashPurge(t *testing.T) {
+	ctx := context.Background()
+	retention := 48 * time.Hour
+	start := time.Now()
+	storageMock := new(MockedStorage)
+	storageMock.On("TrashPurge", ctx, mock.MatchedBy(func(before time.Time) bool {
+		return !before.Before(start.Add(-retention)) && !before.After(time.Now().Add(-retention))
+	})).Return(int64(3), nil)
+	s := service.New(storageMock, new(MockedTgClient))
+
+	count, err := s.TrashPurge(ctx, retention)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
@@ -2,27 +2,161 @@ package users
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
 )
 
 type UserStorage interface {
-	GetUserLogin(ctx context.Context, token string) (string, error)
+	GetUserLogin(ctx context.Context, prefix string, hash string) (string, []entities.Scope, error)
+	UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error)
+	Tokens(ctx context.Context, login string) ([]entities.AccessToken, error)
+	TokenAdd(ctx context.Context, token entities.AccessToken, hash string, login string) (uint64, error)
+	TokenRevoke(ctx context.Context, id uint64, login string) error
+	PlainTokens(ctx context.Context) (map[uint64]string, error)
+	TokenHashSet(ctx context.Context, id uint64, prefix string, hash string) error
+	TokenRehash(ctx context.Context, token string, prefix string, hash string) error
 }
 
-func New(storage UserStorage) *UserService {
+// Issued tokens look like wpt_<prefix>_<secret>, the prefix is stored as is to find the token
+// and to tell tokens apart, the whole token is stored as a keyed hash only.
+const (
+	maxTokenNameLength = 128
+	tokenScheme        = "wpt_"
+	tokenPrefixBytes   = 6
+	tokenSecretBytes   = 32
+)
+
+var tokenPrefixLength = len(tokenScheme) + base64.RawURLEncoding.EncodedLen(tokenPrefixBytes)
+
+// New creates the service, hashKey is the secret key of access token hashes.
+func New(storage UserStorage, hashKey []byte) *UserService {
 	return &UserService{
 		Storage: storage,
+		HashKey: hashKey,
 	}
 }
 
 type UserService struct {
 	Storage UserStorage
+	HashKey []byte
 }
 
-func (s *UserService) GetUserLogin(ctx context.Context, token string) (string, error) {
-	login, err := s.Storage.GetUserLogin(ctx, token)
+// GetUserLogin returns the login of the token owner with the scopes of the token. Plaintext token stored
+// after the startup rehash is hashed on its first use.
+func (s *UserService) GetUserLogin(ctx context.Context, token string) (string, []entities.Scope, error) {
+	prefix, hash := tokenPrefix(token), s.tokenHash(token)
+	login, scopes, err := s.Storage.GetUserLogin(ctx, prefix, hash)
+	if errors.Is(err, entities.ErrNoToken) && s.Storage.TokenRehash(ctx, token, prefix, hash) == nil {
+		login, scopes, err = s.Storage.GetUserLogin(ctx, prefix, hash)
+	}
 	if err != nil {
-		return login, fmt.Errorf("could not auth user: %w", err)
+		return login, nil, fmt.Errorf("could not auth user: %w", err)
+	}
+	return login, scopes, nil
+}
+
+// UserOrg returns the organization the user works in, zero id selects the personal organization of the user.
+func (s *UserService) UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error) {
+	org, err := s.Storage.UserOrg(ctx, login, id)
+	if err != nil {
+		return org, fmt.Errorf("could not get user organization: %w", err)
+	}
+	return org, nil
+}
+
+func (s *UserService) Tokens(ctx context.Context, login string) ([]entities.AccessToken, error) {
+	tokens, err := s.Storage.Tokens(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("could not get access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// TokenAdd issues a new random access token of the user and returns its id and the token itself,
+// which is not shown again. Token without expiry time lives for the default lifetime,
+// token without scopes is read-only.
+func (s *UserService) TokenAdd(ctx context.Context, token entities.AccessToken, login string) (uint64, string, error) {
+	token.Name = strings.TrimSpace(token.Name)
+	if token.Name == "" || utf8.RuneCountInString(token.Name) > maxTokenNameLength {
+		return 0, "", fmt.Errorf("could not add access token: %w", entities.ErrInvalidToken)
+	}
+
+	if len(token.Scopes) == 0 {
+		token.Scopes = []entities.Scope{entities.ScopeRead}
+	}
+	for _, scope := range token.Scopes {
+		if !scope.Valid() {
+			return 0, "", fmt.Errorf("could not add access token: %w: unknown scope %q", entities.ErrInvalidToken, scope)
+		}
+	}
+
+	now := time.Now()
+	if token.ExpiresAt == nil {
+		expiresAt := now.Add(entities.DefaultTokenLifetime)
+		token.ExpiresAt = &expiresAt
 	}
-	return login, nil
+	if !token.ExpiresAt.After(now) || token.ExpiresAt.After(now.Add(entities.MaxTokenLifetime)) {
+		return 0, "", fmt.Errorf("could not add access token: %w", entities.ErrInvalidToken)
+	}
+
+	buf := make([]byte, tokenPrefixBytes+tokenSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return 0, "", fmt.Errorf("could not generate access token: %w", err)
+	}
+	secret := tokenScheme + base64.RawURLEncoding.EncodeToString(buf[:tokenPrefixBytes]) +
+		"_" + base64.RawURLEncoding.EncodeToString(buf[tokenPrefixBytes:])
+	token.Prefix = tokenPrefix(secret)
+
+	id, err := s.Storage.TokenAdd(ctx, token, s.tokenHash(secret), login)
+	if err != nil {
+		return 0, "", fmt.Errorf("could not add access token: %w", err)
+	}
+	return id, secret, nil
+}
+
+func (s *UserService) TokenRevoke(ctx context.Context, id uint64, login string) error {
+	if err := s.Storage.TokenRevoke(ctx, id, login); err != nil {
+		return fmt.Errorf("could not revoke access token: %w", err)
+	}
+	return nil
+}
+
+// RehashTokens replaces access tokens still stored as plaintext with their keyed hashes,
+// the tokens keep working for clients. It returns the number of rehashed tokens.
+func (s *UserService) RehashTokens(ctx context.Context) (int, error) {
+	tokens, err := s.Storage.PlainTokens(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not rehash access tokens: %w", err)
+	}
+
+	for id, token := range tokens {
+		if err := s.Storage.TokenHashSet(ctx, id, tokenPrefix(token), s.tokenHash(token)); err != nil {
+			return 0, fmt.Errorf("could not rehash access token %d: %w", id, err)
+		}
+	}
+	return len(tokens), nil
+}
+
+// tokenPrefix returns the public prefix of the issued token, tokens of other shapes have none.
+func tokenPrefix(token string) string {
+	if len(token) <= tokenPrefixLength || !strings.HasPrefix(token, tokenScheme) || token[tokenPrefixLength] != '_' {
+		return ""
+	}
+	return token[:tokenPrefixLength]
+}
+
+func (s *UserService) tokenHash(token string) string {
+	mac := hmac.New(sha256.New, s.HashKey)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
 }
@@ -2,12 +2,17 @@ package users_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/go-code-mentor/wp-task/internal/entities"
 	"github.com/go-code-mentor/wp-task/internal/service/users"
 )
 
@@ -15,9 +20,53 @@ type MockedStorage struct {
 	mock.Mock
 }
 
-func (m *MockedStorage) GetUserLogin(ctx context.Context, token string) (string, error) {
-	args := m.Called(ctx, token)
-	return args.Get(0).(string), args.Error(1)
+func (m *MockedStorage) GetUserLogin(ctx context.Context, prefix string, hash string) (string, []entities.Scope, error) {
+	args := m.Called(ctx, prefix, hash)
+	return args.Get(0).(string), args.Get(1).([]entities.Scope), args.Error(2)
+}
+
+func (m *MockedStorage) UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error) {
+	args := m.Called(ctx, login, id)
+	return args.Get(0).(entities.Organization), args.Error(1)
+}
+
+func (m *MockedStorage) Tokens(ctx context.Context, login string) ([]entities.AccessToken, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).([]entities.AccessToken), args.Error(1)
+}
+
+func (m *MockedStorage) TokenAdd(ctx context.Context, token entities.AccessToken, hash string, login string) (uint64, error) {
+	args := m.Called(ctx, token, hash, login)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockedStorage) PlainTokens(ctx context.Context) (map[uint64]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[uint64]string), args.Error(1)
+}
+
+func (m *MockedStorage) TokenHashSet(ctx context.Context, id uint64, prefix string, hash string) error {
+	args := m.Called(ctx, id, prefix, hash)
+	return args.Error(0)
+}
+
+func (m *MockedStorage) TokenRehash(ctx context.Context, token string, prefix string, hash string) error {
+	args := m.Called(ctx, token, prefix, hash)
+	return args.Error(0)
+}
+
+var hashKey = []byte("0123456789abcdef0123456789abcdef")
+
+// hash is the keyed hash of the token the way the service computes it
+func hash(token string) string {
+	mac := hmac.New(sha256.New, hashKey)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (m *MockedStorage) TokenRevoke(ctx context.Context, id uint64, login string) error {
+	args := m.Called(ctx, id, login)
+	return args.Error(0)
 }
 
 func TestGetUserLogin(t *testing.T) {
@@ -26,23 +75,183 @@ func TestGetUserLogin(t *testing.T) {
 		login := "user"
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
-		storageMock.On("GetUserLogin", ctx, token).Return(login, nil)
-		s := users.New(storageMock)
+		scopes := []entities.Scope{entities.ScopeRead}
+		storageMock.On("GetUserLogin", ctx, "", hash(token)).Return(login, scopes, nil)
+		s := users.New(storageMock, hashKey)
 
-		result, err := s.GetUserLogin(ctx, token)
+		result, resultScopes, err := s.GetUserLogin(ctx, token)
 		assert.NoError(t, err)
 		assert.Equal(t, login, result)
+		assert.Equal(t, scopes, resultScopes)
 	})
 
 	t.Run("login getting with error", func(t *testing.T) {
 		token := "123"
 		ctx := context.Background()
 		storageMock := new(MockedStorage)
-		storageMock.On("GetUserLogin", ctx, token).Return("", fmt.Errorf("error"))
-		s := users.New(storageMock)
+		storageMock.On("GetUserLogin", ctx, "", hash(token)).Return("", []entities.Scope(nil), fmt.Errorf("error"))
+		s := users.New(storageMock, hashKey)
 
-		_, err := s.GetUserLogin(ctx, token)
+		_, _, err := s.GetUserLogin(ctx, token)
 		assert.Error(t, err)
 	})
 
+	t.Run("plaintext token is hashed on first use", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("GetUserLogin", ctx, "", hash("legacy")).Return("", []entities.Scope(nil), entities.ErrNoToken).Once()
+		storageMock.On("TokenRehash", ctx, "legacy", "", hash("legacy")).Return(nil)
+		storageMock.On("GetUserLogin", ctx, "", hash("legacy")).Return("user", []entities.Scope{entities.ScopeRead}, nil).Once()
+		s := users.New(storageMock, hashKey)
+
+		result, _, err := s.GetUserLogin(ctx, "legacy")
+		assert.NoError(t, err)
+		assert.Equal(t, "user", result)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("GetUserLogin", ctx, "", hash("unknown")).Return("", []entities.Scope(nil), entities.ErrNoToken)
+		storageMock.On("TokenRehash", ctx, "unknown", "", hash("unknown")).Return(entities.ErrNoToken)
+		s := users.New(storageMock, hashKey)
+
+		_, _, err := s.GetUserLogin(ctx, "unknown")
+		assert.ErrorIs(t, err, entities.ErrNoToken)
+		storageMock.AssertNumberOfCalls(t, "GetUserLogin", 1)
+	})
+
+	t.Run("issued token is found by its prefix", func(t *testing.T) {
+		token := "wpt_AbCd-_12_secret"
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("GetUserLogin", ctx, "wpt_AbCd-_12", hash(token)).Return("user", []entities.Scope{entities.ScopeAdmin}, nil)
+		s := users.New(storageMock, hashKey)
+
+		result, _, err := s.GetUserLogin(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "user", result)
+	})
+}
+
+func TestUserOrg(t *testing.T) {
+	t.Run("success organization getting", func(t *testing.T) {
+		ctx := context.Background()
+		org := entities.Organization{ID: 1, Name: "team", Role: entities.OrgAdmin}
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(1)).Return(org, nil)
+		s := users.New(storageMock, hashKey)
+
+		result, err := s.UserOrg(ctx, "user", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, org, result)
+	})
+
+	t.Run("organization getting with error", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("UserOrg", ctx, "user", uint64(1)).Return(entities.Organization{}, entities.ErrNoOrg)
+		s := users.New(storageMock, hashKey)
+
+		_, err := s.UserOrg(ctx, "user", 1)
+		assert.ErrorIs(t, err, entities.ErrNoOrg)
+	})
+}
+
+func TestTokenAdd(t *testing.T) {
+	t.Run("token issued read-only with default expiry", func(t *testing.T) {
+		ctx := context.Background()
+		var stored entities.AccessToken
+		var storedHash string
+		storageMock := new(MockedStorage)
+		storageMock.On("TokenAdd", ctx, mock.Anything, mock.Anything, "user").Run(func(args mock.Arguments) {
+			stored = args.Get(1).(entities.AccessToken)
+			storedHash = args.Get(2).(string)
+		}).Return(uint64(5), nil)
+		s := users.New(storageMock, hashKey)
+
+		id, issued, err := s.TokenAdd(ctx, entities.AccessToken{Name: " ci "}, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), id)
+		assert.Equal(t, hash(issued), storedHash)
+		assert.Len(t, issued, 56)
+		assert.Equal(t, issued[:12], stored.Prefix)
+		assert.Equal(t, "wpt_", stored.Prefix[:4])
+		assert.Equal(t, "ci", stored.Name)
+		assert.Equal(t, []entities.Scope{entities.ScopeRead}, stored.Scopes)
+		if assert.NotNil(t, stored.ExpiresAt) {
+			assert.WithinDuration(t, time.Now().Add(entities.DefaultTokenLifetime), *stored.ExpiresAt, time.Minute)
+		}
+	})
+
+	t.Run("tokens are random", func(t *testing.T) {
+		storageMock := new(MockedStorage)
+		storageMock.On("TokenAdd", mock.Anything, mock.Anything, mock.Anything, "user").Return(uint64(1), nil)
+		s := users.New(storageMock, hashKey)
+
+		_, first, err := s.TokenAdd(context.Background(), entities.AccessToken{Name: "ci"}, "user")
+		assert.NoError(t, err)
+		_, second, err := s.TokenAdd(context.Background(), entities.AccessToken{Name: "ci"}, "user")
+		assert.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	past := time.Now().Add(-time.Hour)
+	far := time.Now().Add(entities.MaxTokenLifetime + time.Hour)
+	invalid := []struct {
+		name  string
+		token entities.AccessToken
+	}{
+		{"empty name", entities.AccessToken{Name: " "}},
+		{"expiry in the past", entities.AccessToken{Name: "ci", ExpiresAt: &past}},
+		{"expiry too far", entities.AccessToken{Name: "ci", ExpiresAt: &far}},
+		{"unknown scope", entities.AccessToken{Name: "ci", Scopes: []entities.Scope{"tasks:delete"}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := new(MockedStorage)
+			s := users.New(storageMock, hashKey)
+
+			_, _, err := s.TokenAdd(context.Background(), tt.token, "user")
+			assert.ErrorIs(t, err, entities.ErrInvalidToken)
+			storageMock.AssertNotCalled(t, "TokenAdd", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTokenRevoke(t *testing.T) {
+	ctx := context.Background()
+	storageMock := new(MockedStorage)
+	storageMock.On("TokenRevoke", ctx, uint64(1), "user").Return(entities.ErrNoToken)
+	s := users.New(storageMock, hashKey)
+
+	err := s.TokenRevoke(ctx, 1, "user")
+	assert.ErrorIs(t, err, entities.ErrNoToken)
+}
+
+func TestRehashTokens(t *testing.T) {
+	t.Run("plaintext tokens are hashed", func(t *testing.T) {
+		ctx := context.Background()
+		token := "wpt_AbCd-_12_secret"
+		storageMock := new(MockedStorage)
+		storageMock.On("PlainTokens", ctx).Return(map[uint64]string{1: "legacy", 2: token}, nil)
+		storageMock.On("TokenHashSet", ctx, uint64(1), "", hash("legacy")).Return(nil)
+		storageMock.On("TokenHashSet", ctx, uint64(2), "wpt_AbCd-_12", hash(token)).Return(nil)
+		s := users.New(storageMock, hashKey)
+
+		count, err := s.RehashTokens(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		storageMock.AssertExpectations(t)
+	})
+
+	t.Run("storage error is returned", func(t *testing.T) {
+		ctx := context.Background()
+		storageMock := new(MockedStorage)
+		storageMock.On("PlainTokens", ctx).Return(map[uint64]string(nil), fmt.Errorf("error"))
+		s := users.New(storageMock, hashKey)
+
+		_, err := s.RehashTokens(ctx)
+		assert.Error(t, err)
+	})
 }
@@ -0,0 +1,53 @@
+package storage_test
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestAssignees() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks, users RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	_, err := suite.conn.Exec(suite.ctx, "INSERT INTO users (login) VALUES ('test-user-2')")
+	assert.NoError(t, err)
+	exists, err := suite.storage.UserExists(suite.ctx, "test-user-2")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = suite.storage.UserExists(suite.ctx, "nobody")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	id, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "assigned", Status: entities.StatusTodo, Assignee: "test-user-2"}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "own", Status: entities.StatusTodo}, "test-user")
+	assert.NoError(t, err)
+
+	// Owner reports the task by default, assignee sees it as an editor
+	task, err := suite.storage.Task(suite.ctx, id, "test-user-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-user", task.Reporter)
+	assert.Equal(t, entities.AccessEditor, task.Access)
+
+	page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{Assignee: "test-user-2"}, entities.PageRequest{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(page.Tasks)) {
+		assert.Equal(t, id, page.Tasks[0].ID)
+	}
+
+	// Unassigned task is no longer visible to the former assignee
+	task.Assignee = ""
+	err = suite.storage.TaskUpdate(suite.ctx, task, "test-user")
+	assert.NoError(t, err)
+	task, err = suite.storage.Task(suite.ctx, id, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "", task.Assignee)
+	assert.Equal(t, "test-user", task.Reporter)
+	_, err = suite.storage.Task(suite.ctx, id, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+}
@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+const boardColumnColumns = `c.id, c.board_id, c.name, c.status, c.position, c.wip_limit`
+
+type BoardSQL struct {
+	ID        uint64  `db:"id"`
+	Owner     string  `db:"owner"`
+	Name      string  `db:"name"`
+	ProjectID *uint64 `db:"project_id"`
+}
+
+// Convert DTO to entity
+func (b BoardSQL) entity() entities.Board {
+	return entities.Board{
+		ID:        b.ID,
+		Owner:     b.Owner,
+		Name:      b.Name,
+		ProjectID: b.ProjectID,
+	}
+}
+
+type BoardColumnSQL struct {
+	ID       uint64 `db:"id"`
+	BoardID  uint64 `db:"board_id"`
+	Name     string `db:"name"`
+	Status   string `db:"status"`
+	Position int    `db:"position"`
+	WIPLimit *int   `db:"wip_limit"`
+}
+
+// Convert DTO to entity
+func (c BoardColumnSQL) entity() entities.BoardColumn {
+	return entities.BoardColumn{
+		ID:       c.ID,
+		BoardID:  c.BoardID,
+		Name:     c.Name,
+		Status:   entities.TaskStatus(c.Status),
+		Position: c.Position,
+		WIPLimit: c.WIPLimit,
+	}
+}
+
+// Boards returns user boards without their columns.
+func (s *Storage) Boards(ctx context.Context, login string) ([]entities.Board, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id, owner, name, project_id FROM boards WHERE owner=$1 AND org_id=$2 ORDER BY id`
+	rows, err := s.conn.Query(c, query, login, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query boards from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	boardsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[BoardSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	boards := make([]entities.Board, len(boardsSQL))
+	for i := range boardsSQL {
+		boards[i] = boardsSQL[i].entity()
+	}
+
+	return boards, nil
+}
+
+// Board returns the board with its columns ordered by position, columns have no tasks loaded.
+func (s *Storage) Board(ctx context.Context, id uint64, login string) (entities.Board, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id, owner, name, project_id FROM boards WHERE id=$1 AND owner=$2 AND org_id=$3`
+	rows, err := s.conn.Query(c, query, id, login, orgID(ctx))
+	if err != nil {
+		return entities.Board{}, fmt.Errorf("unable to get board from storage: %w", err)
+	}
+	boardSQL, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[BoardSQL])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Board{}, fmt.Errorf("unable to get board from storage: %w", entities.ErrNoBoard)
+	}
+	if err != nil {
+		return entities.Board{}, fmt.Errorf("unable to get board from storage: %w", err)
+	}
+	board := boardSQL.entity()
+
+	query = `SELECT ` + boardColumnColumns + ` FROM board_columns c WHERE c.board_id=$1 ORDER BY c.position`
+	if board.Columns, err = s.queryBoardColumns(c, query, id); err != nil {
+		return entities.Board{}, fmt.Errorf("unable to get board columns from storage: %w", err)
+	}
+
+	return board, nil
+}
+
+// BoardAdd creates the board with its columns, column positions follow their order.
+func (s *Storage) BoardAdd(ctx context.Context, board entities.Board, login string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return 0, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Run SQL query, board may show only projects of the same user
+	var boardID uint64
+	query := `INSERT INTO boards (owner, name, project_id, org_id)
+		SELECT $1, $2, $3, $4 WHERE $3::bigint IS NULL OR EXISTS (SELECT 1 FROM projects WHERE id=$3 AND owner=$1 AND org_id=$4)
+		RETURNING id`
+	err = tx.QueryRow(c, query, login, board.Name, board.ProjectID, orgID(ctx)).Scan(&boardID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("unable to add board to storage: %w", entities.ErrNoProject)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to add board to storage: %w", err)
+	}
+
+	for i, column := range board.Columns {
+		query := `INSERT INTO board_columns (board_id, name, status, position, wip_limit) VALUES ($1, $2, $3, $4, $5)`
+		_, err := tx.Exec(c, query, boardID, column.Name, string(column.Status), i, column.WIPLimit)
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("unable to add board column to storage: %w", entities.ErrInvalidBoard)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("unable to add board column to storage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return 0, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return boardID, nil
+}
+
+func (s *Storage) BoardRemove(ctx context.Context, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query, columns are removed by cascade
+	query := `DELETE FROM boards WHERE id=$1 AND owner=$2 AND org_id=$3`
+	row, err := s.conn.Exec(c, query, id, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to remove board from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove board from storage: %w", entities.ErrNoBoard)
+	}
+
+	return nil
+}
+
+func (s *Storage) BoardColumn(ctx context.Context, id uint64, login string) (entities.BoardColumn, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT ` + boardColumnColumns + ` FROM board_columns c
+		JOIN boards b ON b.id = c.board_id WHERE c.id=$1 AND b.owner=$2 AND b.org_id=$3`
+	columns, err := s.queryBoardColumns(c, query, id, login, orgID(ctx))
+	if err != nil {
+		return entities.BoardColumn{}, fmt.Errorf("unable to get board column from storage: %w", err)
+	}
+	if len(columns) == 0 {
+		return entities.BoardColumn{}, fmt.Errorf("unable to get board column from storage: %w", entities.ErrNoColumn)
+	}
+
+	return columns[0], nil
+}
+
+// BoardColumnUpdate changes column name and WIP limit, the status is kept.
+func (s *Storage) BoardColumnUpdate(ctx context.Context, column entities.BoardColumn, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE board_columns c SET name = $1, wip_limit = $2
+		FROM boards b WHERE b.id = c.board_id AND c.id = $3 AND c.board_id = $4 AND b.owner = $5 AND b.org_id = $6`
+	row, err := s.conn.Exec(c, query, column.Name, column.WIPLimit, column.ID, column.BoardID, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to update board column in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to update board column in storage: %w", entities.ErrNoColumn)
+	}
+
+	return nil
+}
+
+// BoardTasks returns tasks shown on the board ordered by rank, tasks without rank follow in order of creation.
+func (s *Storage) BoardTasks(ctx context.Context, board entities.Board, login string) ([]entities.Task, error) {
+	statuses := make([]string, len(board.Columns))
+	for i, column := range board.Columns {
+		statuses[i] = string(column.Status)
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE owner=$1 AND org_id=$4 AND deleted_at IS NULL AND status = ANY($2) AND ($3::bigint IS NULL OR project_id = $3)
+		ORDER BY rank NULLS LAST, id`
+	tasks, err := s.queryTasks(ctx, query, login, statuses, board.ProjectID, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get board tasks from storage: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// checkWIPLimits rejects the task in the status when a column of the user boards showing it reached
+// its WIP limit without counting the task itself. The columns are locked until the transaction ends,
+// concurrent transactions moving tasks into them wait and count the tasks committed meanwhile.
+func checkWIPLimits(ctx context.Context, q querier, id uint64, projectID *uint64, status entities.TaskStatus, login string) error {
+	query := `SELECT c.id FROM board_columns c
+		JOIN boards b ON b.id = c.board_id
+		WHERE b.owner = $1 AND b.org_id = $4 AND c.status = $2 AND c.wip_limit IS NOT NULL
+			AND (b.project_id IS NULL OR b.project_id = $3)
+		ORDER BY c.id FOR UPDATE OF c`
+	if _, err := q.Exec(ctx, query, login, string(status), projectID, orgID(ctx)); err != nil {
+		return fmt.Errorf("unable to lock board columns: %w", err)
+	}
+
+	query = `SELECT c.name, c.board_id FROM board_columns c
+		JOIN boards b ON b.id = c.board_id
+		WHERE b.owner = $1 AND b.org_id = $5 AND c.status = $2 AND c.wip_limit IS NOT NULL
+			AND (b.project_id IS NULL OR b.project_id = $3)
+			AND c.wip_limit <= (SELECT count(*) FROM tasks t
+				WHERE t.owner = $1 AND t.org_id = b.org_id AND t.deleted_at IS NULL AND t.status = c.status AND t.id <> $4
+					AND (b.project_id IS NULL OR t.project_id = b.project_id))
+		ORDER BY c.id LIMIT 1`
+	var name string
+	var boardID uint64
+	err := q.QueryRow(ctx, query, login, string(status), projectID, id, orgID(ctx)).Scan(&name, &boardID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get full board columns: %w", err)
+	}
+
+	return fmt.Errorf("%w: column %q of board %d", entities.ErrWIPLimit, name, boardID)
+}
+
+// taskRanksUpdate sets ranks of the owner tasks, every task must be found.
+func taskRanksUpdate(ctx context.Context, q querier, ranks map[uint64]string, login string) error {
+	ids := make([]uint64, 0, len(ranks))
+	keys := make([]string, 0, len(ranks))
+	for id, key := range ranks {
+		ids = append(ids, id)
+		keys = append(keys, key)
+	}
+
+	query := `UPDATE tasks SET rank = u.rank
+		FROM unnest($1::bigint[], $2::text[]) AS u(id, rank)
+		WHERE tasks.id = u.id AND tasks.owner = $3 AND tasks.org_id = $4 AND tasks.deleted_at IS NULL`
+	row, err := q.Exec(ctx, query, ids, keys, login, orgID(ctx))
+	if err != nil {
+		return err
+	}
+	if row.RowsAffected() != int64(len(ranks)) {
+		return entities.ErrNoTask
+	}
+
+	return nil
+}
+
+// TaskRanksUpdate sets ranks of several tasks at once.
+func (s *Storage) TaskRanksUpdate(ctx context.Context, ranks map[uint64]string, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	if err := taskRanksUpdate(c, s.conn, ranks, login); err != nil {
+		return fmt.Errorf("unable to update task ranks in storage: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) queryBoardColumns(ctx context.Context, query string, args ...any) ([]entities.BoardColumn, error) {
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[BoardColumnSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	columns := make([]entities.BoardColumn, len(columnsSQL))
+	for i := range columnsSQL {
+		columns[i] = columnsSQL[i].entity()
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,104 @@
+package storage_test
+
+import (
+	"sync"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestBoards() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks, projects, boards RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	limit := 1
+	id, err := suite.storage.BoardAdd(suite.ctx, entities.Board{Name: "Sprint", Columns: []entities.BoardColumn{
+		{Name: "To do", Status: entities.StatusTodo},
+		{Name: "Doing", Status: entities.StatusInProgress, WIPLimit: &limit},
+	}}, "test-user")
+	assert.NoError(t, err)
+
+	board, err := suite.storage.Board(suite.ctx, id, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(board.Columns)) {
+		assert.Equal(t, entities.StatusInProgress, board.Columns[1].Status)
+		assert.Equal(t, 1, board.Columns[1].Position)
+		assert.Equal(t, &limit, board.Columns[1].WIPLimit)
+	}
+
+	_, err = suite.storage.Board(suite.ctx, id, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoBoard)
+
+	// Ranked tasks go first, the rest in order of creation
+	for _, name := range []string{"first", "second", "third"} {
+		_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: name, Status: entities.StatusTodo}, "test-user")
+		assert.NoError(t, err)
+	}
+	err = suite.storage.TaskRanksUpdate(suite.ctx, map[uint64]string{3: "i"}, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TaskRanksUpdate(suite.ctx, map[uint64]string{1: "i"}, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	tasks, err := suite.storage.BoardTasks(suite.ctx, board, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 3, len(tasks)) {
+		assert.Equal(t, uint64(3), tasks[0].ID)
+		assert.Equal(t, "i", tasks[0].Rank)
+		assert.Equal(t, uint64(1), tasks[1].ID)
+	}
+
+	// Column is full when it has as many tasks as its limit, concurrent moves do not exceed it
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			change := entities.StatusChange{ID: uint64(i + 1), From: entities.StatusTodo, To: entities.StatusInProgress}
+			errs[i] = suite.storage.TaskStatusUpdate(suite.ctx, change, "test-user")
+		}()
+	}
+	wg.Wait()
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			assert.ErrorIs(t, err, entities.ErrWIPLimit)
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+
+	// Rejected move keeps the status and ranks of the task
+	change := entities.StatusChange{ID: 3, From: entities.StatusTodo, To: entities.StatusInProgress, Ranks: map[uint64]string{3: "a"}}
+	err = suite.storage.TaskStatusUpdate(suite.ctx, change, "test-user")
+	assert.ErrorIs(t, err, entities.ErrWIPLimit)
+	task, err := suite.storage.Task(suite.ctx, 3, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.StatusTodo, task.Status)
+	assert.Equal(t, "i", task.Rank)
+	_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "fourth", Status: entities.StatusInProgress}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrWIPLimit)
+
+	// Column without WIP limit is never full
+	column := board.Columns[1]
+	column.Name = "In progress"
+	column.WIPLimit = nil
+	err = suite.storage.BoardColumnUpdate(suite.ctx, column, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TaskStatusUpdate(suite.ctx, change, "test-user")
+	assert.NoError(t, err)
+	task, err = suite.storage.Task(suite.ctx, 3, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.StatusInProgress, task.Status)
+	assert.Equal(t, "a", task.Rank)
+
+	err = suite.storage.BoardRemove(suite.ctx, id, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.BoardColumn(suite.ctx, column.ID, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoColumn)
+}
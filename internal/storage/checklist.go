@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+type ChecklistItemSQL struct {
+	ID       uint64 `db:"id"`
+	TaskID   uint64 `db:"task_id"`
+	Title    string `db:"title"`
+	Done     bool   `db:"done"`
+	Position int    `db:"position"`
+}
+
+// Convert DTO to checklist item entity
+func (i ChecklistItemSQL) entity() entities.ChecklistItem {
+	return entities.ChecklistItem{
+		ID:       i.ID,
+		TaskID:   i.TaskID,
+		Title:    i.Title,
+		Done:     i.Done,
+		Position: i.Position,
+	}
+}
+
+// Checklist returns items of the task of the owner in their order.
+func (s *Storage) Checklist(ctx context.Context, taskID uint64, owner string) ([]entities.ChecklistItem, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT i.id, i.task_id, i.title, i.done, i.position FROM checklist_items i
+		JOIN tasks t ON t.id = i.task_id
+		WHERE i.task_id=$1 AND t.owner=$2 AND t.org_id=$3 AND t.deleted_at IS NULL
+		ORDER BY i.position, i.id`
+	rows, err := s.conn.Query(c, query, taskID, owner, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query checklist from storage: %w", err)
+	}
+	defer rows.Close()
+
+	itemsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[ChecklistItemSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	items := make([]entities.ChecklistItem, len(itemsSQL))
+	for i := range itemsSQL {
+		items[i] = itemsSQL[i].entity()
+	}
+
+	return items, nil
+}
+
+// ChecklistItemAdd appends item to the end of the task checklist.
+func (s *Storage) ChecklistItemAdd(ctx context.Context, item entities.ChecklistItem, owner string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query, task row is locked so that concurrent items get distinct positions
+	var id uint64
+	query := `WITH task AS (SELECT id FROM tasks WHERE id=$1 AND owner=$3 AND org_id=$4 AND deleted_at IS NULL FOR UPDATE)
+		INSERT INTO checklist_items (task_id, title, position)
+		SELECT task.id, $2, COALESCE((SELECT max(position) FROM checklist_items WHERE task_id = task.id), 0) + 1
+		FROM task
+		RETURNING id`
+	err := s.conn.QueryRow(c, query, item.TaskID, item.Title, owner, orgID(ctx)).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("unable to add checklist item to storage: %w", entities.ErrNoTask)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to add checklist item to storage: %w", err)
+	}
+
+	return id, nil
+}
+
+// ChecklistItemToggle flips done flag of the item and returns its new state.
+func (s *Storage) ChecklistItemToggle(ctx context.Context, taskID uint64, id uint64, owner string) (entities.ChecklistItem, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE checklist_items i SET done = NOT i.done FROM tasks t
+		WHERE t.id = i.task_id AND i.id=$1 AND i.task_id=$2 AND t.owner=$3 AND t.org_id=$4 AND t.deleted_at IS NULL
+		RETURNING i.id, i.task_id, i.title, i.done, i.position`
+	rows, err := s.conn.Query(c, query, id, taskID, owner, orgID(ctx))
+	if err != nil {
+		return entities.ChecklistItem{}, fmt.Errorf("unable to toggle checklist item in storage: %w", err)
+	}
+	defer rows.Close()
+
+	itemSQL, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[ChecklistItemSQL])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.ChecklistItem{}, fmt.Errorf("unable to toggle checklist item in storage: %w", entities.ErrNoChecklistItem)
+	}
+	if err != nil {
+		return entities.ChecklistItem{}, fmt.Errorf("unable to get parse row to DTO: %w", err)
+	}
+
+	return itemSQL.entity(), nil
+}
+
+// ChecklistReorder sets positions of the task items, ids must list every item of the checklist exactly once.
+func (s *Storage) ChecklistReorder(ctx context.Context, taskID uint64, ids []uint64, owner string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Lock items of the task
+	query := `SELECT i.id FROM checklist_items i JOIN tasks t ON t.id = i.task_id
+		WHERE i.task_id=$1 AND t.owner=$2 AND t.org_id=$3 AND t.deleted_at IS NULL
+		FOR UPDATE OF i`
+	rows, err := tx.Query(c, query, taskID, owner, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to get query checklist from storage: %w", err)
+	}
+	current, err := pgx.CollectRows(rows, pgx.RowTo[uint64])
+	if err != nil {
+		return fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	slices.Sort(current)
+	if !slices.Equal(sorted, current) {
+		return fmt.Errorf("unable to reorder checklist in storage: %w", entities.ErrInvalidChecklistOrder)
+	}
+
+	// Run SQL query
+	query = `UPDATE checklist_items i SET position = o.position
+		FROM unnest($1::bigint[]) WITH ORDINALITY AS o(id, position)
+		WHERE i.id = o.id AND i.task_id = $2`
+	if _, err := tx.Exec(c, query, ids, taskID); err != nil {
+		return fmt.Errorf("unable to reorder checklist in storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) ChecklistItemRemove(ctx context.Context, taskID uint64, id uint64, owner string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `DELETE FROM checklist_items i USING tasks t
+		WHERE t.id = i.task_id AND i.id=$1 AND i.task_id=$2 AND t.owner=$3 AND t.org_id=$4 AND t.deleted_at IS NULL`
+	row, err := s.conn.Exec(c, query, id, taskID, owner, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to remove checklist item from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove checklist item from storage: %w", entities.ErrNoChecklistItem)
+	}
+
+	return nil
+}
+
+// attachChecklist loads checklist of a single task and computes its completion percentage.
+func (s *Storage) attachChecklist(ctx context.Context, task *entities.Task) error {
+	items, err := s.Checklist(ctx, task.ID, task.Owner)
+	if err != nil {
+		return err
+	}
+
+	done := 0
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+
+	if len(items) > 0 {
+		task.Checklist = items
+		task.Completion = done * 100 / len(items)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package storage_test
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestChecklist() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	query := `INSERT INTO tasks (name, description, owner) VALUES ('task', '', 'test-user')`
+	_, err := suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	var ids []uint64
+	for _, title := range []string{"first", "second", "third"} {
+		id, err := suite.storage.ChecklistItemAdd(suite.ctx, entities.ChecklistItem{TaskID: 1, Title: title}, "test-user")
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	_, err = suite.storage.ChecklistItemAdd(suite.ctx, entities.ChecklistItem{TaskID: 1, Title: "foreign"}, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	item, err := suite.storage.ChecklistItemToggle(suite.ctx, 1, ids[0], "test-user")
+	assert.NoError(t, err)
+	assert.True(t, item.Done)
+
+	_, err = suite.storage.ChecklistItemToggle(suite.ctx, 1, ids[0], "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoChecklistItem)
+
+	task, err := suite.storage.Task(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(task.Checklist))
+	assert.Equal(t, 33, task.Completion)
+
+	// Reordering requires every item exactly once
+	err = suite.storage.ChecklistReorder(suite.ctx, 1, []uint64{ids[2], ids[0]}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrInvalidChecklistOrder)
+
+	err = suite.storage.ChecklistReorder(suite.ctx, 1, []uint64{ids[2], ids[0], ids[1]}, "test-user")
+	assert.NoError(t, err)
+
+	items, err := suite.storage.Checklist(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 3, len(items)) {
+		assert.Equal(t, "third", items[0].Title)
+		assert.Equal(t, "first", items[1].Title)
+		assert.Equal(t, "second", items[2].Title)
+	}
+
+	err = suite.storage.ChecklistItemRemove(suite.ctx, 1, ids[1], "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.ChecklistItemRemove(suite.ctx, 1, ids[1], "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoChecklistItem)
+
+	task, err = suite.storage.Task(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, 50, task.Completion)
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+const commentsCursor = "comments"
+
+type CommentSQL struct {
+	ID        uint64     `db:"id"`
+	TaskID    uint64     `db:"task_id"`
+	Author    string     `db:"author"`
+	Body      string     `db:"body"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// Convert DTO to comment entity
+func (c CommentSQL) entity() entities.Comment {
+	return entities.Comment{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		Author:    c.Author,
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// Comments returns page of comments on the task of the owner, oldest first.
+func (s *Storage) Comments(ctx context.Context, taskID uint64, owner string, page entities.PageRequest) (entities.CommentPage, error) {
+	var after uint64
+	if page.Cursor != "" {
+		var err error
+		if after, err = decodeIDCursor(commentsCursor, page.Cursor); err != nil {
+			return entities.CommentPage{}, fmt.Errorf("unable to get comments from storage: %w", err)
+		}
+	}
+
+	// Fetch one extra row to know whether the next page exists
+	limit := page.Limit
+	if limit <= 0 {
+		limit = entities.DefaultPageLimit
+	}
+
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT c.id, c.task_id, c.author, c.body, c.created_at, c.updated_at FROM comments c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE c.task_id=$1 AND t.owner=$2 AND t.org_id=$5 AND t.deleted_at IS NULL AND c.id > $3
+		ORDER BY c.id LIMIT $4`
+	rows, err := s.conn.Query(c, query, taskID, owner, after, limit+1, orgID(ctx))
+	if err != nil {
+		return entities.CommentPage{}, fmt.Errorf("unable to get query comments from storage: %w", err)
+	}
+	defer rows.Close()
+
+	commentsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[CommentSQL])
+	if err != nil {
+		return entities.CommentPage{}, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	var result entities.CommentPage
+	if len(commentsSQL) > limit {
+		commentsSQL = commentsSQL[:limit]
+		result.NextCursor, err = encodeIDCursor(commentsCursor, commentsSQL[limit-1].ID)
+		if err != nil {
+			return entities.CommentPage{}, fmt.Errorf("unable to get comments from storage: %w", err)
+		}
+	}
+
+	// Convert DTO to entity
+	result.Comments = make([]entities.Comment, len(commentsSQL))
+	for i := range commentsSQL {
+		result.Comments[i] = commentsSQL[i].entity()
+	}
+
+	return result, nil
+}
+
+// CommentAdd adds comment to the task of the owner, access of the author is checked by the service.
+func (s *Storage) CommentAdd(ctx context.Context, comment entities.Comment, owner string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query, task must belong to the owner
+	var id uint64
+	query := `INSERT INTO comments (task_id, author, body)
+		SELECT id, $2, $3 FROM tasks WHERE id=$1 AND owner=$4 AND org_id=$5 AND deleted_at IS NULL
+		RETURNING id`
+	err := s.conn.QueryRow(c, query, comment.TaskID, comment.Author, comment.Body, owner, orgID(ctx)).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("unable to add comment to storage: %w", entities.ErrNoTask)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to add comment to storage: %w", err)
+	}
+
+	return id, nil
+}
+
+// CommentUpdate changes comment body, only author can edit a comment.
+func (s *Storage) CommentUpdate(ctx context.Context, comment entities.Comment, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE comments c SET body=$1, updated_at=now() FROM tasks t
+		WHERE t.id = c.task_id AND c.id=$2 AND c.task_id=$3 AND c.author=$4 AND t.org_id=$5 AND t.deleted_at IS NULL`
+	row, err := s.conn.Exec(c, query, comment.Body, comment.ID, comment.TaskID, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to update comment in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to update comment in storage: %w", entities.ErrNoComment)
+	}
+
+	return nil
+}
+
+// CommentRemove removes comment, it is allowed to the author and to the task owner.
+func (s *Storage) CommentRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `DELETE FROM comments c USING tasks t
+		WHERE t.id = c.task_id AND c.id=$1 AND c.task_id=$2 AND (c.author=$3 OR t.owner=$3) AND t.org_id=$4 AND t.deleted_at IS NULL`
+	row, err := s.conn.Exec(c, query, id, taskID, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to remove comment from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove comment from storage: %w", entities.ErrNoComment)
+	}
+
+	return nil
+}
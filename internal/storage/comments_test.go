@@ -0,0 +1,65 @@
+package storage_test
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestComments() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	query := `INSERT INTO tasks (name, description, owner) VALUES ('task', '', 'test-user')`
+	_, err := suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	for _, body := range []string{"first", "second", "third"} {
+		_, err := suite.storage.CommentAdd(suite.ctx, entities.Comment{TaskID: 1, Author: "test-user", Body: body}, "test-user")
+		assert.NoError(t, err)
+	}
+
+	_, err = suite.storage.CommentAdd(suite.ctx, entities.Comment{TaskID: 1, Author: "test-user-2", Body: "foreign"}, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	// Walk pages
+	page, err := suite.storage.Comments(suite.ctx, 1, "test-user", entities.PageRequest{Limit: 2})
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(page.Comments)) {
+		assert.Equal(t, "first", page.Comments[0].Body)
+		assert.Nil(t, page.Comments[0].UpdatedAt)
+	}
+	assert.NotEmpty(t, page.NextCursor)
+
+	page, err = suite.storage.Comments(suite.ctx, 1, "test-user", entities.PageRequest{Limit: 2, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(page.Comments)) {
+		assert.Equal(t, "third", page.Comments[0].Body)
+	}
+	assert.Empty(t, page.NextCursor)
+
+	_, err = suite.storage.Comments(suite.ctx, 1, "test-user", entities.PageRequest{Limit: 2, Cursor: "bad"})
+	assert.ErrorIs(t, err, entities.ErrInvalidCursor)
+
+	// Only author can edit
+	err = suite.storage.CommentUpdate(suite.ctx, entities.Comment{ID: 1, TaskID: 1, Body: "edited"}, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoComment)
+	err = suite.storage.CommentUpdate(suite.ctx, entities.Comment{ID: 1, TaskID: 1, Body: "edited"}, "test-user")
+	assert.NoError(t, err)
+
+	page, err = suite.storage.Comments(suite.ctx, 1, "test-user", entities.PageRequest{Limit: 1})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(page.Comments)) {
+		assert.Equal(t, "edited", page.Comments[0].Body)
+		assert.NotNil(t, page.Comments[0].UpdatedAt)
+	}
+
+	err = suite.storage.CommentRemove(suite.ctx, 1, 1, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.CommentRemove(suite.ctx, 1, 1, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoComment)
+}
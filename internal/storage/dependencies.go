@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+// TaskDependencies returns all dependency edges between tasks of the user.
+func (s *Storage) TaskDependencies(ctx context.Context, login string) ([]entities.Dependency, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT d.task_id, d.blocker_id FROM task_dependencies d
+		JOIN tasks t ON t.id = d.task_id
+		JOIN tasks b ON b.id = d.blocker_id
+		WHERE t.owner=$1 AND t.org_id=$2 AND t.deleted_at IS NULL AND b.deleted_at IS NULL`
+	rows, err := s.conn.Query(c, query, login, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query task dependencies from storage: %w", err)
+	}
+	defer rows.Close()
+
+	var dependencies []entities.Dependency
+	for rows.Next() {
+		var dependency entities.Dependency
+		if err := rows.Scan(&dependency.TaskID, &dependency.BlockerID); err != nil {
+			return nil, fmt.Errorf("unable to get parse row to DTO: %w", err)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to get query task dependencies from storage: %w", err)
+	}
+
+	return dependencies, nil
+}
+
+// TaskDependencyAdd marks the task as blocked by the blocker, both must belong to the owner.
+// Dependency which closes a cycle is rejected, adding existing dependency is not an error.
+func (s *Storage) TaskDependencyAdd(ctx context.Context, taskID uint64, blockerID uint64, owner string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Concurrent additions may close a cycle none of them sees alone, the serializable transaction
+	// of one of them fails then and is retried
+	var err error
+	for attempt := 0; attempt < maxSerializableAttempts; attempt++ {
+		if err = s.taskDependencyAdd(c, taskID, blockerID, owner); !isSerializationFailure(err) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to add task dependency to storage: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) taskDependencyAdd(ctx context.Context, taskID uint64, blockerID uint64, owner string) error {
+	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Both tasks are locked so that neither is trashed or moved while the dependency is added
+	var found int
+	query := `SELECT count(*) FROM (
+			SELECT id FROM tasks WHERE id IN ($1, $2) AND owner=$3 AND org_id=$4 AND deleted_at IS NULL
+			ORDER BY id FOR UPDATE
+		) locked`
+	if err := tx.QueryRow(ctx, query, taskID, blockerID, owner, orgID(ctx)).Scan(&found); err != nil {
+		return err
+	}
+	if found != 2 {
+		return entities.ErrNoTask
+	}
+
+	// The new edge closes a cycle if the task already blocks the blocker, edges of trashed tasks
+	// count as they come back on restore
+	var cycle bool
+	query = `WITH RECURSIVE blockers AS (
+			SELECT blocker_id AS id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocker_id FROM task_dependencies d JOIN blockers b ON d.task_id = b.id
+		)
+		SELECT EXISTS (SELECT 1 FROM blockers WHERE id = $2)`
+	if err := tx.QueryRow(ctx, query, blockerID, taskID).Scan(&cycle); err != nil {
+		return err
+	}
+	if cycle {
+		return entities.ErrDependencyCycle
+	}
+
+	query = `INSERT INTO task_dependencies (task_id, blocker_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := tx.Exec(ctx, query, taskID, blockerID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *Storage) TaskDependencyRemove(ctx context.Context, taskID uint64, blockerID uint64, owner string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `DELETE FROM task_dependencies d USING tasks t
+		WHERE t.id = d.task_id AND d.task_id=$1 AND d.blocker_id=$2 AND t.owner=$3 AND t.org_id=$4 AND t.deleted_at IS NULL`
+	row, err := s.conn.Exec(c, query, taskID, blockerID, owner, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to remove task dependency from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove task dependency from storage: %w", entities.ErrNoDependency)
+	}
+
+	return nil
+}
+
+// TaskBlockers returns tasks which block the given one.
+func (s *Storage) TaskBlockers(ctx context.Context, id uint64, login string) ([]entities.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE owner=$2 AND org_id=$3 AND deleted_at IS NULL AND id IN (SELECT blocker_id FROM task_dependencies WHERE task_id=$1)
+		ORDER BY id`
+	return s.queryTasks(ctx, query, id, login, orgID(ctx))
+}
+
+// TaskIDs returns identifiers of all user tasks without loading the tasks themselves.
+func (s *Storage) TaskIDs(ctx context.Context, login string) ([]uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id FROM tasks WHERE owner=$1 AND org_id=$2 AND deleted_at IS NULL ORDER BY id`
+	rows, err := s.conn.Query(c, query, login, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query task ids from storage: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("unable to get parse row to DTO: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to get query task ids from storage: %w", err)
+	}
+
+	return ids, nil
+}
+
+// attachDependencies loads blockers and dependents of a single task.
+func (s *Storage) attachDependencies(ctx context.Context, task *entities.Task) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query, edges to trashed tasks are skipped
+	query := `SELECT d.task_id, d.blocker_id FROM task_dependencies d
+		JOIN tasks t ON t.id = d.task_id
+		JOIN tasks b ON b.id = d.blocker_id
+		WHERE (d.task_id=$1 OR d.blocker_id=$1) AND t.deleted_at IS NULL AND b.deleted_at IS NULL
+		ORDER BY d.task_id, d.blocker_id`
+	rows, err := s.conn.Query(c, query, task.ID)
+	if err != nil {
+		return fmt.Errorf("unable to get query task dependencies from storage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dependency entities.Dependency
+		if err := rows.Scan(&dependency.TaskID, &dependency.BlockerID); err != nil {
+			return fmt.Errorf("unable to get parse row to DTO: %w", err)
+		}
+		if dependency.TaskID == task.ID {
+			task.Blockers = append(task.Blockers, dependency.BlockerID)
+		} else {
+			task.Dependents = append(task.Dependents, dependency.TaskID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to get query task dependencies from storage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package storage_test
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTaskDependencies() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	query := `INSERT INTO tasks (name, description, owner, status) VALUES
+		('task', '', 'test-user', 'todo'), ('blocker', '', 'test-user', 'done'), ('foreign', '', 'test-user-2', 'todo')`
+	_, err := suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	err = suite.storage.TaskDependencyAdd(suite.ctx, 1, 2, "test-user")
+	assert.NoError(t, err)
+
+	// Foreign task can not become a blocker
+	err = suite.storage.TaskDependencyAdd(suite.ctx, 1, 3, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	// Adding existing dependency again is not an error
+	err = suite.storage.TaskDependencyAdd(suite.ctx, 1, 2, "test-user")
+	assert.NoError(t, err)
+
+	// Dependency closing a cycle is rejected
+	err = suite.storage.TaskDependencyAdd(suite.ctx, 2, 1, "test-user")
+	assert.ErrorIs(t, err, entities.ErrDependencyCycle)
+
+	dependencies, err := suite.storage.TaskDependencies(suite.ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Dependency{{TaskID: 1, BlockerID: 2}}, dependencies)
+
+	task, err := suite.storage.Task(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2}, task.Blockers)
+
+	blocker, err := suite.storage.Task(suite.ctx, 2, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, blocker.Dependents)
+
+	blockers, err := suite.storage.TaskBlockers(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(blockers)) {
+		assert.Equal(t, entities.StatusDone, blockers[0].Status)
+	}
+
+	ids, err := suite.storage.TaskIDs(suite.ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, ids)
+
+	err = suite.storage.TaskDependencyRemove(suite.ctx, 1, 2, "test-user")
+	assert.NoError(t, err)
+
+	err = suite.storage.TaskDependencyRemove(suite.ctx, 1, 2, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoDependency)
+}
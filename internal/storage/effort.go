@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+// loggedMinutes sums time logged on the task t by all users, running timers count up to now passed as $1.
+const loggedMinutes = `(SELECT COALESCE(ROUND(SUM(EXTRACT(EPOCH FROM COALESCE(e.stopped_at, $1) - e.started_at)) / 60), 0)::integer
+		FROM time_entries e WHERE e.task_id = t.id)`
+
+type TaskEffortSQL struct {
+	TaskID            uint64  `db:"id"`
+	EstimateUnit      *string `db:"estimate_unit"`
+	OriginalEstimate  *int    `db:"original_estimate"`
+	RemainingEstimate *int    `db:"remaining_estimate"`
+	LoggedMinutes     int     `db:"logged_minutes"`
+}
+
+// Convert DTO to task effort entity
+func (e TaskEffortSQL) entity() entities.TaskEffort {
+	effort := entities.TaskEffort{
+		TaskID:            e.TaskID,
+		OriginalEstimate:  e.OriginalEstimate,
+		RemainingEstimate: e.RemainingEstimate,
+		LoggedMinutes:     e.LoggedMinutes,
+	}
+	if e.EstimateUnit != nil {
+		effort.EstimateUnit = entities.EstimateUnit(*e.EstimateUnit)
+	}
+	return effort
+}
+
+func (s *Storage) TaskEffort(ctx context.Context, id uint64, login string, now time.Time) (entities.TaskEffort, error) {
+	query := `SELECT t.id, t.estimate_unit, t.original_estimate, t.remaining_estimate, ` + loggedMinutes + ` AS logged_minutes
+		FROM tasks t WHERE t.id = $2 AND t.owner = $3 AND t.org_id = $4 AND t.deleted_at IS NULL`
+	efforts, err := s.queryEfforts(ctx, query, now, id, login, orgID(ctx))
+	if err != nil {
+		return entities.TaskEffort{}, fmt.Errorf("unable to get task effort from storage: %w", err)
+	}
+	if len(efforts) == 0 {
+		return entities.TaskEffort{}, fmt.Errorf("unable to get task effort from storage: %w", entities.ErrNoTask)
+	}
+
+	return efforts[0], nil
+}
+
+type EstimateTotalSQL struct {
+	Login            string `db:"login"`
+	Tasks            int    `db:"tasks"`
+	EstimatedMinutes int    `db:"estimated_minutes"`
+	LoggedMinutes    int    `db:"logged_minutes"`
+}
+
+// EstimateTotals sums estimates and logged time of the organization tasks estimated in minutes and completed
+// within [from, to) per assignee, unassigned tasks are summed for their owners.
+func (s *Storage) EstimateTotals(ctx context.Context, from time.Time, to time.Time, now time.Time) ([]entities.EstimateAccuracy, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT login, count(*)::integer AS tasks, SUM(original_estimate)::integer AS estimated_minutes,
+			SUM(logged_minutes)::integer AS logged_minutes
+		FROM (
+			SELECT COALESCE(t.assignee, t.owner) AS login, t.original_estimate, ` + loggedMinutes + ` AS logged_minutes
+			FROM tasks t
+			WHERE t.org_id = $4 AND t.deleted_at IS NULL AND t.estimate_unit = 'minutes' AND t.original_estimate IS NOT NULL
+				AND t.completed_at >= $2 AND t.completed_at < $3
+		) efforts
+		GROUP BY login ORDER BY login`
+	rows, err := s.conn.Query(c, query, now, from, to, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query estimate totals from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	totalsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[EstimateTotalSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	totals := make([]entities.EstimateAccuracy, len(totalsSQL))
+	for i, total := range totalsSQL {
+		totals[i] = entities.EstimateAccuracy{
+			Login:            total.Login,
+			Tasks:            total.Tasks,
+			EstimatedMinutes: total.EstimatedMinutes,
+			LoggedMinutes:    total.LoggedMinutes,
+		}
+	}
+
+	return totals, nil
+}
+
+func (s *Storage) queryEfforts(ctx context.Context, query string, args ...any) ([]entities.TaskEffort, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	rows, err := s.conn.Query(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	effortsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[TaskEffortSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	efforts := make([]entities.TaskEffort, len(effortsSQL))
+	for i := range effortsSQL {
+		efforts[i] = effortsSQL[i].entity()
+	}
+
+	return efforts, nil
+}
@@ -0,0 +1,69 @@
+package storage_test
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTaskEffort() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	original, points := 90, 3
+	_, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "minutes", Status: entities.StatusInProgress, OriginalEstimate: &original}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "points", Status: entities.StatusInProgress, EstimateUnit: entities.EstimatePoints, OriginalEstimate: &points}, "test-user")
+	assert.NoError(t, err)
+
+	task, err := suite.storage.Task(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.EstimateMinutes, task.EstimateUnit)
+
+	// Empty unit keeps the current one, removed estimates clear it
+	remaining := 10
+	err = suite.storage.TaskUpdate(suite.ctx, entities.Task{ID: 2, Name: "points", OriginalEstimate: &points, RemainingEstimate: &remaining}, "test-user")
+	assert.NoError(t, err)
+	task, err = suite.storage.Task(suite.ctx, 2, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.EstimatePoints, task.EstimateUnit)
+	assert.Equal(t, &remaining, task.RemainingEstimate)
+
+	now := time.Now()
+	stopped := now.Add(-time.Hour)
+	_, err = suite.storage.TimeEntryAdd(suite.ctx, entities.TimeEntry{TaskID: 1, Login: "test-user", StartedAt: now.Add(-2 * time.Hour), StoppedAt: &stopped}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.TimerStart(suite.ctx, 1, "test-user", "test-user", now.Add(-30*time.Minute))
+	assert.NoError(t, err)
+
+	effort, err := suite.storage.TaskEffort(suite.ctx, 1, "test-user", now)
+	assert.NoError(t, err)
+	assert.Equal(t, 90, effort.LoggedMinutes)
+	assert.Equal(t, &original, effort.OriginalEstimate)
+
+	_, err = suite.storage.TaskEffort(suite.ctx, 1, "test-user-2", now)
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	// Only completed tasks estimated in minutes are taken into account
+	err = suite.storage.TaskStatusUpdate(suite.ctx, entities.StatusChange{ID: 1, From: entities.StatusInProgress, To: entities.StatusDone}, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TaskStatusUpdate(suite.ctx, entities.StatusChange{ID: 2, From: entities.StatusInProgress, To: entities.StatusDone}, "test-user")
+	assert.NoError(t, err)
+
+	// Tasks of the organization are summed per assignee, unassigned ones for their owners
+	_, err = suite.conn.Exec(suite.ctx, "UPDATE tasks SET owner = 'test-user-2' WHERE id = 1")
+	assert.NoError(t, err)
+	totals, err := suite.storage.EstimateTotals(suite.ctx, now.Add(-time.Hour), now.Add(time.Hour), now)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.EstimateAccuracy{{Login: "test-user-2", Tasks: 1, EstimatedMinutes: 90, LoggedMinutes: 90}}, totals)
+
+	totals, err = suite.storage.EstimateTotals(suite.ctx, now.Add(time.Hour), now.Add(2*time.Hour), now)
+	assert.NoError(t, err)
+	assert.Empty(t, totals)
+}
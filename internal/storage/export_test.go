@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TenantQuery runs a raw query the way storage methods do, tests check row level security with it.
+func (s *Storage) TenantQuery(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	return s.conn.Query(ctx, query, args...)
+}
+
+func (s *Storage) TenantExec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	return s.conn.Exec(ctx, query, args...)
+}
@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+type TaskEventSQL struct {
+	ID        uint64    `db:"id"`
+	TaskID    uint64    `db:"task_id"`
+	Author    string    `db:"author"`
+	Field     string    `db:"field"`
+	OldValue  string    `db:"old_value"`
+	NewValue  string    `db:"new_value"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Convert DTO to task event entity
+func (e TaskEventSQL) entity() entities.TaskEvent {
+	return entities.TaskEvent{
+		ID:        e.ID,
+		TaskID:    e.TaskID,
+		Author:    e.Author,
+		Field:     entities.TaskField(e.Field),
+		OldValue:  e.OldValue,
+		NewValue:  e.NewValue,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// TaskHistory returns changes of the task, oldest first.
+func (s *Storage) TaskHistory(ctx context.Context, id uint64, login string) ([]entities.TaskEvent, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT e.id, e.task_id, e.author, e.field, e.old_value, e.new_value, e.created_at FROM task_events e
+		JOIN tasks t ON t.id = e.task_id
+		WHERE e.task_id = $1 AND t.owner = $2 AND t.org_id = $3 AND t.deleted_at IS NULL
+		ORDER BY e.id`
+	rows, err := s.conn.Query(c, query, id, login, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query task history from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	eventsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[TaskEventSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	events := make([]entities.TaskEvent, len(eventsSQL))
+	for i := range eventsSQL {
+		events[i] = eventsSQL[i].entity()
+	}
+
+	return events, nil
+}
+
+// TaskRevert restores values the task had before the event, later changes of the same fields are undone too.
+// Revert is recorded in the history as a regular change.
+func (s *Storage) TaskRevert(ctx context.Context, id uint64, eventID uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	current, err := taskFields(c, tx, id)
+	if err != nil {
+		return fmt.Errorf("unable to revert task in storage: %w", err)
+	}
+
+	// Earliest change of each field since the event holds the value to restore
+	query := `SELECT DISTINCT ON (field) field, old_value FROM task_events
+		WHERE task_id = $1 AND id >= $2 AND EXISTS (SELECT 1 FROM task_events WHERE id = $2 AND task_id = $1)
+		ORDER BY field, id`
+	rows, err := tx.Query(c, query, id, eventID)
+	if err != nil {
+		return fmt.Errorf("unable to get query task history from storage: %w", err)
+	}
+	restored := make(map[entities.TaskField]string, len(current))
+	for field, value := range current {
+		restored[field] = value
+	}
+	var found bool
+	var field, value string
+	_, err = pgx.ForEachRow(rows, []any{&field, &value}, func() error {
+		found = true
+		restored[entities.TaskField(field)] = value
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("unable to revert task in storage: %w", entities.ErrNoTaskEvent)
+	}
+
+	parentID, err := idValue(restored[entities.TaskFieldParent])
+	if err != nil {
+		return fmt.Errorf("unable to revert task in storage: %w", err)
+	}
+	projectID, err := idValue(restored[entities.TaskFieldProject])
+	if err != nil {
+		return fmt.Errorf("unable to revert task in storage: %w", err)
+	}
+	if parentID != nil {
+		if err := checkParent(c, tx, id, *parentID, login); err != nil {
+			return fmt.Errorf("unable to revert task in storage: %w", err)
+		}
+	}
+	number, err := taskNumber(c, tx, id, projectID, login)
+	if err != nil {
+		return fmt.Errorf("unable to revert task in storage: %w", err)
+	}
+
+	// Values are kept as text, empty one stands for NULL
+	query = `UPDATE tasks SET name = $1, description = $2, priority = $3,
+			start_at = NULLIF($4, '')::timestamptz, due_at = NULLIF($5, '')::timestamptz, parent_id = $6,
+			project_id = $7, project_number = $8, estimate_unit = NULLIF($9, ''),
+			original_estimate = NULLIF($10, '')::integer, remaining_estimate = NULLIF($11, '')::integer,
+			assignee = NULLIF($12, ''), reporter = NULLIF($13, ''),
+			completed_at = CASE WHEN $14 = status THEN completed_at WHEN $14 = 'done' THEN now() END, status = $14
+		WHERE id = $15 AND owner = $16 AND org_id = $17 AND deleted_at IS NULL`
+	row, err := tx.Exec(c, query, restored[entities.TaskFieldName], restored[entities.TaskFieldDescription],
+		entities.TaskPriority(restored[entities.TaskFieldPriority]).Level(),
+		restored[entities.TaskFieldStartAt], restored[entities.TaskFieldDueAt], parentID, projectID, number,
+		restored[entities.TaskFieldEstimateUnit], restored[entities.TaskFieldOriginalEstimate], restored[entities.TaskFieldRemainingEstimate],
+		restored[entities.TaskFieldAssignee], restored[entities.TaskFieldReporter], restored[entities.TaskFieldStatus],
+		id, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to revert task in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to revert task in storage: %w", entities.ErrNoTask)
+	}
+
+	if err := recordChanges(c, tx, id, eventAuthor(ctx, login), current, restored); err != nil {
+		return fmt.Errorf("unable to revert task in storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// taskFields returns current values of the fields kept in the task history,
+// the task is locked till the end of the transaction so that concurrent changes are recorded in order.
+func taskFields(ctx context.Context, q querier, id uint64) (map[entities.TaskField]string, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL FOR UPDATE`
+	rows, err := q.Query(ctx, query, id, orgID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	taskSQL, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[TaskSQL])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, entities.ErrNoTask
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fieldValues(taskSQL.entity()), nil
+}
+
+// fieldValues converts the task fields kept in the history to text, missing values are empty.
+func fieldValues(task entities.Task) map[entities.TaskField]string {
+	timeText := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	idText := func(id *uint64) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.FormatUint(*id, 10)
+	}
+	intText := func(n *int) string {
+		if n == nil {
+			return ""
+		}
+		return strconv.Itoa(*n)
+	}
+
+	return map[entities.TaskField]string{
+		entities.TaskFieldName:              task.Name,
+		entities.TaskFieldDescription:       task.Description,
+		entities.TaskFieldStatus:            string(task.Status),
+		entities.TaskFieldPriority:          string(task.Priority),
+		entities.TaskFieldStartAt:           timeText(task.StartAt),
+		entities.TaskFieldDueAt:             timeText(task.DueAt),
+		entities.TaskFieldParent:            idText(task.ParentID),
+		entities.TaskFieldProject:           idText(task.ProjectID),
+		entities.TaskFieldEstimateUnit:      string(task.EstimateUnit),
+		entities.TaskFieldOriginalEstimate:  intText(task.OriginalEstimate),
+		entities.TaskFieldRemainingEstimate: intText(task.RemainingEstimate),
+		entities.TaskFieldAssignee:          task.Assignee,
+		entities.TaskFieldReporter:          task.Reporter,
+	}
+}
+
+// idValue parses the id kept in the history, empty value is no id.
+func idValue(value string) (*uint64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %q in task history: %w", value, err)
+	}
+	return &id, nil
+}
+
+// recordChanges adds an event for every field which value differs.
+func recordChanges(ctx context.Context, q querier, id uint64, author string, old map[entities.TaskField]string, new map[entities.TaskField]string) error {
+	query := `INSERT INTO task_events (task_id, author, field, old_value, new_value) VALUES ($1, $2, $3, $4, $5)`
+	for _, field := range entities.TaskFields {
+		if old[field] == new[field] {
+			continue
+		}
+		if _, err := q.Exec(ctx, query, id, author, string(field), old[field], new[field]); err != nil {
+			return fmt.Errorf("unable to add task event to storage: %w", err)
+		}
+	}
+	return nil
+}
+
+// eventAuthor is the authenticated user of the request, the login given to storage is used outside of requests.
+// They differ when an editor changes a shared task on behalf of its owner.
+func eventAuthor(ctx context.Context, login string) string {
+	if author := requestLogin(ctx); author != "" {
+		return author
+	}
+	return login
+}
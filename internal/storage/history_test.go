@@ -0,0 +1,99 @@
+package storage_test
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTaskHistory() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	query := `INSERT INTO tasks (name, description, owner) VALUES ('v1', 'd1', 'test-user')`
+	_, err := suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	// Only changed fields are recorded
+	err = suite.storage.TaskUpdate(suite.ctx, entities.Task{ID: 1, Name: "v2", Description: "d1"}, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TaskUpdate(suite.ctx, entities.Task{ID: 1, Name: "v2", Description: "d1"}, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TaskUpdate(suite.ctx, entities.Task{ID: 1, Name: "v3", Description: "d2"}, "test-user")
+	assert.NoError(t, err)
+
+	events, err := suite.storage.TaskHistory(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 3, len(events)) {
+		assert.Equal(t, entities.TaskEvent{ID: 1, TaskID: 1, Author: "test-user", Field: entities.TaskFieldName, OldValue: "v1", NewValue: "v2", CreatedAt: events[0].CreatedAt}, events[0])
+		assert.Equal(t, entities.TaskFieldName, events[1].Field)
+		assert.Equal(t, entities.TaskFieldDescription, events[2].Field)
+		assert.Equal(t, "d1", events[2].OldValue)
+		assert.Equal(t, "d2", events[2].NewValue)
+	}
+
+	events, err = suite.storage.TaskHistory(suite.ctx, 1, "test-user-2")
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Revert undoes the event and everything after it
+	err = suite.storage.TaskRevert(suite.ctx, 1, 2, "test-user")
+	assert.NoError(t, err)
+
+	task, err := suite.storage.Task(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", task.Name)
+	assert.Equal(t, "d1", task.Description)
+
+	events, err = suite.storage.TaskHistory(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 5, len(events)) {
+		assert.Equal(t, "v3", events[3].OldValue)
+		assert.Equal(t, "v2", events[3].NewValue)
+		assert.Equal(t, "d2", events[4].OldValue)
+		assert.Equal(t, "d1", events[4].NewValue)
+	}
+
+	err = suite.storage.TaskRevert(suite.ctx, 1, 99, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTaskEvent)
+	err = suite.storage.TaskRevert(suite.ctx, 2, 1, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	// Every written field is recorded and restored, status changes included
+	dueAt := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC)
+	estimate := 30
+	err = suite.storage.TaskUpdate(suite.ctx, entities.Task{ID: 1, Name: "v2", Description: "d1", DueAt: &dueAt, OriginalEstimate: &estimate}, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TaskStatusUpdate(suite.ctx, entities.StatusChange{ID: 1, From: entities.StatusTodo, To: entities.StatusInProgress}, "test-user")
+	assert.NoError(t, err)
+
+	events, err = suite.storage.TaskHistory(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 9, len(events)) {
+		assert.Equal(t, entities.TaskFieldDueAt, events[5].Field)
+		assert.Equal(t, "", events[5].OldValue)
+		assert.Equal(t, "2025-05-05T10:00:00Z", events[5].NewValue)
+		assert.Equal(t, entities.TaskFieldEstimateUnit, events[6].Field)
+		assert.Equal(t, entities.TaskFieldOriginalEstimate, events[7].Field)
+		assert.Equal(t, "30", events[7].NewValue)
+		assert.Equal(t, entities.TaskFieldStatus, events[8].Field)
+		assert.Equal(t, "todo", events[8].OldValue)
+		assert.Equal(t, "in_progress", events[8].NewValue)
+	}
+
+	err = suite.storage.TaskRevert(suite.ctx, 1, 6, "test-user")
+	assert.NoError(t, err)
+
+	task, err = suite.storage.Task(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	assert.Nil(t, task.DueAt)
+	assert.Nil(t, task.OriginalEstimate)
+	assert.Equal(t, entities.EstimateUnit(""), task.EstimateUnit)
+	assert.Equal(t, entities.StatusTodo, task.Status)
+}
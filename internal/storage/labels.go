@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	uniqueViolationCode      = "23505"
+	serializationFailureCode = "40001"
+)
+
+// occurrenceIndex keeps a single live occurrence of a series per due date.
+const occurrenceIndex = "tasks_series_id_due_at_idx"
+
+// maxSerializableAttempts limits retries of a serializable transaction failing to serialize with concurrent ones.
+const maxSerializableAttempts = 3
+
+type LabelSQL struct {
+	ID    uint64 `db:"id"`
+	Name  string `db:"name"`
+	Color string `db:"color"`
+	Owner string `db:"owner"`
+}
+
+// Convert DTO to entity
+func (l LabelSQL) entity() entities.Label {
+	return entities.Label{
+		ID:    l.ID,
+		Name:  l.Name,
+		Color: l.Color,
+		Owner: l.Owner,
+	}
+}
+
+func (s *Storage) Labels(ctx context.Context, login string) ([]entities.Label, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id, name, color, owner FROM labels WHERE owner=$1 AND org_id=$2 ORDER BY name`
+	rows, err := s.conn.Query(c, query, login, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query labels from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	labelsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[LabelSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	labels := make([]entities.Label, len(labelsSQL))
+	for i := range labelsSQL {
+		labels[i] = labelsSQL[i].entity()
+	}
+
+	return labels, nil
+}
+
+func (s *Storage) Label(ctx context.Context, id uint64, login string) (entities.Label, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	var labelSQL LabelSQL
+	query := `SELECT id, name, color, owner FROM labels WHERE id=$1 AND owner=$2 AND org_id=$3`
+	err := s.conn.QueryRow(c, query, id, login, orgID(ctx)).Scan(&labelSQL.ID, &labelSQL.Name, &labelSQL.Color, &labelSQL.Owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Label{}, fmt.Errorf("unable to get label from storage: %w", entities.ErrNoLabel)
+	}
+	if err != nil {
+		return entities.Label{}, fmt.Errorf("unable to get label from storage: %w", err)
+	}
+
+	return labelSQL.entity(), nil
+}
+
+func (s *Storage) LabelAdd(ctx context.Context, label entities.Label, login string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	var labelID uint64
+	query := `INSERT INTO labels (name, color, owner, org_id) VALUES ($1, $2, $3, $4) RETURNING id`
+	err := s.conn.QueryRow(c, query, label.Name, label.Color, login, orgID(ctx)).Scan(&labelID)
+	if isUniqueViolation(err) {
+		return 0, fmt.Errorf("unable to add label to storage: %w", entities.ErrLabelExists)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to add label to storage: %w", err)
+	}
+
+	return labelID, nil
+}
+
+func (s *Storage) LabelUpdate(ctx context.Context, label entities.Label, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE labels SET name = $1, color = $2 WHERE id = $3 AND owner = $4 AND org_id = $5`
+	row, err := s.conn.Exec(c, query, label.Name, label.Color, label.ID, login, orgID(ctx))
+	if isUniqueViolation(err) {
+		return fmt.Errorf("unable to update label in storage: %w", entities.ErrLabelExists)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to update label in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to update label in storage: %w", entities.ErrNoLabel)
+	}
+
+	return nil
+}
+
+func (s *Storage) LabelRemove(ctx context.Context, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query, task links are removed by cascade
+	query := `DELETE FROM labels WHERE id=$1 AND owner=$2 AND org_id=$3`
+	row, err := s.conn.Exec(c, query, id, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to remove label from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove label from storage: %w", entities.ErrNoLabel)
+	}
+
+	return nil
+}
+
+// TaskLabelAttach attaches label to the task, both must belong to the owner. Attaching label twice is not an error.
+func (s *Storage) TaskLabelAttach(ctx context.Context, taskID uint64, labelID uint64, owner string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query, pair is counted before conflicts are skipped to tell missing rows from existing link
+	var found int
+	query := `WITH pair AS (
+			SELECT t.id AS task_id, l.id AS label_id FROM tasks t, labels l
+			WHERE t.id=$1 AND t.owner=$3 AND l.id=$2 AND l.owner=$3
+				AND t.org_id=$4 AND l.org_id=$4 AND t.deleted_at IS NULL
+		), inserted AS (
+			INSERT INTO task_labels (task_id, label_id) SELECT task_id, label_id FROM pair
+			ON CONFLICT DO NOTHING
+		)
+		SELECT count(*) FROM pair`
+	if err := s.conn.QueryRow(c, query, taskID, labelID, owner, orgID(ctx)).Scan(&found); err != nil {
+		return fmt.Errorf("unable to attach label to task in storage: %w", err)
+	}
+	if found == 0 {
+		return fmt.Errorf("unable to attach label to task in storage: %w", entities.ErrNoLabel)
+	}
+
+	return nil
+}
+
+func (s *Storage) TaskLabelDetach(ctx context.Context, taskID uint64, labelID uint64, owner string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `DELETE FROM task_labels tl USING labels l, tasks t
+		WHERE tl.label_id = l.id AND tl.task_id = t.id AND tl.task_id=$1 AND tl.label_id=$2
+			AND l.owner=$3 AND t.owner=$3 AND l.org_id=$4 AND t.org_id=$4`
+	row, err := s.conn.Exec(c, query, taskID, labelID, owner, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to detach label from task in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to detach label from task in storage: %w", entities.ErrNoLabel)
+	}
+
+	return nil
+}
+
+// attachLabels loads labels of the given tasks with a single query.
+func (s *Storage) attachLabels(ctx context.Context, tasks []entities.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	ids := make([]uint64, len(tasks))
+	index := make(map[uint64]int, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+		index[tasks[i].ID] = i
+	}
+
+	// Run SQL query
+	query := `SELECT tl.task_id, l.id, l.name, l.color, l.owner FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = ANY($1)
+		ORDER BY l.name`
+	rows, err := s.conn.Query(c, query, ids)
+	if err != nil {
+		return fmt.Errorf("unable to get query task labels from storage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID uint64
+		var labelSQL LabelSQL
+		if err := rows.Scan(&taskID, &labelSQL.ID, &labelSQL.Name, &labelSQL.Color, &labelSQL.Owner); err != nil {
+			return fmt.Errorf("unable to get parse row to DTO: %w", err)
+		}
+		i := index[taskID]
+		tasks[i].Labels = append(tasks[i].Labels, labelSQL.entity())
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to get query task labels from storage: %w", err)
+	}
+
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// isConstraintViolation reports whether the error is a unique violation of the named constraint or index.
+func isConstraintViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == constraint
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == serializationFailureCode
+}
@@ -0,0 +1,137 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestLabels() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE labels, tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	t.Run("success label lifecycle", func(t *testing.T) {
+		id, err := suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "bug", Color: "red"}, "test-user")
+		assert.NoError(t, err)
+
+		label, err := suite.storage.Label(suite.ctx, id, "test-user")
+		assert.NoError(t, err)
+		assert.Equal(t, entities.Label{ID: id, Name: "bug", Color: "red", Owner: "test-user"}, label)
+
+		err = suite.storage.LabelUpdate(suite.ctx, entities.Label{ID: id, Name: "defect"}, "test-user")
+		assert.NoError(t, err)
+
+		labels, err := suite.storage.Labels(suite.ctx, "test-user")
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(labels)) {
+			assert.Equal(t, "defect", labels[0].Name)
+		}
+
+		err = suite.storage.LabelRemove(suite.ctx, id, "test-user")
+		assert.NoError(t, err)
+
+		_, err = suite.storage.Label(suite.ctx, id, "test-user")
+		assert.ErrorIs(t, err, entities.ErrNoLabel)
+	})
+
+	t.Run("adding duplicated label", func(t *testing.T) {
+		_, err := suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "dup"}, "test-user")
+		assert.NoError(t, err)
+
+		_, err = suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "dup"}, "test-user")
+		assert.ErrorIs(t, err, entities.ErrLabelExists)
+
+		_, err = suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "dup"}, "test-user-2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("getting not your label", func(t *testing.T) {
+		id, err := suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "private"}, "test-user-2")
+		assert.NoError(t, err)
+
+		_, err = suite.storage.Label(suite.ctx, id, "test-user")
+		assert.ErrorIs(t, err, entities.ErrNoLabel)
+
+		err = suite.storage.LabelRemove(suite.ctx, id, "test-user")
+		assert.ErrorIs(t, err, entities.ErrNoLabel)
+	})
+}
+
+func (suite *Suite) TestTaskLabels() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE labels, tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	query := `INSERT INTO tasks (name, description, owner) VALUES
+		('both', '', 'test-user'), ('bug-only', '', 'test-user'), ('none', '', 'test-user')`
+	_, err := suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	bug, err := suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "bug"}, "test-user")
+	assert.NoError(t, err)
+	ui, err := suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "ui"}, "test-user")
+	assert.NoError(t, err)
+	foreign, err := suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "ui"}, "test-user-2")
+	assert.NoError(t, err)
+
+	assert.NoError(t, suite.storage.TaskLabelAttach(suite.ctx, 1, bug, "test-user"))
+	assert.NoError(t, suite.storage.TaskLabelAttach(suite.ctx, 1, ui, "test-user"))
+	assert.NoError(t, suite.storage.TaskLabelAttach(suite.ctx, 2, bug, "test-user"))
+	assert.NoError(t, suite.storage.TaskLabelAttach(suite.ctx, 2, bug, "test-user"))
+	assert.ErrorIs(t, suite.storage.TaskLabelAttach(suite.ctx, 3, foreign, "test-user"), entities.ErrNoLabel)
+
+	names := func(tasks []entities.Task) []string {
+		result := []string{}
+		for _, task := range tasks {
+			result = append(result, task.Name)
+		}
+		return result
+	}
+
+	t.Run("task carries its labels", func(t *testing.T) {
+		task, err := suite.storage.Task(suite.ctx, 1, "test-user")
+		assert.NoError(t, err)
+		if assert.Equal(t, 2, len(task.Labels)) {
+			assert.Equal(t, "bug", task.Labels[0].Name)
+			assert.Equal(t, "ui", task.Labels[1].Name)
+		}
+
+		task, err = suite.storage.Task(suite.ctx, 3, "test-user")
+		assert.NoError(t, err)
+		assert.Empty(t, task.Labels)
+	})
+
+	t.Run("filtering with all labels", func(t *testing.T) {
+		page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{
+			Labels: []string{"bug", "ui"},
+		}, entities.PageRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"both"}, names(page.Tasks))
+	})
+
+	t.Run("filtering with any label", func(t *testing.T) {
+		page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{
+			Labels:    []string{"bug", "ui"},
+			LabelsAny: true,
+		}, entities.PageRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"both", "bug-only"}, names(page.Tasks))
+	})
+
+	t.Run("detaching label", func(t *testing.T) {
+		err := suite.storage.TaskLabelDetach(suite.ctx, 2, bug, "test-user")
+		assert.NoError(t, err)
+
+		err = suite.storage.TaskLabelDetach(suite.ctx, 2, bug, "test-user")
+		assert.ErrorIs(t, err, entities.ErrNoLabel)
+	})
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxLoginLength follows the login column of users
+const maxLoginLength = 64
+
+// OIDCStateAdd stores the pending login, states older than the max age are removed on the way.
+func (s *Storage) OIDCStateAdd(ctx context.Context, state entities.OIDCState, maxAge time.Duration) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Run SQL query
+	if _, err := tx.Exec(c, `DELETE FROM oidc_states WHERE created_at < $1`, time.Now().Add(-maxAge)); err != nil {
+		return fmt.Errorf("unable to remove expired login states: %w", err)
+	}
+	query := `INSERT INTO oidc_states (state, verifier, nonce, binding_hash) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.Exec(c, query, state.State, state.Verifier, state.Nonce, state.BindingHash); err != nil {
+		return fmt.Errorf("unable to add login state: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// OIDCStateTake removes the pending login and returns it, a state is usable once and only until the max age.
+func (s *Storage) OIDCStateTake(ctx context.Context, state string, maxAge time.Duration) (entities.OIDCState, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	var result entities.OIDCState
+
+	// Run SQL query
+	query := `DELETE FROM oidc_states WHERE state=$1 RETURNING state, verifier, nonce, binding_hash, created_at`
+	err := s.conn.QueryRow(c, query, state).Scan(&result.State, &result.Verifier, &result.Nonce, &result.BindingHash, &result.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.OIDCState{}, fmt.Errorf("unable to take login state: %w", entities.ErrNoOIDCState)
+	}
+	if err != nil {
+		return entities.OIDCState{}, fmt.Errorf("unable to take login state: %w", err)
+	}
+	if time.Since(result.CreatedAt) > maxAge {
+		return entities.OIDCState{}, fmt.Errorf("unable to take login state: %w", entities.ErrNoOIDCState)
+	}
+
+	return result, nil
+}
+
+// IdentityUser returns the login of the user signed in with the identity, the user is created on the first login
+// together with the personal organization.
+// The login is taken as is when it is free, otherwise it gets a suffix derived from the identity.
+func (s *Storage) IdentityUser(ctx context.Context, issuer string, subject string, login string) (string, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return "", fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Run SQL query
+	var existing string
+	query := `SELECT u.login FROM identities i JOIN users u ON u.id = i.user_id WHERE i.issuer=$1 AND i.subject=$2`
+	err = tx.QueryRow(c, query, issuer, subject).Scan(&existing)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("unable to get user by identity: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(issuer + "\n" + subject))
+	suffix := "-" + hex.EncodeToString(sum[:4])
+
+	var userID uint64
+	for _, candidate := range []string{truncateLogin(login, ""), truncateLogin(login, suffix)} {
+		query = `INSERT INTO users (login) VALUES ($1) ON CONFLICT (login) DO NOTHING RETURNING id`
+		err = tx.QueryRow(c, query, candidate).Scan(&userID)
+		if err == nil {
+			login = candidate
+			break
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("unable to add user: %w", err)
+		}
+	}
+	if userID == 0 {
+		return "", fmt.Errorf("unable to add user: login %q is taken", login)
+	}
+
+	query = `INSERT INTO identities (issuer, subject, user_id) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(c, query, issuer, subject, userID); err != nil {
+		return "", fmt.Errorf("unable to add identity: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return "", fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return login, nil
+}
+
+// truncateLogin cuts the login to fit the column together with the suffix.
+func truncateLogin(login string, suffix string) string {
+	runes := []rune(login)
+	if limit := maxLoginLength - len(suffix); len(runes) > limit {
+		runes = runes[:limit]
+	}
+	return string(runes) + suffix
+}
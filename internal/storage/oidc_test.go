@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestOIDCState() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE oidc_states")
+		assert.NoError(t, err)
+	}()
+
+	state := entities.OIDCState{State: "state", Verifier: "verifier", Nonce: "nonce", BindingHash: "binding-hash"}
+	err := suite.storage.OIDCStateAdd(suite.ctx, state, time.Minute)
+	assert.NoError(t, err)
+
+	taken, err := suite.storage.OIDCStateTake(suite.ctx, "state", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "verifier", taken.Verifier)
+	assert.Equal(t, "nonce", taken.Nonce)
+	assert.Equal(t, "binding-hash", taken.BindingHash)
+
+	// State is taken once
+	_, err = suite.storage.OIDCStateTake(suite.ctx, "state", time.Minute)
+	assert.ErrorIs(t, err, entities.ErrNoOIDCState)
+
+	// Expired state is not usable and is removed by later logins
+	_, err = suite.conn.Exec(suite.ctx, "INSERT INTO oidc_states (state, verifier, nonce, binding_hash, created_at) VALUES ('old', 'v', 'n', 'b', now() - interval '1 hour')")
+	assert.NoError(t, err)
+	_, err = suite.storage.OIDCStateTake(suite.ctx, "old", time.Minute)
+	assert.ErrorIs(t, err, entities.ErrNoOIDCState)
+
+	_, err = suite.conn.Exec(suite.ctx, "INSERT INTO oidc_states (state, verifier, nonce, binding_hash, created_at) VALUES ('old', 'v', 'n', 'b', now() - interval '1 hour')")
+	assert.NoError(t, err)
+	err = suite.storage.OIDCStateAdd(suite.ctx, entities.OIDCState{State: "new", Verifier: "v", Nonce: "n"}, time.Minute)
+	assert.NoError(t, err)
+	var count int
+	err = suite.conn.QueryRow(suite.ctx, "SELECT count(*) FROM oidc_states").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func (suite *Suite) TestIdentityUser() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE users RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	login, err := suite.storage.IdentityUser(suite.ctx, "https://issuer", "subject-1", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", login)
+
+	exists, err := suite.storage.UserExists(suite.ctx, "alice")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	personal, err := suite.storage.UserOrg(suite.ctx, "alice", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.OrgOwner, personal.Role)
+
+	// The identity keeps its user even when the claim changes
+	login, err = suite.storage.IdentityUser(suite.ctx, "https://issuer", "subject-1", "alice-renamed")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", login)
+
+	// Taken login gets a suffix, the same subject of another issuer is another user
+	other, err := suite.storage.IdentityUser(suite.ctx, "https://other", "subject-1", "alice")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "alice", other)
+	assert.True(t, strings.HasPrefix(other, "alice-"))
+
+	long, err := suite.storage.IdentityUser(suite.ctx, "https://issuer", "subject-2", strings.Repeat("a", 100))
+	assert.NoError(t, err)
+	assert.Len(t, long, 64)
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+type OrganizationSQL struct {
+	ID   uint64 `db:"id"`
+	Name string `db:"name"`
+	Role string `db:"role"`
+}
+
+// Convert DTO to entity
+func (o OrganizationSQL) entity() entities.Organization {
+	return entities.Organization{
+		ID:   o.ID,
+		Name: o.Name,
+		Role: entities.OrgRole(o.Role),
+	}
+}
+
+type MembershipSQL struct {
+	OrgID uint64 `db:"org_id"`
+	Login string `db:"login"`
+	Role  string `db:"role"`
+}
+
+// Convert DTO to entity
+func (m MembershipSQL) entity() entities.Membership {
+	return entities.Membership{
+		OrgID: m.OrgID,
+		Login: m.Login,
+		Role:  entities.OrgRole(m.Role),
+	}
+}
+
+// orgID returns the active organization of the request set by the auth middleware.
+// Without it no organization matches, so tasks, projects, labels and boards are never returned.
+func orgID(ctx context.Context) uint64 {
+	id, _ := ctx.Value(entities.OrgIDKey).(uint64)
+	return id
+}
+
+// UserOrg returns the organization of the user by id, zero id selects the personal organization
+// which the database creates together with the user.
+func (s *Storage) UserOrg(ctx context.Context, login string, id uint64) (entities.Organization, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT o.id, o.name, m.role FROM organizations o
+		JOIN memberships m ON m.org_id = o.id
+		WHERE (o.id=$1 OR ($1=0 AND o.personal_login=$2)) AND m.login=$2`
+	row, err := s.conn.Query(c, query, id, login)
+	if err != nil {
+		return entities.Organization{}, fmt.Errorf("unable to get query organization from storage: %w", err)
+	}
+	defer row.Close()
+
+	// Parse SQL query to DTO
+	orgSQL, err := pgx.CollectOneRow(row, pgx.RowToStructByName[OrganizationSQL])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Organization{}, fmt.Errorf("unable to get organization from storage: %w: %w", entities.ErrNoOrg, err)
+	}
+	if err != nil {
+		return entities.Organization{}, fmt.Errorf("unable to get parse row to DTO: %w", err)
+	}
+
+	return orgSQL.entity(), nil
+}
+
+// Orgs returns organizations the user is a member of ordered by name.
+func (s *Storage) Orgs(ctx context.Context, login string) ([]entities.Organization, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT o.id, o.name, m.role FROM organizations o
+		JOIN memberships m ON m.org_id = o.id WHERE m.login=$1 ORDER BY o.name, o.id`
+	rows, err := s.conn.Query(c, query, login)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query organizations from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	orgsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[OrganizationSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	orgs := make([]entities.Organization, len(orgsSQL))
+	for i := range orgsSQL {
+		orgs[i] = orgsSQL[i].entity()
+	}
+
+	return orgs, nil
+}
+
+// OrgAdd creates the organization with the user as its owner.
+func (s *Storage) OrgAdd(ctx context.Context, org entities.Organization, login string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return 0, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Run SQL query
+	var id uint64
+	if err := tx.QueryRow(c, `INSERT INTO organizations (name) VALUES ($1) RETURNING id`, org.Name).Scan(&id); err != nil {
+		return 0, fmt.Errorf("unable to add organization to storage: %w", err)
+	}
+
+	query := `INSERT INTO memberships (org_id, login, role) VALUES ($1, $2, 'owner')`
+	if _, err := tx.Exec(c, query, id, login); err != nil {
+		return 0, fmt.Errorf("unable to add membership to storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return 0, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+// Members returns members of the organization ordered by login.
+func (s *Storage) Members(ctx context.Context, id uint64) ([]entities.Membership, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT org_id, login, role FROM memberships WHERE org_id=$1 ORDER BY login`
+	rows, err := s.conn.Query(c, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query members from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	membersSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[MembershipSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	members := make([]entities.Membership, len(membersSQL))
+	for i := range membersSQL {
+		members[i] = membersSQL[i].entity()
+	}
+
+	return members, nil
+}
+
+// MemberSet adds the member to the organization or changes the member role.
+func (s *Storage) MemberSet(ctx context.Context, member entities.Membership) error {
+	return s.changeMembers(ctx, member.OrgID, func(c context.Context, tx pgx.Tx) error {
+		query := `INSERT INTO memberships (org_id, login, role) VALUES ($1, $2, $3)
+			ON CONFLICT (org_id, login) DO UPDATE SET role = EXCLUDED.role`
+		if _, err := tx.Exec(c, query, member.OrgID, member.Login, string(member.Role)); err != nil {
+			return fmt.Errorf("unable to set member in storage: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Storage) MemberRemove(ctx context.Context, id uint64, login string) error {
+	return s.changeMembers(ctx, id, func(c context.Context, tx pgx.Tx) error {
+		row, err := tx.Exec(c, `DELETE FROM memberships WHERE org_id=$1 AND login=$2`, id, login)
+		if err != nil {
+			return fmt.Errorf("unable to remove member from storage: %w", err)
+		}
+		if row.RowsAffected() == 0 {
+			return fmt.Errorf("unable to remove member from storage: %w", entities.ErrNoMember)
+		}
+		return nil
+	})
+}
+
+// changeMembers runs the change of the organization members in a transaction holding the organization lock,
+// the change is rolled back when the organization is left without an owner.
+func (s *Storage) changeMembers(ctx context.Context, id uint64, change func(c context.Context, tx pgx.Tx) error) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	var locked uint64
+	err = tx.QueryRow(c, `SELECT id FROM organizations WHERE id=$1 FOR UPDATE`, id).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("unable to change members in storage: %w", entities.ErrNoOrg)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to lock organization in storage: %w", err)
+	}
+
+	if err := change(c, tx); err != nil {
+		return err
+	}
+
+	var owners int
+	query := `SELECT count(*) FROM memberships WHERE org_id=$1 AND role='owner'`
+	if err := tx.QueryRow(c, query, id).Scan(&owners); err != nil {
+		return fmt.Errorf("unable to count organization owners in storage: %w", err)
+	}
+	if owners == 0 {
+		return fmt.Errorf("unable to change members in storage: %w", entities.ErrLastOwner)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package storage_test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestOrgMembers() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "DELETE FROM organizations WHERE id <> $1", suite.orgID)
+		assert.NoError(t, err)
+	}()
+
+	// Personal organization is only looked up, users without one have none
+	personal, err := suite.storage.UserOrg(suite.ctx, "test-user", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, suite.orgID, personal.ID)
+	assert.Equal(t, entities.OrgOwner, personal.Role)
+	_, err = suite.storage.UserOrg(suite.ctx, "unknown-user", 0)
+	assert.ErrorIs(t, err, entities.ErrNoOrg)
+	var count int
+	err = suite.conn.QueryRow(suite.ctx, "SELECT count(*) FROM organizations WHERE personal_login = 'unknown-user'").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	id, err := suite.storage.OrgAdd(suite.ctx, entities.Organization{Name: "team"}, "test-user")
+	assert.NoError(t, err)
+
+	_, err = suite.storage.UserOrg(suite.ctx, "test-user-2", id)
+	assert.ErrorIs(t, err, entities.ErrNoOrg)
+
+	err = suite.storage.MemberSet(suite.ctx, entities.Membership{OrgID: id, Login: "test-user-2", Role: entities.OrgMember})
+	assert.NoError(t, err)
+	org, err := suite.storage.UserOrg(suite.ctx, "test-user-2", id)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.Organization{ID: id, Name: "team", Role: entities.OrgMember}, org)
+
+	orgs, err := suite.storage.Orgs(suite.ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Organization{
+		{ID: id, Name: "team", Role: entities.OrgOwner},
+		{ID: suite.orgID, Name: "test-user", Role: entities.OrgOwner},
+	}, orgs)
+
+	members, err := suite.storage.Members(suite.ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Membership{
+		{OrgID: id, Login: "test-user", Role: entities.OrgOwner},
+		{OrgID: id, Login: "test-user-2", Role: entities.OrgMember},
+	}, members)
+
+	// Organization always keeps an owner
+	err = suite.storage.MemberSet(suite.ctx, entities.Membership{OrgID: id, Login: "test-user", Role: entities.OrgAdmin})
+	assert.ErrorIs(t, err, entities.ErrLastOwner)
+	err = suite.storage.MemberRemove(suite.ctx, id, "test-user")
+	assert.ErrorIs(t, err, entities.ErrLastOwner)
+
+	err = suite.storage.MemberSet(suite.ctx, entities.Membership{OrgID: id, Login: "test-user-2", Role: entities.OrgOwner})
+	assert.NoError(t, err)
+	err = suite.storage.MemberRemove(suite.ctx, id, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.MemberRemove(suite.ctx, id, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoMember)
+}
+
+func (suite *Suite) TestPersonalOrgOfUser() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "DELETE FROM users WHERE login = 'scripted-user'")
+		assert.NoError(t, err)
+		_, err = suite.conn.Exec(suite.ctx, "DELETE FROM organizations WHERE id <> $1", suite.orgID)
+		assert.NoError(t, err)
+	}()
+
+	// User added by plain SQL gets the personal organization as well
+	_, err := suite.conn.Exec(suite.ctx, "INSERT INTO users (login) VALUES ('scripted-user')")
+	assert.NoError(t, err)
+
+	personal, err := suite.storage.UserOrg(suite.ctx, "scripted-user", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.Organization{ID: personal.ID, Name: "scripted-user", Role: entities.OrgOwner}, personal)
+}
+
+func (suite *Suite) TestOrgIsolation() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks, projects, labels, boards RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+		_, err = suite.conn.Exec(suite.ctx, "DELETE FROM organizations WHERE id <> $1", suite.orgID)
+		assert.NoError(t, err)
+	}()
+
+	// The same user works in both organizations
+	otherID, err := suite.storage.OrgAdd(suite.ctx, entities.Organization{Name: "other"}, "test-user")
+	assert.NoError(t, err)
+	other := context.WithValue(suite.ctx, entities.OrgIDKey, otherID)
+
+	projectID, err := suite.storage.ProjectAdd(suite.ctx, entities.Project{Key: "WEB", Name: "Website"}, "test-user")
+	assert.NoError(t, err)
+	taskID, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "task", Status: entities.StatusTodo, ProjectID: &projectID}, "test-user")
+	assert.NoError(t, err)
+	labelID, err := suite.storage.LabelAdd(suite.ctx, entities.Label{Name: "bug"}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.BoardAdd(suite.ctx, entities.Board{Name: "board"}, "test-user")
+	assert.NoError(t, err)
+
+	// Nothing of the first organization is visible in the other one
+	page, err := suite.storage.Tasks(other, "test-user", entities.TaskFilter{}, entities.PageRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+	_, err = suite.storage.Task(other, taskID, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	projects, err := suite.storage.Projects(other, "test-user", true)
+	assert.NoError(t, err)
+	assert.Empty(t, projects)
+	_, err = suite.storage.Project(other, projectID, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoProject)
+	labels, err := suite.storage.Labels(other, "test-user")
+	assert.NoError(t, err)
+	assert.Empty(t, labels)
+	boards, err := suite.storage.Boards(other, "test-user")
+	assert.NoError(t, err)
+	assert.Empty(t, boards)
+
+	// Nor can it be changed from there
+	err = suite.storage.TaskUpdate(other, entities.Task{ID: taskID, Name: "changed"}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	err = suite.storage.TaskRemove(other, taskID, "test-user", entities.RemoveReject)
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	_, err = suite.storage.CommentAdd(other, entities.Comment{TaskID: taskID, Author: "test-user", Body: "hi"}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	err = suite.storage.LabelRemove(other, labelID, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoLabel)
+	_, err = suite.storage.TaskAdd(other, entities.Task{Name: "task", Status: entities.StatusTodo, ProjectID: &projectID}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoProject)
+
+	// Label names are unique within the organization only
+	_, err = suite.storage.LabelAdd(other, entities.Label{Name: "bug"}, "test-user")
+	assert.NoError(t, err)
+
+	// Requests without an organization see nothing
+	page, err = suite.storage.Tasks(context.Background(), "test-user", entities.TaskFilter{}, entities.PageRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+
+	task, err := suite.storage.Task(suite.ctx, taskID, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "task", task.Name)
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+// sortColumn describes whitelisted sort field: SQL expression used in ORDER BY
+// and keyset condition, and the way its value is stored in the page cursor.
+type sortColumn struct {
+	expr     string
+	nullable bool
+	value    func(t TaskSQL) any
+	decode   func(raw json.RawMessage) (any, error)
+}
+
+// taskSortColumns maps whitelisted sort fields to SQL expressions.
+// Priority level is negated so that descending order starts with the most urgent tasks.
+var taskSortColumns = map[string]sortColumn{
+	"id":       {expr: "id", value: func(t TaskSQL) any { return t.ID }, decode: decodeCursorValue[int64]},
+	"name":     {expr: "name", value: func(t TaskSQL) any { return t.Name }, decode: decodeCursorValue[string]},
+	"status":   {expr: "status", value: func(t TaskSQL) any { return t.Status }, decode: decodeCursorValue[string]},
+	"priority": {expr: "(-priority)", value: func(t TaskSQL) any { return -int64(t.Priority) }, decode: decodeCursorValue[int64]},
+	"start_at": {expr: "start_at", nullable: true, value: func(t TaskSQL) any { return t.StartAt }, decode: decodeCursorValue[*time.Time]},
+	"due_at":   {expr: "due_at", nullable: true, value: func(t TaskSQL) any { return t.DueAt }, decode: decodeCursorValue[*time.Time]},
+}
+
+type taskOrder struct {
+	field  string
+	column sortColumn
+	desc   bool
+}
+
+// taskSortOrder validates sort keys against the whitelist.
+// Tasks are always finally ordered by id to keep the order stable and make keyset unique.
+func taskSortOrder(keys []entities.SortKey) ([]taskOrder, error) {
+	var order []taskOrder
+	hasID := false
+	for _, key := range keys {
+		column, ok := taskSortColumns[key.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", entities.ErrInvalidSort, key.Field)
+		}
+		order = append(order, taskOrder{field: key.Field, column: column, desc: key.Desc})
+		if key.Field == "id" {
+			hasID = true
+			break
+		}
+	}
+	if !hasID {
+		order = append(order, taskOrder{field: "id", column: taskSortColumns["id"]})
+	}
+
+	return order, nil
+}
+
+// orderBy translates sort order to ORDER BY clause, NULL values always go last.
+func orderBy(order []taskOrder) string {
+	parts := make([]string, len(order))
+	for i, o := range order {
+		direction := "ASC"
+		if o.desc {
+			direction = "DESC"
+		}
+		parts[i] = o.column.expr + " " + direction + " NULLS LAST"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// signature identifies sort order, so a cursor can not be reused with another sorting.
+func signature(order []taskOrder) string {
+	parts := make([]string, len(order))
+	for i, o := range order {
+		parts[i] = o.field
+		if o.desc {
+			parts[i] = "-" + o.field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+type cursorJSON struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// encodeCursor builds opaque token pointing right after the given row.
+func encodeCursor(order []taskOrder, last TaskSQL) (string, error) {
+	c := cursorJSON{Sort: signature(order)}
+	for _, o := range order {
+		raw, err := json.Marshal(o.column.value(last))
+		if err != nil {
+			return "", fmt.Errorf("unable to encode cursor: %w", err)
+		}
+		c.Values = append(c.Values, raw)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(order []taskOrder, cursor string) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrInvalidCursor, err)
+	}
+
+	var c cursorJSON
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrInvalidCursor, err)
+	}
+	if c.Sort != signature(order) || len(c.Values) != len(order) {
+		return nil, fmt.Errorf("%w: cursor does not match sort order", entities.ErrInvalidCursor)
+	}
+
+	values := make([]any, len(order))
+	for i, o := range order {
+		values[i], err = o.column.decode(c.Values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", entities.ErrInvalidCursor, err)
+		}
+	}
+	return values, nil
+}
+
+// encodeIDCursor builds opaque token for lists ordered by id only, name distinguishes the list.
+func encodeIDCursor(name string, id uint64) (string, error) {
+	raw, err := json.Marshal(id)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode cursor: %w", err)
+	}
+
+	data, err := json.Marshal(cursorJSON{Sort: name, Values: []json.RawMessage{raw}})
+	if err != nil {
+		return "", fmt.Errorf("unable to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeIDCursor(name string, cursor string) (uint64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", entities.ErrInvalidCursor, err)
+	}
+
+	var c cursorJSON
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, fmt.Errorf("%w: %w", entities.ErrInvalidCursor, err)
+	}
+	if c.Sort != name || len(c.Values) != 1 {
+		return 0, fmt.Errorf("%w: cursor does not match the list", entities.ErrInvalidCursor)
+	}
+
+	var id uint64
+	if err := json.Unmarshal(c.Values[0], &id); err != nil {
+		return 0, fmt.Errorf("%w: %w", entities.ErrInvalidCursor, err)
+	}
+	return id, nil
+}
+
+func decodeCursorValue[T any](raw json.RawMessage) (any, error) {
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// keysetCondition builds condition selecting rows placed after the cursor values
+// in the given order: (k1 after v1) OR (k1 = v1 AND k2 after v2) OR ...
+// Arguments are appended to args and the placeholders are numbered accordingly.
+func keysetCondition(order []taskOrder, values []any, args []any) (string, []any) {
+	var disjuncts []string
+	var equals []string
+
+	for i, o := range order {
+		value := values[i]
+		isNull := value == nil
+		if t, ok := value.(*time.Time); ok && t == nil {
+			isNull = true
+		}
+
+		// Nulls go last, so nothing is placed after NULL within the same key
+		if !isNull {
+			args = append(args, value)
+			op := ">"
+			if o.desc {
+				op = "<"
+			}
+			after := fmt.Sprintf("%s %s $%d", o.column.expr, op, len(args))
+			if o.column.nullable {
+				after = fmt.Sprintf("(%s OR %s IS NULL)", after, o.column.expr)
+			}
+			disjuncts = append(disjuncts, "("+strings.Join(append(equals, after), " AND ")+")")
+		}
+
+		if isNull {
+			equals = append(equals, o.column.expr+" IS NULL")
+		} else {
+			equals = append(equals, fmt.Sprintf("%s = $%d", o.column.expr, len(args)))
+		}
+	}
+
+	if len(disjuncts) == 0 {
+		return "FALSE", args
+	}
+	return "(" + strings.Join(disjuncts, " OR ") + ")", args
+}
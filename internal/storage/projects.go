@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+type ProjectSQL struct {
+	ID          uint64 `db:"id"`
+	Owner       string `db:"owner"`
+	Key         string `db:"key"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+	Archived    bool   `db:"archived"`
+}
+
+// Convert DTO to entity
+func (p ProjectSQL) entity() entities.Project {
+	return entities.Project{
+		ID:          p.ID,
+		Owner:       p.Owner,
+		Key:         p.Key,
+		Name:        p.Name,
+		Description: p.Description,
+		Archived:    p.Archived,
+	}
+}
+
+// Projects returns user projects ordered by key, archived ones are included on request.
+func (s *Storage) Projects(ctx context.Context, login string, archived bool) ([]entities.Project, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id, owner, key, name, description, archived FROM projects
+		WHERE owner=$1 AND org_id=$3 AND (NOT archived OR $2) ORDER BY key`
+	rows, err := s.conn.Query(c, query, login, archived, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query projects from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	projectsSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[ProjectSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	projects := make([]entities.Project, len(projectsSQL))
+	for i := range projectsSQL {
+		projects[i] = projectsSQL[i].entity()
+	}
+
+	return projects, nil
+}
+
+func (s *Storage) Project(ctx context.Context, id uint64, login string) (entities.Project, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id, owner, key, name, description, archived FROM projects WHERE id=$1 AND owner=$2 AND org_id=$3`
+	rows, err := s.conn.Query(c, query, id, login, orgID(ctx))
+	if err != nil {
+		return entities.Project{}, fmt.Errorf("unable to get project from storage: %w", err)
+	}
+	defer rows.Close()
+
+	projectSQL, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[ProjectSQL])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Project{}, fmt.Errorf("unable to get project from storage: %w", entities.ErrNoProject)
+	}
+	if err != nil {
+		return entities.Project{}, fmt.Errorf("unable to get project from storage: %w", err)
+	}
+
+	return projectSQL.entity(), nil
+}
+
+func (s *Storage) ProjectAdd(ctx context.Context, project entities.Project, login string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	var projectID uint64
+	query := `INSERT INTO projects (owner, key, name, description, org_id) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err := s.conn.QueryRow(c, query, login, project.Key, project.Name, project.Description, orgID(ctx)).Scan(&projectID)
+	if isUniqueViolation(err) {
+		return 0, fmt.Errorf("unable to add project to storage: %w", entities.ErrProjectExists)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to add project to storage: %w", err)
+	}
+
+	return projectID, nil
+}
+
+// ProjectUpdate changes project details, the key is kept since task keys are built from it.
+func (s *Storage) ProjectUpdate(ctx context.Context, project entities.Project, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE projects SET name = $1, description = $2, archived = $3 WHERE id = $4 AND owner = $5 AND org_id = $6`
+	row, err := s.conn.Exec(c, query, project.Name, project.Description, project.Archived, project.ID, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to update project in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to update project in storage: %w", entities.ErrNoProject)
+	}
+
+	return nil
+}
+
+// ProjectRemove removes the project, its tasks are kept outside of any project.
+func (s *Storage) ProjectRemove(ctx context.Context, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Run SQL query
+	query := `UPDATE tasks SET project_id = NULL, project_number = NULL
+		WHERE project_id = (SELECT id FROM projects WHERE id=$1 AND owner=$2 AND org_id=$3)`
+	if _, err := tx.Exec(c, query, id, login, orgID(ctx)); err != nil {
+		return fmt.Errorf("unable to detach project tasks in storage: %w", err)
+	}
+
+	row, err := tx.Exec(c, `DELETE FROM projects WHERE id=$1 AND owner=$2 AND org_id=$3`, id, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to remove project from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove project from storage: %w", entities.ErrNoProject)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// taskNumber returns the number of task within the project. Task keeps its number while it stays
+// in the same project, otherwise the next number of the project is taken. Zero id means a new task.
+func taskNumber(ctx context.Context, q querier, id uint64, projectID *uint64, login string) (*int, error) {
+	if projectID == nil {
+		return nil, nil
+	}
+
+	if id != 0 {
+		var currentProject *uint64
+		var currentNumber *int
+		query := `SELECT project_id, project_number FROM tasks WHERE id=$1 AND owner=$2 AND deleted_at IS NULL FOR UPDATE`
+		err := q.QueryRow(ctx, query, id, login).Scan(&currentProject, &currentNumber)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entities.ErrNoTask
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to get task project: %w", err)
+		}
+		if currentProject != nil && *currentProject == *projectID {
+			return currentNumber, nil
+		}
+	}
+
+	// Row lock of the project serializes concurrent numbering
+	var number int
+	query := `UPDATE projects SET next_number = next_number + 1
+		WHERE id=$1 AND owner=$2 AND org_id=$3 AND NOT archived RETURNING next_number - 1`
+	err := q.QueryRow(ctx, query, *projectID, login, orgID(ctx)).Scan(&number)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var archived bool
+		err = q.QueryRow(ctx, `SELECT archived FROM projects WHERE id=$1 AND owner=$2 AND org_id=$3`, *projectID, login, orgID(ctx)).Scan(&archived)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entities.ErrNoProject
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to get project: %w", err)
+		}
+		return nil, entities.ErrProjectArchived
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get next project task number: %w", err)
+	}
+
+	return &number, nil
+}
@@ -0,0 +1,95 @@
+package storage_test
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestProjects() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks, projects RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	webID, err := suite.storage.ProjectAdd(suite.ctx, entities.Project{Key: "WEB", Name: "Website"}, "test-user")
+	assert.NoError(t, err)
+	apiID, err := suite.storage.ProjectAdd(suite.ctx, entities.Project{Key: "API", Name: "Backend"}, "test-user")
+	assert.NoError(t, err)
+
+	_, err = suite.storage.ProjectAdd(suite.ctx, entities.Project{Key: "WEB", Name: "Duplicate"}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrProjectExists)
+	_, err = suite.storage.ProjectAdd(suite.ctx, entities.Project{Key: "WEB", Name: "Website"}, "test-user-2")
+	assert.NoError(t, err)
+
+	// Tasks are numbered within their projects
+	for _, name := range []string{"first", "second"} {
+		_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: name, Status: entities.StatusTodo, ProjectID: &webID}, "test-user")
+		assert.NoError(t, err)
+	}
+	_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "foreign", Status: entities.StatusTodo, ProjectID: &webID}, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoProject)
+
+	// Clash of project numbers is not taken for an existing series occurrence
+	_, err = suite.conn.Exec(suite.ctx, "INSERT INTO tasks (name, owner, project_id, project_number) VALUES ('clash', 'test-user', $1, 3)", webID)
+	assert.NoError(t, err)
+	_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "third", Status: entities.StatusTodo, ProjectID: &webID}, "test-user")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, entities.ErrOccurrenceExists)
+	_, err = suite.conn.Exec(suite.ctx, "DELETE FROM tasks WHERE name = 'clash'")
+	assert.NoError(t, err)
+
+	task, err := suite.storage.Task(suite.ctx, 2, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "WEB-2", task.Key)
+	assert.Equal(t, &webID, task.ProjectID)
+
+	// Task keeps its number while it stays in the project and gets a new one after moving
+	task.Name = "renamed"
+	err = suite.storage.TaskUpdate(suite.ctx, task, "test-user")
+	assert.NoError(t, err)
+	task, err = suite.storage.Task(suite.ctx, 2, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "WEB-2", task.Key)
+
+	task.ProjectID = &apiID
+	err = suite.storage.TaskUpdate(suite.ctx, task, "test-user")
+	assert.NoError(t, err)
+	task, err = suite.storage.Task(suite.ctx, 2, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "API-1", task.Key)
+
+	page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{ProjectID: &webID}, entities.PageRequest{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(page.Tasks)) {
+		assert.Equal(t, "WEB-1", page.Tasks[0].Key)
+	}
+
+	// Archived projects do not accept tasks and are hidden by default
+	err = suite.storage.ProjectUpdate(suite.ctx, entities.Project{ID: webID, Name: "Website", Archived: true}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "third", Status: entities.StatusTodo, ProjectID: &webID}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrProjectArchived)
+
+	projects, err := suite.storage.Projects(suite.ctx, "test-user", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(projects))
+	projects, err = suite.storage.Projects(suite.ctx, "test-user", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(projects))
+
+	// Removing project keeps its tasks
+	err = suite.storage.ProjectRemove(suite.ctx, apiID, "test-user")
+	assert.NoError(t, err)
+	task, err = suite.storage.Task(suite.ctx, 2, "test-user")
+	assert.NoError(t, err)
+	assert.Nil(t, task.ProjectID)
+	assert.Equal(t, "", task.Key)
+
+	err = suite.storage.ProjectRemove(suite.ctx, apiID, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoProject)
+	_, err = suite.storage.Project(suite.ctx, apiID, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoProject)
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+type SeriesSQL struct {
+	ID    uint64    `db:"id"`
+	Owner string    `db:"owner"`
+	Rule  string    `db:"rule"`
+	Start time.Time `db:"start_at"`
+}
+
+// Convert DTO to series entity
+func (s SeriesSQL) entity() entities.Series {
+	return entities.Series{
+		ID:    s.ID,
+		Owner: s.Owner,
+		Rule:  s.Rule,
+		Start: s.Start,
+	}
+}
+
+func (s *Storage) Series(ctx context.Context, id uint64, login string) (entities.Series, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id, owner, rule, start_at FROM task_series WHERE id=$1 AND owner=$2`
+	rows, err := s.conn.Query(c, query, id, login)
+	if err != nil {
+		return entities.Series{}, fmt.Errorf("unable to get query task series from storage: %w", err)
+	}
+	defer rows.Close()
+
+	seriesSQL, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[SeriesSQL])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Series{}, fmt.Errorf("unable to get task series from storage: %w", entities.ErrNoSeries)
+	}
+	if err != nil {
+		return entities.Series{}, fmt.Errorf("unable to get parse row to DTO: %w", err)
+	}
+
+	return seriesSQL.entity(), nil
+}
+
+// SeriesSplit ends the series with the rule before and moves occurrences due at or after the given time
+// to a new series, applying the update to them. Returns id of the new series.
+func (s *Storage) SeriesSplit(ctx context.Context, id uint64, at time.Time, before string, after entities.Series, update entities.SeriesUpdate, login string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Empty priority keeps the current one
+	var priority *int16
+	if update.Priority != "" {
+		level := int16(update.Priority.Level())
+		priority = &level
+	}
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return 0, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	query := `UPDATE task_series SET rule=$1 WHERE id=$2 AND owner=$3`
+	row, err := tx.Exec(c, query, before, id, login)
+	if err != nil {
+		return 0, fmt.Errorf("unable to update task series in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return 0, fmt.Errorf("unable to update task series in storage: %w", entities.ErrNoSeries)
+	}
+
+	var seriesID uint64
+	query = `INSERT INTO task_series (owner, rule, start_at) VALUES ($1, $2, $3) RETURNING id`
+	if err := tx.QueryRow(c, query, login, after.Rule, after.Start).Scan(&seriesID); err != nil {
+		return 0, fmt.Errorf("unable to add task series to storage: %w", err)
+	}
+
+	// Moved occurrences are locked before the update to record their previous fields
+	query = `SELECT id FROM tasks
+		WHERE series_id = $1 AND owner = $2 AND due_at >= $3 AND org_id = $4 AND deleted_at IS NULL
+		ORDER BY id FOR UPDATE`
+	rows, err := tx.Query(c, query, id, login, at, orgID(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("unable to get task occurrences from storage: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uint64])
+	if err != nil {
+		return 0, fmt.Errorf("unable to get task occurrences from storage: %w", err)
+	}
+	previous := make(map[uint64]map[entities.TaskField]string, len(ids))
+	for _, taskID := range ids {
+		if previous[taskID], err = taskFields(c, tx, taskID); err != nil {
+			return 0, fmt.Errorf("unable to get task occurrences from storage: %w", err)
+		}
+	}
+
+	query = `UPDATE tasks SET series_id = $1,
+			name = COALESCE(NULLIF($2, ''), name),
+			description = COALESCE(NULLIF($3, ''), description),
+			priority = COALESCE($4, priority)
+		WHERE id = ANY($5)`
+	if _, err := tx.Exec(c, query, seriesID, update.Name, update.Description, priority, ids); err != nil {
+		return 0, fmt.Errorf("unable to update task occurrences in storage: %w", err)
+	}
+
+	author := eventAuthor(ctx, login)
+	for _, taskID := range ids {
+		changed, err := taskFields(c, tx, taskID)
+		if err != nil {
+			return 0, fmt.Errorf("unable to update task occurrences in storage: %w", err)
+		}
+		if err := recordChanges(c, tx, taskID, author, previous[taskID], changed); err != nil {
+			return 0, fmt.Errorf("unable to update task occurrences in storage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return 0, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return seriesID, nil
+}
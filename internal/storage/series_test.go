@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTaskSeries() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE task_series, tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	dueAt := time.Date(2025, 5, 5, 10, 0, 0, 0, time.UTC)
+	task := entities.Task{Name: "report", Owner: "test-user", Status: entities.StatusTodo, DueAt: &dueAt, Recurrence: "FREQ=DAILY"}
+	id, err := suite.storage.TaskAdd(suite.ctx, task, "test-user")
+	assert.NoError(t, err)
+
+	stored, err := suite.storage.Task(suite.ctx, id, "test-user")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, stored.SeriesID) {
+		return
+	}
+
+	series, err := suite.storage.Series(suite.ctx, *stored.SeriesID, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=DAILY", series.Rule)
+	assert.True(t, dueAt.Equal(series.Start))
+
+	_, err = suite.storage.Series(suite.ctx, *stored.SeriesID, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoSeries)
+
+	// Occurrence with the same due date in the series is rejected
+	nextDueAt := dueAt.AddDate(0, 0, 1)
+	next := entities.Task{Name: "report", Owner: "test-user", Status: entities.StatusTodo, DueAt: &nextDueAt, SeriesID: stored.SeriesID}
+	nextID, err := suite.storage.TaskAdd(suite.ctx, next, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.TaskAdd(suite.ctx, next, "test-user")
+	assert.ErrorIs(t, err, entities.ErrOccurrenceExists)
+
+	// Completion adds the next occurrence in the same transaction and keeps the existing one
+	change := entities.StatusChange{ID: id, From: entities.StatusTodo, To: entities.StatusDone, Next: &next}
+	err = suite.storage.TaskStatusUpdate(suite.ctx, change, "test-user")
+	assert.NoError(t, err)
+	change = entities.StatusChange{ID: id, From: entities.StatusDone, To: entities.StatusTodo}
+	err = suite.storage.TaskStatusUpdate(suite.ctx, change, "test-user")
+	assert.NoError(t, err)
+	laterDueAt := dueAt.AddDate(0, 0, 2)
+	later := entities.Task{Name: "report", Owner: "test-user", Status: entities.StatusTodo, DueAt: &laterDueAt, SeriesID: stored.SeriesID}
+	change = entities.StatusChange{ID: id, From: entities.StatusTodo, To: entities.StatusDone, Next: &later}
+	err = suite.storage.TaskStatusUpdate(suite.ctx, change, "test-user")
+	assert.NoError(t, err)
+	var count int
+	err = suite.conn.QueryRow(suite.ctx, "SELECT count(*) FROM tasks WHERE series_id = $1", *stored.SeriesID).Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	after := entities.Series{Owner: "test-user", Rule: "FREQ=WEEKLY", Start: nextDueAt}
+	update := entities.SeriesUpdate{Rule: after.Rule, Name: "weekly report"}
+	seriesID, err := suite.storage.SeriesSplit(suite.ctx, *stored.SeriesID, nextDueAt, "FREQ=DAILY;UNTIL=20250506T095959Z", after, update, "test-user")
+	assert.NoError(t, err)
+
+	first, err := suite.storage.Task(suite.ctx, id, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "report", first.Name)
+	assert.Equal(t, stored.SeriesID, first.SeriesID)
+
+	moved, err := suite.storage.Task(suite.ctx, nextID, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "weekly report", moved.Name)
+	assert.Equal(t, &seriesID, moved.SeriesID)
+
+	// Update of moved occurrences is kept in their history
+	events, err := suite.storage.TaskHistory(suite.ctx, nextID, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(events)) {
+		assert.Equal(t, entities.TaskFieldName, events[0].Field)
+		assert.Equal(t, "report", events[0].OldValue)
+		assert.Equal(t, "weekly report", events[0].NewValue)
+	}
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+type ShareSQL struct {
+	TaskID    *uint64 `db:"task_id"`
+	ProjectID *uint64 `db:"project_id"`
+	Login     string  `db:"login"`
+	Role      string  `db:"role"`
+}
+
+// Convert DTO to entity
+func (s ShareSQL) entity() entities.Share {
+	return entities.Share{
+		TaskID:    s.TaskID,
+		ProjectID: s.ProjectID,
+		Login:     s.Login,
+		Role:      entities.Access(s.Role),
+	}
+}
+
+// visibleTask returns condition on tasks owned by or assigned to the user or shared with them directly
+// or through the project, param is the number of the user login argument.
+func visibleTask(param int) string {
+	return fmt.Sprintf(`(owner = $%[1]d OR assignee = $%[1]d OR EXISTS (SELECT 1 FROM task_shares sh
+		WHERE sh.login = $%[1]d AND (sh.task_id = tasks.id OR sh.project_id = tasks.project_id)))`, param)
+}
+
+// Shares returns shares of the task or the project ordered by login.
+func (s *Storage) Shares(ctx context.Context, target entities.Share) ([]entities.Share, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT task_id, project_id, login, role FROM task_shares
+		WHERE task_id = $1 OR project_id = $2 ORDER BY login`
+	rows, err := s.conn.Query(c, query, target.TaskID, target.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query shares from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	sharesSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[ShareSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	shares := make([]entities.Share, len(sharesSQL))
+	for i := range sharesSQL {
+		shares[i] = sharesSQL[i].entity()
+	}
+
+	return shares, nil
+}
+
+// ShareSet grants access to the task or the project, role of an existing share is replaced.
+func (s *Storage) ShareSet(ctx context.Context, share entities.Share) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `INSERT INTO task_shares (task_id, login, role) VALUES ($1, $2, $3)
+		ON CONFLICT (task_id, login) WHERE task_id IS NOT NULL DO UPDATE SET role = EXCLUDED.role`
+	args := []any{share.TaskID, share.Login, string(share.Role)}
+	if share.ProjectID != nil {
+		query = `INSERT INTO task_shares (project_id, login, role) VALUES ($1, $2, $3)
+			ON CONFLICT (project_id, login) WHERE project_id IS NOT NULL DO UPDATE SET role = EXCLUDED.role`
+		args[0] = share.ProjectID
+	}
+	if _, err := s.conn.Exec(c, query, args...); err != nil {
+		return fmt.Errorf("unable to set share in storage: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) ShareRemove(ctx context.Context, share entities.Share) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `DELETE FROM task_shares WHERE (task_id = $1 OR project_id = $2) AND login = $3`
+	row, err := s.conn.Exec(c, query, share.TaskID, share.ProjectID, share.Login)
+	if err != nil {
+		return fmt.Errorf("unable to remove share from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove share from storage: %w", entities.ErrNoShare)
+	}
+
+	return nil
+}
+
+// attachAccess sets access of the user to the tasks, tasks of other users are shared with the user
+// and the strongest of the task and project shares applies. Assignee of the task is its editor.
+func (s *Storage) attachAccess(ctx context.Context, tasks []entities.Task, login string) error {
+	var ids []uint64
+	index := make(map[uint64]int)
+	for i := range tasks {
+		if tasks[i].Owner == login {
+			tasks[i].Access = entities.AccessOwner
+			continue
+		}
+		if tasks[i].Assignee == login {
+			tasks[i].Access = entities.AccessEditor
+			continue
+		}
+		ids = append(ids, tasks[i].ID)
+		index[tasks[i].ID] = i
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT t.id, CASE WHEN bool_or(sh.role = 'editor') THEN 'editor' ELSE 'viewer' END
+		FROM tasks t JOIN task_shares sh ON sh.task_id = t.id OR sh.project_id = t.project_id
+		WHERE t.id = ANY($1) AND sh.login = $2 AND t.deleted_at IS NULL
+		GROUP BY t.id`
+	rows, err := s.conn.Query(c, query, ids, login)
+	if err != nil {
+		return fmt.Errorf("unable to get query task access from storage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID uint64
+		var role string
+		if err := rows.Scan(&taskID, &role); err != nil {
+			return fmt.Errorf("unable to get parse row to DTO: %w", err)
+		}
+		tasks[index[taskID]].Access = entities.Access(role)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to get query task access from storage: %w", err)
+	}
+
+	return nil
+}
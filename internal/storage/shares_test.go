@@ -0,0 +1,62 @@
+package storage_test
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestShares() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks, projects, task_shares RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	projectID, err := suite.storage.ProjectAdd(suite.ctx, entities.Project{Key: "WEB", Name: "Website"}, "test-user")
+	assert.NoError(t, err)
+	for _, task := range []entities.Task{
+		{Name: "shared", Status: entities.StatusTodo},
+		{Name: "in project", Status: entities.StatusTodo, ProjectID: &projectID},
+		{Name: "private", Status: entities.StatusTodo},
+	} {
+		_, err = suite.storage.TaskAdd(suite.ctx, task, "test-user")
+		assert.NoError(t, err)
+	}
+
+	taskID := uint64(1)
+	err = suite.storage.ShareSet(suite.ctx, entities.Share{TaskID: &taskID, Login: "test-user-2", Role: entities.AccessViewer})
+	assert.NoError(t, err)
+	err = suite.storage.ShareSet(suite.ctx, entities.Share{ProjectID: &projectID, Login: "test-user-2", Role: entities.AccessViewer})
+	assert.NoError(t, err)
+
+	// Existing share gets the new role
+	err = suite.storage.ShareSet(suite.ctx, entities.Share{TaskID: &taskID, Login: "test-user-2", Role: entities.AccessEditor})
+	assert.NoError(t, err)
+	shares, err := suite.storage.Shares(suite.ctx, entities.Share{TaskID: &taskID})
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Share{{TaskID: &taskID, Login: "test-user-2", Role: entities.AccessEditor}}, shares)
+
+	// Shared tasks are listed with access of the user
+	page, err := suite.storage.Tasks(suite.ctx, "test-user-2", entities.TaskFilter{}, entities.PageRequest{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(page.Tasks)) {
+		assert.Equal(t, entities.AccessEditor, page.Tasks[0].Access)
+		assert.Equal(t, entities.AccessViewer, page.Tasks[1].Access)
+	}
+
+	task, err := suite.storage.Task(suite.ctx, 2, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.AccessOwner, task.Access)
+	_, err = suite.storage.Task(suite.ctx, 3, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	// Removing the project share hides its tasks
+	err = suite.storage.ShareRemove(suite.ctx, entities.Share{ProjectID: &projectID, Login: "test-user-2"})
+	assert.NoError(t, err)
+	err = suite.storage.ShareRemove(suite.ctx, entities.Share{ProjectID: &projectID, Login: "test-user-2"})
+	assert.ErrorIs(t, err, entities.ErrNoShare)
+	_, err = suite.storage.Task(suite.ctx, 2, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+}
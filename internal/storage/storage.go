@@ -2,30 +2,105 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
 	"time"
 
 	"github.com/go-code-mentor/wp-task/internal/entities"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const rowsRetrieveTimeout = 10 * time.Second
 
 type Storage struct {
-	conn *pgx.Conn
+	conn *tenantConn
 }
 
-func New(conn *pgx.Conn) *Storage {
+func New(pool *pgxpool.Pool) *Storage {
 	return &Storage{
-		conn: conn,
+		conn: &tenantConn{pool: pool},
 	}
 }
 
+// taskColumns is the list of columns matching TaskSQL fields, key is built from the project key and task number.
+const taskColumns = `id, name, description, owner, status, priority, start_at, due_at, parent_id, series_id,
+	estimate_unit, original_estimate, remaining_estimate,
+	project_id, (SELECT p.key || '-' || project_number FROM projects p WHERE p.id = project_id) AS key, rank,
+	assignee, reporter, deleted_at`
+
 type TaskSQL struct {
-	ID          uint64 `db:"id"`
-	Name        string `db:"name"`
-	Description string `db:"description"`
-	Owner       string `db:"owner"`
+	ID          uint64     `db:"id"`
+	Name        string     `db:"name"`
+	Description string     `db:"description"`
+	Owner       string     `db:"owner"`
+	Status      string     `db:"status"`
+	Priority    int16      `db:"priority"`
+	StartAt     *time.Time `db:"start_at"`
+	DueAt       *time.Time `db:"due_at"`
+	ParentID    *uint64    `db:"parent_id"`
+	SeriesID    *uint64    `db:"series_id"`
+
+	EstimateUnit      *string `db:"estimate_unit"`
+	OriginalEstimate  *int    `db:"original_estimate"`
+	RemainingEstimate *int    `db:"remaining_estimate"`
+
+	ProjectID *uint64 `db:"project_id"`
+	Key       *string `db:"key"`
+	Rank      *string `db:"rank"`
+
+	Assignee *string `db:"assignee"`
+	Reporter *string `db:"reporter"`
+
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+// Convert DTO to entity
+func (t TaskSQL) entity() entities.Task {
+	var unit entities.EstimateUnit
+	if t.EstimateUnit != nil {
+		unit = entities.EstimateUnit(*t.EstimateUnit)
+	}
+	var key, rank, assignee, reporter string
+	if t.Key != nil {
+		key = *t.Key
+	}
+	if t.Rank != nil {
+		rank = *t.Rank
+	}
+	if t.Assignee != nil {
+		assignee = *t.Assignee
+	}
+	if t.Reporter != nil {
+		reporter = *t.Reporter
+	}
+	return entities.Task{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Owner:       t.Owner,
+		Status:      entities.TaskStatus(t.Status),
+		Priority:    entities.PriorityFromLevel(int(t.Priority)),
+		StartAt:     t.StartAt,
+		DueAt:       t.DueAt,
+		ParentID:    t.ParentID,
+		SeriesID:    t.SeriesID,
+
+		EstimateUnit:      unit,
+		OriginalEstimate:  t.OriginalEstimate,
+		RemainingEstimate: t.RemainingEstimate,
+
+		ProjectID: t.ProjectID,
+		Key:       key,
+		Rank:      rank,
+
+		Assignee: assignee,
+		Reporter: reporter,
+
+		DeletedAt: t.DeletedAt,
+	}
 }
 
 func (s *Storage) Task(ctx context.Context, id uint64, login string) (entities.Task, error) {
@@ -36,36 +111,181 @@ func (s *Storage) Task(ctx context.Context, id uint64, login string) (entities.T
 	var taskSQL TaskSQL
 
 	// Run SQL query
-	query := `SELECT id, name, description, owner FROM tasks WHERE id=$1 AND owner=$2`
-	row, err := s.conn.Query(c, query, id, login)
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id=$1 AND org_id=$3 AND deleted_at IS NULL AND ` + visibleTask(2)
+	row, err := s.conn.Query(c, query, id, login, orgID(ctx))
 	if err != nil {
 		return entities.Task{}, fmt.Errorf("unable to get query task from storage: %w", err)
 	}
 	defer row.Close()
 
-	taskSQL, err = pgx.CollectOneRow(row, pgx.RowToStructByPos[TaskSQL])
+	taskSQL, err = pgx.CollectOneRow(row, pgx.RowToStructByName[TaskSQL])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.Task{}, fmt.Errorf("unable to get task from storage: %w: %w", entities.ErrNoTask, err)
+	}
 	if err != nil {
 		return entities.Task{}, fmt.Errorf("unable to get parse row to DTO: %w", err)
 	}
 
+	tasks := []entities.Task{taskSQL.entity()}
+	if err := s.attachAccess(ctx, tasks, login); err != nil {
+		return entities.Task{}, err
+	}
+	if err := s.attachLabels(ctx, tasks); err != nil {
+		return entities.Task{}, err
+	}
+	if err := s.attachDependencies(ctx, &tasks[0]); err != nil {
+		return entities.Task{}, err
+	}
+	if err := s.attachChecklist(ctx, &tasks[0]); err != nil {
+		return entities.Task{}, err
+	}
+
+	return tasks[0], nil
+}
+
+// Tasks returns one page of tasks owned by or shared with the user using keyset pagination over the requested sort order.
+func (s *Storage) Tasks(ctx context.Context, login string, filter entities.TaskFilter, page entities.PageRequest) (entities.TaskPage, error) {
+	order, err := taskSortOrder(filter.Sort)
+	if err != nil {
+		return entities.TaskPage{}, fmt.Errorf("unable to get tasks from storage: %w", err)
+	}
+
+	// Build filter conditions
+	conditions := []string{visibleTask(1), "org_id = $2", "deleted_at IS NULL"}
+	args := []any{login, orgID(ctx)}
+	if filter.ProjectID != nil {
+		args = append(args, *filter.ProjectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+	if filter.Assignee != "" {
+		args = append(args, filter.Assignee)
+		conditions = append(conditions, fmt.Sprintf("assignee = $%d", len(args)))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i := range filter.Statuses {
+			statuses[i] = string(filter.Statuses[i])
+		}
+		args = append(args, statuses)
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+	if len(filter.Labels) > 0 {
+		args = append(args, filter.Labels)
+		labelsQuery := fmt.Sprintf(`SELECT count(DISTINCT l.name) FROM task_labels tl
+			JOIN labels l ON l.id = tl.label_id
+			WHERE tl.task_id = tasks.id AND l.name = ANY($%d)`, len(args))
+		if filter.LabelsAny {
+			conditions = append(conditions, "("+labelsQuery+") > 0")
+		} else {
+			args = append(args, len(uniqueStrings(filter.Labels)))
+			conditions = append(conditions, fmt.Sprintf("(%s) = $%d", labelsQuery, len(args)))
+		}
+	}
+
+	// Continue right after the row the cursor points to
+	if page.Cursor != "" {
+		values, err := decodeCursor(order, page.Cursor)
+		if err != nil {
+			return entities.TaskPage{}, fmt.Errorf("unable to get tasks from storage: %w", err)
+		}
+		var condition string
+		condition, args = keysetCondition(order, values, args)
+		conditions = append(conditions, condition)
+	}
+
+	// Fetch one extra row to know whether the next page exists
+	limit := page.Limit
+	if limit <= 0 {
+		limit = entities.DefaultPageLimit
+	}
+	args = append(args, limit+1)
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY ` + orderBy(order) + fmt.Sprintf(` LIMIT $%d`, len(args))
+	tasksSQL, err := s.queryTasksSQL(ctx, query, args...)
+	if err != nil {
+		return entities.TaskPage{}, err
+	}
+
+	var result entities.TaskPage
+	if len(tasksSQL) > limit {
+		tasksSQL = tasksSQL[:limit]
+		result.NextCursor, err = encodeCursor(order, tasksSQL[limit-1])
+		if err != nil {
+			return entities.TaskPage{}, fmt.Errorf("unable to get tasks from storage: %w", err)
+		}
+	}
+
 	// Convert DTO to entity
-	task := entities.Task{
-		ID:          taskSQL.ID,
-		Name:        taskSQL.Name,
-		Description: taskSQL.Description,
-		Owner:       taskSQL.Owner,
+	result.Tasks = make([]entities.Task, len(tasksSQL))
+	for i := range tasksSQL {
+		result.Tasks[i] = tasksSQL[i].entity()
 	}
-	return task, nil
+
+	if err := s.attachAccess(ctx, result.Tasks, login); err != nil {
+		return entities.TaskPage{}, err
+	}
+	if err := s.attachLabels(ctx, result.Tasks); err != nil {
+		return entities.TaskPage{}, err
+	}
+
+	return result, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	var result []string
+	for _, v := range values {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// TasksOverdue returns not completed tasks which due date is before now.
+func (s *Storage) TasksOverdue(ctx context.Context, login string, now time.Time) ([]entities.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE owner=$1 AND org_id=$3 AND deleted_at IS NULL AND due_at IS NOT NULL AND due_at < $2 AND status NOT IN ('done', 'cancelled')
+		ORDER BY due_at`
+	return s.queryTasks(ctx, query, login, now, orgID(ctx))
+}
+
+// TasksUpcoming returns not completed tasks which due date is in [from, to) interval.
+func (s *Storage) TasksUpcoming(ctx context.Context, login string, from time.Time, to time.Time) ([]entities.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE owner=$1 AND org_id=$4 AND deleted_at IS NULL AND due_at IS NOT NULL AND due_at >= $2 AND due_at < $3 AND status NOT IN ('done', 'cancelled')
+		ORDER BY due_at`
+	return s.queryTasks(ctx, query, login, from, to, orgID(ctx))
+}
+
+func (s *Storage) queryTasks(ctx context.Context, query string, args ...any) ([]entities.Task, error) {
+	tasksSQL, err := s.queryTasksSQL(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to entity
+	tasks := make([]entities.Task, len(tasksSQL))
+	for i := range tasksSQL {
+		tasks[i] = tasksSQL[i].entity()
+	}
+
+	if err := s.attachLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
 }
 
-func (s *Storage) Tasks(ctx context.Context, login string) ([]entities.Task, error) {
+func (s *Storage) queryTasksSQL(ctx context.Context, query string, args ...any) ([]TaskSQL, error) {
 	// Create context with timeout for SQL query
 	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
 	defer cancel()
 
 	// Run SQL query
-	query := `SELECT id, name, description, owner FROM tasks WHERE owner=$1`
-	rows, err := s.conn.Query(c, query, login)
+	rows, err := s.conn.Query(c, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get query tasks from storage: %w", err)
 	}
@@ -78,28 +298,40 @@ func (s *Storage) Tasks(ctx context.Context, login string) ([]entities.Task, err
 		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
 	}
 
-	// Convert DTO to entity
-	tasks := make([]entities.Task, len(tasksSQL))
-	for i := range tasksSQL {
-		tasks[i] = entities.Task{
-			ID:          tasksSQL[i].ID,
-			Name:        tasksSQL[i].Name,
-			Description: tasksSQL[i].Description,
-			Owner:       tasksSQL[i].Owner,
-		}
-	}
-
-	return tasks, nil
+	return tasksSQL, nil
 }
 
-func (s *Storage) TaskRemove(ctx context.Context, id uint64, login string) error {
+// TaskRemove moves the task of the owner to trash, access of the user is checked by the caller.
+// Trashed tasks are hidden from every other method until they are restored.
+func (s *Storage) TaskRemove(ctx context.Context, id uint64, login string, mode entities.RemoveMode) error {
 	// Create context with timeout for SQL query
 	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
 	defer cancel()
 
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Lock the task so that no subtasks are added concurrently
+	var taskID uint64
+	query := `SELECT id FROM tasks WHERE id=$1 AND org_id=$2 AND deleted_at IS NULL FOR UPDATE`
+	err = tx.QueryRow(c, query, id, orgID(ctx)).Scan(&taskID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("unable to remove task from storage: %w", entities.ErrNoTask)
+	}
+	if err != nil {
+		return fmt.Errorf("unbale to remove task from storage: %w", err)
+	}
+
+	if err := removeSubtasks(c, tx, id, login, mode); err != nil {
+		return fmt.Errorf("unable to remove task from storage: %w", err)
+	}
+
 	// Run SQL query
-	query := `DELETE FROM tasks WHERE id=$1 and owner=$2`
-	row, err := s.conn.Exec(c, query, id, login)
+	query = `UPDATE tasks SET deleted_at = now() WHERE id=$1`
+	row, err := tx.Exec(c, query, id)
 	if err != nil {
 		return fmt.Errorf("unbale to remove task from storage: %w", err)
 	}
@@ -107,17 +339,65 @@ func (s *Storage) TaskRemove(ctx context.Context, id uint64, login string) error
 		return fmt.Errorf("unable to remove task from storage: %w", entities.ErrNoTask)
 	}
 
+	// Timers of trashed tasks could not be stopped otherwise, now() is the same for the whole transaction
+	query = `UPDATE time_entries SET stopped_at = GREATEST(now(), started_at)
+		WHERE stopped_at IS NULL AND task_id IN (SELECT id FROM tasks WHERE deleted_at = now() AND org_id = $1)`
+	if _, err := tx.Exec(c, query, orgID(ctx)); err != nil {
+		return fmt.Errorf("unable to stop timers of removed task: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
+// TaskUpdate changes the task of the owner, parent and project are looked up among the owner ones.
+// Empty reporter keeps the current one. Access of the user is checked by the caller.
+// Changes of name and description are kept in the task history.
 func (s *Storage) TaskUpdate(ctx context.Context, task entities.Task, login string) error {
 	// Create context with timeout for SQL query
 	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
 	defer cancel()
 
-	// Run SQL query
-	query := `UPDATE tasks SET name = $1, description = $2 WHERE id = $3 and owner=$4`
-	row, err := s.conn.Exec(c, query, task.Name, task.Description, task.ID, login)
+	// Empty priority keeps the current one
+	var priority *int16
+	if task.Priority != "" {
+		level := int16(task.Priority.Level())
+		priority = &level
+	}
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	if task.ParentID != nil {
+		if err := checkParent(c, tx, task.ID, *task.ParentID, login); err != nil {
+			return fmt.Errorf("unable to update task in storage: %w", err)
+		}
+	}
+	number, err := taskNumber(c, tx, task.ID, task.ProjectID, login)
+	if err != nil {
+		return fmt.Errorf("unable to update task in storage: %w", err)
+	}
+	previous, err := taskFields(c, tx, task.ID)
+	if err != nil {
+		return fmt.Errorf("unable to update task in storage: %w", err)
+	}
+
+	// Run SQL query, empty estimate unit keeps the current one and estimates in minutes are assumed by default
+	query := `UPDATE tasks SET name = $1, description = $2, start_at = $3, due_at = $4, priority = COALESCE($5, priority), parent_id = $6,
+			original_estimate = $8, remaining_estimate = $9,
+			estimate_unit = CASE WHEN $8::integer IS NULL AND $9::integer IS NULL THEN NULL
+				ELSE COALESCE(NULLIF($10, ''), estimate_unit, 'minutes') END,
+			project_id = $11, project_number = $12,
+			assignee = NULLIF($13, ''), reporter = COALESCE(NULLIF($14, ''), reporter)
+		WHERE id = $7 AND org_id = $15 AND deleted_at IS NULL`
+	row, err := tx.Exec(c, query, task.Name, task.Description, task.StartAt, task.DueAt, priority, task.ParentID, task.ID,
+		task.OriginalEstimate, task.RemainingEstimate, string(task.EstimateUnit), task.ProjectID, number, task.Assignee, task.Reporter, orgID(ctx))
 	if err != nil {
 		return fmt.Errorf("unable to update task in storage: %w", err)
 	}
@@ -125,6 +405,87 @@ func (s *Storage) TaskUpdate(ctx context.Context, task entities.Task, login stri
 		return fmt.Errorf("unable to update task in storage: %w", entities.ErrNoTask)
 	}
 
+	// Changes are recorded in the same transaction so that history never misses an update,
+	// values are read back since some of them are kept or defaulted by the query
+	changed, err := taskFields(c, tx, task.ID)
+	if err != nil {
+		return fmt.Errorf("unable to update task in storage: %w", err)
+	}
+	if err := recordChanges(c, tx, task.ID, eventAuthor(ctx, login), previous, changed); err != nil {
+		return fmt.Errorf("unable to update task in storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// TaskStatusUpdate moves the task to another status, sets ranks and adds the next occurrence of the change
+// in one transaction. The status must not exceed WIP limits of the user boards.
+func (s *Storage) TaskStatusUpdate(ctx context.Context, change entities.StatusChange, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	previous, err := taskFields(c, tx, change.ID)
+	if errors.Is(err, entities.ErrNoTask) {
+		return fmt.Errorf("unable to update task status in storage: %w", entities.ErrInvalidTransition)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to update task status in storage: %w", err)
+	}
+
+	if change.From != change.To {
+		var projectID *uint64
+		if err := tx.QueryRow(c, "SELECT project_id FROM tasks WHERE id = $1", change.ID).Scan(&projectID); err != nil {
+			return fmt.Errorf("unable to update task status in storage: %w", err)
+		}
+		if err := checkWIPLimits(c, tx, change.ID, projectID, change.To, login); err != nil {
+			return fmt.Errorf("unable to update task status in storage: %w", err)
+		}
+	}
+
+	// Run SQL query, status condition protects from concurrent transitions
+	// Completion time is kept only while the task is done
+	query := `UPDATE tasks SET status = $1, completed_at = CASE WHEN $1 = 'done' THEN now() END
+		WHERE id = $2 AND owner = $3 AND status = $4 AND org_id = $5 AND deleted_at IS NULL`
+	row, err := tx.Exec(c, query, string(change.To), change.ID, login, string(change.From), orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to update task status in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to update task status in storage: %w", entities.ErrInvalidTransition)
+	}
+
+	if len(change.Ranks) > 0 {
+		if err := taskRanksUpdate(c, tx, change.Ranks, login); err != nil {
+			return fmt.Errorf("unable to update task ranks in storage: %w", err)
+		}
+	}
+	if change.Next != nil {
+		if err := occurrenceInsert(c, tx, *change.Next, login); err != nil {
+			return fmt.Errorf("unable to add next task occurrence to storage: %w", err)
+		}
+	}
+
+	changed := maps.Clone(previous)
+	changed[entities.TaskFieldStatus] = string(change.To)
+	if err := recordChanges(c, tx, change.ID, eventAuthor(ctx, login), previous, changed); err != nil {
+		return fmt.Errorf("unable to update task status in storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -133,20 +494,118 @@ func (s *Storage) TaskAdd(ctx context.Context, task entities.Task, login string)
 	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
 	defer cancel()
 
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return 0, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	if err := checkWIPLimits(c, tx, 0, task.ProjectID, task.Status, login); err != nil {
+		return 0, fmt.Errorf("unable to add task to storage: %w", err)
+	}
+	id, err := taskInsert(c, tx, task, login)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add task to storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return 0, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+// taskInsert adds the task of the user within the transaction.
+func taskInsert(ctx context.Context, tx pgx.Tx, task entities.Task, login string) (uint64, error) {
 	// Convert entity to DTO
+	if task.Priority == "" {
+		task.Priority = entities.DefaultPriority
+	}
+	var unit *string
+	if task.OriginalEstimate != nil || task.RemainingEstimate != nil {
+		if task.EstimateUnit == "" {
+			task.EstimateUnit = entities.EstimateMinutes
+		}
+		unit = (*string)(&task.EstimateUnit)
+	}
 	taskSQL := TaskSQL{
 		Name:        task.Name,
 		Description: task.Description,
 		Owner:       login,
+		Status:      string(task.Status),
+		Priority:    int16(task.Priority.Level()),
+		StartAt:     task.StartAt,
+		DueAt:       task.DueAt,
+		ParentID:    task.ParentID,
+		SeriesID:    task.SeriesID,
+
+		EstimateUnit:      unit,
+		OriginalEstimate:  task.OriginalEstimate,
+		RemainingEstimate: task.RemainingEstimate,
 	}
 	var taskID int64
 
-	// Run SQL query
-	query := "INSERT INTO tasks (name, description, owner) VALUES ($1, $2, $3) RETURNING id"
-	err := s.conn.QueryRow(c, query, taskSQL.Name, taskSQL.Description, taskSQL.Owner).Scan(&taskID)
+	if task.ParentID != nil {
+		if err := checkParent(ctx, tx, 0, *task.ParentID, login); err != nil {
+			return 0, err
+		}
+	}
+	number, err := taskNumber(ctx, tx, 0, task.ProjectID, login)
 	if err != nil {
-		return 0, fmt.Errorf("unable to add task to storage: %w", err)
+		return 0, err
+	}
+
+	// Recurring task starts a new series anchored at its due date
+	if task.Recurrence != "" && task.DueAt != nil {
+		query := "INSERT INTO task_series (owner, rule, start_at) VALUES ($1, $2, $3) RETURNING id"
+		if err := tx.QueryRow(ctx, query, login, task.Recurrence, *task.DueAt).Scan(&taskSQL.SeriesID); err != nil {
+			return 0, fmt.Errorf("unable to add task series: %w", err)
+		}
+	}
+
+	// Run SQL query, the owner reports the task by default
+	query := `INSERT INTO tasks (name, description, owner, status, priority, start_at, due_at, parent_id, series_id, estimate_unit, original_estimate, remaining_estimate,
+			project_id, project_number, assignee, reporter, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), COALESCE(NULLIF($16, ''), $3), $17) RETURNING id`
+	err = tx.QueryRow(ctx, query, taskSQL.Name, taskSQL.Description, taskSQL.Owner, taskSQL.Status, taskSQL.Priority, taskSQL.StartAt, taskSQL.DueAt, taskSQL.ParentID, taskSQL.SeriesID,
+		taskSQL.EstimateUnit, taskSQL.OriginalEstimate, taskSQL.RemainingEstimate, task.ProjectID, number, task.Assignee, task.Reporter, orgID(ctx)).Scan(&taskID)
+	if isConstraintViolation(err, occurrenceIndex) {
+		return 0, entities.ErrOccurrenceExists
+	}
+	if err != nil {
+		return 0, err
 	}
 
 	return uint64(taskID), nil
 }
+
+// occurrenceInsert adds the next occurrence with labels of the completed one. Savepoint keeps
+// the transaction usable when the occurrence already exists, which happens when the completed
+// task was reopened and completed again.
+func occurrenceInsert(ctx context.Context, tx pgx.Tx, next entities.Task, login string) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sp.Rollback(ctx) }()
+
+	id, err := taskInsert(ctx, sp, next, login)
+	if errors.Is(err, entities.ErrOccurrenceExists) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	labels := make([]uint64, 0, len(next.Labels))
+	for _, label := range next.Labels {
+		labels = append(labels, label.ID)
+	}
+	query := `INSERT INTO task_labels (task_id, label_id)
+		SELECT $1, id FROM labels WHERE id = ANY($2) AND owner = $3 AND org_id = $4`
+	if _, err := sp.Exec(ctx, query, id, labels, login, orgID(ctx)); err != nil {
+		return err
+	}
+
+	return sp.Commit(ctx)
+}
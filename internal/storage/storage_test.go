@@ -2,6 +2,7 @@ package storage_test
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-code-mentor/wp-task/internal/entities"
 	"github.com/go-code-mentor/wp-task/internal/storage"
 	"github.com/go-code-mentor/wp-task/internal/testhelper"
@@ -9,9 +10,11 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type Suite struct {
@@ -20,6 +23,8 @@ type Suite struct {
 	storage     *storage.Storage
 	ctx         context.Context
 	conn        *pgx.Conn
+	pool        *pgxpool.Pool
+	orgID       uint64
 }
 
 func (suite *Suite) SetupSuite() {
@@ -44,13 +49,40 @@ func (suite *Suite) SetupSuite() {
 		suite.T().Fatalf("failed to up migration: %s", err)
 	}
 
-	repository := storage.New(conn)
+	pool, err := pgxpool.New(context.Background(), suite.pgContainer.ConnectionString)
+	if err != nil {
+		suite.T().Fatalf("could not create db pool: %s", err)
+	}
+	suite.pool = pool
+
+	repository := storage.New(pool)
 	suite.storage = repository
 
 	suite.conn = conn
+
+	// Tests work in a single organization as its owner, fixtures inserted by plain SQL land in it too
+	query := `WITH org AS (INSERT INTO organizations (name, personal_login) VALUES ($1, $1) RETURNING id)
+		INSERT INTO memberships (org_id, login, role) SELECT id, $1, 'owner' FROM org`
+	if _, err := conn.Exec(suite.ctx, query, "test-user"); err != nil {
+		suite.T().Fatalf("failed to add organization: %s", err)
+	}
+	org, err := repository.UserOrg(suite.ctx, "test-user", 0)
+	if err != nil {
+		suite.T().Fatalf("failed to get organization: %s", err)
+	}
+	suite.orgID = org.ID
+	suite.ctx = context.WithValue(suite.ctx, entities.UserLoginKey, "test-user")
+	suite.ctx = context.WithValue(suite.ctx, entities.OrgIDKey, org.ID)
+	for _, table := range []string{"tasks", "projects", "labels", "boards"} {
+		query := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN org_id SET DEFAULT %d", table, org.ID)
+		if _, err := conn.Exec(suite.ctx, query); err != nil {
+			suite.T().Fatalf("failed to set default organization: %s", err)
+		}
+	}
 }
 
 func (suite *Suite) TearDownSuite() {
+	suite.pool.Close()
 	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
 		suite.T().Fatalf("error terminating postgres container: %s", err)
 	}
@@ -60,9 +92,10 @@ func (suite *Suite) TestGetTasks() {
 	t := suite.T()
 
 	t.Run("success getting empty tasks list", func(t *testing.T) {
-		list, err := suite.storage.Tasks(suite.ctx, "test-user")
+		page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{}, entities.PageRequest{})
 		assert.NoError(t, err)
-		assert.NotNil(t, list)
+		assert.NotNil(t, page.Tasks)
+		assert.Empty(t, page.NextCursor)
 	})
 
 	t.Run("success getting tasks list", func(t *testing.T) {
@@ -71,6 +104,8 @@ func (suite *Suite) TestGetTasks() {
 			Name:        "test-task-1",
 			Description: "test-task-1",
 			Owner:       "test-user-1",
+			Status:      entities.StatusTodo,
+			Priority:    entities.PriorityP2,
 		}
 
 		task2 := entities.Task{
@@ -78,6 +113,8 @@ func (suite *Suite) TestGetTasks() {
 			Name:        "test-task-2",
 			Description: "test-task-2",
 			Owner:       "test-user-2",
+			Status:      entities.StatusTodo,
+			Priority:    entities.PriorityP2,
 		}
 
 		query := "INSERT INTO tasks (name, description, owner) VALUES ($1, $2, $3),($4, $5, $6)"
@@ -85,19 +122,19 @@ func (suite *Suite) TestGetTasks() {
 		assert.NoError(t, err)
 		assert.Equal(t, int64(2), res.RowsAffected())
 		defer func() {
-			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY")
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
 			assert.NoError(t, err)
 		}()
 
-		list1, err := suite.storage.Tasks(suite.ctx, "test-user-1")
+		page1, err := suite.storage.Tasks(suite.ctx, "test-user-1", entities.TaskFilter{}, entities.PageRequest{})
 		assert.NoError(t, err)
-		assert.Equal(t, 1, len(list1))
-		assert.Equal(t, task1, list1[0])
+		assert.Equal(t, 1, len(page1.Tasks))
+		assert.Equal(t, task1, page1.Tasks[0])
 
-		list2, err := suite.storage.Tasks(suite.ctx, "test-user-2")
+		page2, err := suite.storage.Tasks(suite.ctx, "test-user-2", entities.TaskFilter{}, entities.PageRequest{})
 		assert.NoError(t, err)
-		assert.Equal(t, 1, len(list2))
-		assert.Equal(t, task2, list2[0])
+		assert.Equal(t, 1, len(page2.Tasks))
+		assert.Equal(t, task2, page2.Tasks[0])
 
 	})
 }
@@ -111,6 +148,8 @@ func (suite *Suite) TestGetTask() {
 			Name:        "test-task",
 			Description: "test-task",
 			Owner:       "test-user",
+			Status:      entities.StatusTodo,
+			Priority:    entities.PriorityP2,
 		}
 
 		query := "INSERT INTO tasks (name, description, owner) VALUES ($1, $2, $3)"
@@ -118,7 +157,7 @@ func (suite *Suite) TestGetTask() {
 		assert.NoError(t, err)
 		assert.Equal(t, int64(1), res.RowsAffected())
 		defer func() {
-			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY")
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
 			assert.NoError(t, err)
 		}()
 
@@ -153,7 +192,7 @@ func (suite *Suite) TestGetTask() {
 		assert.NoError(t, err)
 		assert.Equal(t, int64(2), res.RowsAffected())
 		defer func() {
-			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY")
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
 			assert.NoError(t, err)
 		}()
 
@@ -180,22 +219,27 @@ func (suite *Suite) TestRemoveTasks() {
 		assert.NoError(t, err)
 		assert.Equal(t, int64(1), res.RowsAffected())
 		defer func() {
-			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY")
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
 			assert.NoError(t, err)
 		}()
 
-		err = suite.storage.TaskRemove(suite.ctx, 1, "test-user")
+		err = suite.storage.TaskRemove(suite.ctx, 1, "test-user", entities.RemoveReject)
 		assert.NoError(t, err)
 
+		// Task is kept in trash
 		var taskSQL storage.TaskSQL
-		query = `SELECT id, name, description, owner FROM tasks WHERE id=$1 AND owner=$2`
-		err = suite.conn.QueryRow(suite.ctx, query, 1, "test-user").Scan(&taskSQL.ID, &taskSQL.Name, &taskSQL.Description, &taskSQL.Owner)
-		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		query = `SELECT id, name, description, owner, deleted_at FROM tasks WHERE id=$1 AND owner=$2`
+		err = suite.conn.QueryRow(suite.ctx, query, 1, "test-user").Scan(&taskSQL.ID, &taskSQL.Name, &taskSQL.Description, &taskSQL.Owner, &taskSQL.DeletedAt)
+		assert.NoError(t, err)
+		assert.NotNil(t, taskSQL.DeletedAt)
+
+		_, err = suite.storage.Task(suite.ctx, 1, "test-user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
 
 	})
 
 	t.Run("removing unexisted task", func(t *testing.T) {
-		err := suite.storage.TaskRemove(suite.ctx, 1, "test-user")
+		err := suite.storage.TaskRemove(suite.ctx, 1, "test-user", entities.RemoveReject)
 		assert.ErrorIs(t, err, entities.ErrNoTask)
 
 	})
@@ -223,7 +267,7 @@ func (suite *Suite) TestUpdateTask() {
 		assert.NoError(t, err)
 		assert.Equal(t, int64(1), res.RowsAffected())
 		defer func() {
-			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY")
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
 			assert.NoError(t, err)
 		}()
 
@@ -236,7 +280,7 @@ func (suite *Suite) TestUpdateTask() {
 		assert.NoError(t, err)
 		defer row.Close()
 
-		task, err = pgx.CollectOneRow(row, pgx.RowToStructByPos[storage.TaskSQL])
+		task, err = pgx.CollectOneRow(row, pgx.RowToStructByNameLax[storage.TaskSQL])
 		assert.NoError(t, err)
 		assert.Equal(t, updTask.ID, task.ID)
 		assert.Equal(t, updTask.Name, task.Name)
@@ -257,7 +301,7 @@ func (suite *Suite) TestUpdateTask() {
 			assert.ErrorIs(t, err, entities.ErrNoTask)
 		}
 
-		_, err = suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY")
+		_, err = suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
 		assert.NoError(t, err)
 	})
 }
@@ -287,9 +331,219 @@ func (suite *Suite) TestAddTask() {
 		assert.Equal(t, task.Description, taskSQL.Description)
 		assert.Equal(t, task.Owner, taskSQL.Owner)
 
-		_, err = suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY")
+		_, err = suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+
+	})
+}
+
+func (suite *Suite) TestGetTasksByStatus() {
+	t := suite.T()
+
+	t.Run("success filtering tasks by status", func(t *testing.T) {
+		query := "INSERT INTO tasks (name, description, owner, status) VALUES ($1, $2, $3, $4),($5, $6, $7, $8),($9, $10, $11, $12)"
+		res, err := suite.conn.Exec(suite.ctx, query,
+			"test-task-1", "", "test-user", entities.StatusTodo,
+			"test-task-2", "", "test-user", entities.StatusDone,
+			"test-task-3", "", "test-user", entities.StatusCancelled)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(3), res.RowsAffected())
+		defer func() {
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+			assert.NoError(t, err)
+		}()
+
+		page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{
+			Statuses: []entities.TaskStatus{entities.StatusDone, entities.StatusCancelled},
+		}, entities.PageRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(page.Tasks))
+		for _, task := range page.Tasks {
+			assert.NotEqual(t, entities.StatusTodo, task.Status)
+		}
+	})
+}
+
+func (suite *Suite) TestUpdateTaskStatus() {
+	t := suite.T()
+
+	t.Run("success updating task status", func(t *testing.T) {
+		query := "INSERT INTO tasks (name, description, owner) VALUES ($1, $2, $3)"
+		_, err := suite.conn.Exec(suite.ctx, query, "test-task", "test-task", "test-user")
+		assert.NoError(t, err)
+		defer func() {
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+			assert.NoError(t, err)
+		}()
+
+		err = suite.storage.TaskStatusUpdate(suite.ctx, entities.StatusChange{ID: 1, From: entities.StatusTodo, To: entities.StatusInProgress}, "test-user")
+		assert.NoError(t, err)
+
+		task, err := suite.storage.Task(suite.ctx, 1, "test-user")
+		assert.NoError(t, err)
+		assert.Equal(t, entities.StatusInProgress, task.Status)
+	})
+
+	t.Run("updating task status with stale current status", func(t *testing.T) {
+		query := "INSERT INTO tasks (name, description, owner, status) VALUES ($1, $2, $3, $4)"
+		_, err := suite.conn.Exec(suite.ctx, query, "test-task", "test-task", "test-user", entities.StatusDone)
+		assert.NoError(t, err)
+		defer func() {
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+			assert.NoError(t, err)
+		}()
+
+		err = suite.storage.TaskStatusUpdate(suite.ctx, entities.StatusChange{ID: 1, From: entities.StatusTodo, To: entities.StatusInProgress}, "test-user")
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+	})
+}
+
+func (suite *Suite) TestGetTasksByDueDate() {
+	t := suite.T()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+	nextWeek := now.Add(7 * 24 * time.Hour)
+
+	query := `INSERT INTO tasks (name, description, owner, status, due_at) VALUES
+		('overdue', '', 'test-user', 'todo', $1),
+		('overdue-done', '', 'test-user', 'done', $1),
+		('tomorrow', '', 'test-user', 'in_progress', $2),
+		('next-week', '', 'test-user', 'todo', $3),
+		('no-due-date', '', 'test-user', 'todo', NULL),
+		('foreign-overdue', '', 'test-user-2', 'todo', $1)`
+	_, err := suite.conn.Exec(suite.ctx, query, yesterday, tomorrow, nextWeek)
+	assert.NoError(t, err)
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	t.Run("success getting overdue tasks", func(t *testing.T) {
+		list, err := suite.storage.TasksOverdue(suite.ctx, "test-user", now)
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(list)) {
+			assert.Equal(t, "overdue", list[0].Name)
+			assert.True(t, yesterday.Equal(*list[0].DueAt))
+		}
+	})
+
+	t.Run("success getting upcoming tasks", func(t *testing.T) {
+		list, err := suite.storage.TasksUpcoming(suite.ctx, "test-user", now, now.Add(72*time.Hour))
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(list)) {
+			assert.Equal(t, "tomorrow", list[0].Name)
+		}
+	})
+}
+
+func (suite *Suite) TestGetTasksSorted() {
+	t := suite.T()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	query := `INSERT INTO tasks (name, description, owner, priority, due_at) VALUES
+		('p3', '', 'test-user', 3, $1),
+		('p0-late', '', 'test-user', 0, $2),
+		('p0-early', '', 'test-user', 0, $1),
+		('p1-no-due', '', 'test-user', 1, NULL)`
+	_, err := suite.conn.Exec(suite.ctx, query, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	t.Run("success sorting by priority and due date", func(t *testing.T) {
+		page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{
+			Sort: []entities.SortKey{{Field: "priority", Desc: true}, {Field: "due_at"}, {Field: "id"}},
+		}, entities.PageRequest{})
+		assert.NoError(t, err)
+
+		names := make([]string, len(page.Tasks))
+		for i := range page.Tasks {
+			names[i] = page.Tasks[i].Name
+		}
+		assert.Equal(t, []string{"p0-early", "p0-late", "p1-no-due", "p3"}, names)
+		assert.Equal(t, entities.PriorityP0, page.Tasks[0].Priority)
+	})
+
+	t.Run("sorting by not whitelisted field", func(t *testing.T) {
+		_, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{
+			Sort: []entities.SortKey{{Field: "owner; DROP TABLE tasks"}},
+		}, entities.PageRequest{})
+		assert.ErrorIs(t, err, entities.ErrInvalidSort)
+	})
+}
+
+func (suite *Suite) TestGetTasksPaginated() {
+	t := suite.T()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	query := `INSERT INTO tasks (name, description, owner, priority, due_at) VALUES
+		('t1', '', 'test-user', 1, $1),
+		('t2', '', 'test-user', 0, NULL),
+		('t3', '', 'test-user', 1, NULL),
+		('t4', '', 'test-user', 3, $2),
+		('t5', '', 'test-user', 0, $2),
+		('t6', '', 'test-user', 1, $2),
+		('t7', '', 'test-user-2', 1, $2)`
+	_, err := suite.conn.Exec(suite.ctx, query, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	collect := func(t *testing.T, filter entities.TaskFilter, limit int) []string {
+		var names []string
+		page := entities.PageRequest{Limit: limit}
+		for {
+			result, err := suite.storage.Tasks(suite.ctx, "test-user", filter, page)
+			if !assert.NoError(t, err) {
+				return names
+			}
+			assert.LessOrEqual(t, len(result.Tasks), limit)
+			for _, task := range result.Tasks {
+				names = append(names, task.Name)
+			}
+			if result.NextCursor == "" {
+				return names
+			}
+			page.Cursor = result.NextCursor
+		}
+	}
+
+	t.Run("success paging in default order", func(t *testing.T) {
+		names := collect(t, entities.TaskFilter{}, 4)
+		assert.Equal(t, []string{"t1", "t2", "t3", "t4", "t5", "t6"}, names)
+	})
+
+	t.Run("success paging with nullable sort keys", func(t *testing.T) {
+		filter := entities.TaskFilter{
+			Sort: []entities.SortKey{{Field: "priority", Desc: true}, {Field: "due_at"}},
+		}
+		for _, limit := range []int{1, 2, 5} {
+			names := collect(t, filter, limit)
+			assert.Equal(t, []string{"t5", "t2", "t1", "t6", "t3", "t4"}, names)
+		}
+	})
+
+	t.Run("cursor used with another sort order", func(t *testing.T) {
+		result, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{}, entities.PageRequest{Limit: 1})
+		assert.NoError(t, err)
+
+		_, err = suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{
+			Sort: []entities.SortKey{{Field: "name"}},
+		}, entities.PageRequest{Limit: 1, Cursor: result.NextCursor})
+		assert.ErrorIs(t, err, entities.ErrInvalidCursor)
+	})
 
+	t.Run("malformed cursor", func(t *testing.T) {
+		_, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{}, entities.PageRequest{Cursor: "!!!"})
+		assert.ErrorIs(t, err, entities.ErrInvalidCursor)
 	})
 }
 
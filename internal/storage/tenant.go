@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TenantRole is the database role storage statements run as, row level security policies apply to it.
+const TenantRole = "wp_task_tenant"
+
+// tenantConn runs every statement in a transaction scoped to the request: the tenant role is taken and
+// the active organization and the login of the request are set for row level security policies.
+// A missing clause in a query can not leak rows of another organization this way.
+// Every transaction holds a connection of the pool of its own until it ends, settings of concurrent
+// requests never meet in one session.
+type tenantConn struct {
+	pool *pgxpool.Pool
+}
+
+// Begin starts a scoped transaction, the settings are local to it and are reset on its end.
+func (t *tenantConn) Begin(ctx context.Context) (pgx.Tx, error) {
+	return t.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// BeginTx starts a scoped transaction with the options, a serializable one for instance.
+func (t *tenantConn) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := t.pool.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var org string
+	if id := orgID(ctx); id != 0 {
+		org = strconv.FormatUint(id, 10)
+	}
+	login := requestLogin(ctx)
+
+	query := `SELECT set_config('role', $1, true), set_config('app.org_id', $2, true), set_config('app.login', $3, true)`
+	if _, err := tx.Exec(ctx, query, TenantRole, org, login); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, fmt.Errorf("unable to scope transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// BeginUnscoped starts a transaction as the connecting user, row level security does not apply to it.
+// It is meant for maintenance jobs working across organizations, never for requests.
+func (t *tenantConn) BeginUnscoped(ctx context.Context) (pgx.Tx, error) {
+	return t.pool.Begin(ctx)
+}
+
+// requestLogin returns the authenticated login of the request, it is empty outside of requests.
+func requestLogin(ctx context.Context) string {
+	login, _ := ctx.Value(entities.UserLoginKey).(string)
+	return login
+}
+
+func (t *tenantConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tx, err := t.Begin(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return tag, err
+	}
+
+	return tag, tx.Commit(ctx)
+}
+
+// Query keeps the transaction open until the rows are closed.
+func (t *tenantConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	tx, err := t.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+
+	return &tenantRows{Rows: rows, ctx: ctx, tx: tx}, nil
+}
+
+func (t *tenantConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := t.Query(ctx, sql, args...)
+	return &tenantRow{rows: rows, err: err}
+}
+
+// tenantRows commits the transaction of the query when the rows are closed,
+// commit error is reported by Err.
+type tenantRows struct {
+	pgx.Rows
+	ctx    context.Context
+	tx     pgx.Tx
+	err    error
+	closed bool
+}
+
+func (r *tenantRows) Close() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+
+	r.Rows.Close()
+	if r.Rows.Err() != nil {
+		_ = r.tx.Rollback(r.ctx)
+		return
+	}
+	r.err = r.tx.Commit(r.ctx)
+}
+
+func (r *tenantRows) Err() error {
+	if err := r.Rows.Err(); err != nil {
+		return err
+	}
+	return r.err
+}
+
+// tenantRow follows pgx.Row, missing row is reported as pgx.ErrNoRows.
+type tenantRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *tenantRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+
+	r.rows.Close()
+	return r.rows.Err()
+}
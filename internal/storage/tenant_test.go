@@ -0,0 +1,110 @@
+package storage_test
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/go-code-mentor/wp-task/internal/storage"
+)
+
+func (suite *Suite) TestTenantRowLevelSecurity() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+		_, err = suite.conn.Exec(suite.ctx, "DELETE FROM organizations WHERE id <> $1", suite.orgID)
+		assert.NoError(t, err)
+	}()
+
+	// Organization of another user with a task of the same owner login
+	var foreignID uint64
+	err := suite.conn.QueryRow(suite.ctx, "INSERT INTO organizations (name) VALUES ('foreign') RETURNING id").Scan(&foreignID)
+	assert.NoError(t, err)
+	_, err = suite.conn.Exec(suite.ctx, "INSERT INTO memberships (org_id, login, role) VALUES ($1, 'stranger', 'owner')", foreignID)
+	assert.NoError(t, err)
+
+	query := "INSERT INTO tasks (name, description, owner, org_id) VALUES ($1, '', 'test-user', $2), ($3, '', 'test-user', $4)"
+	_, err = suite.conn.Exec(suite.ctx, query, "own", suite.orgID, "foreign", foreignID)
+	assert.NoError(t, err)
+
+	names := func(ctx context.Context) []string {
+		// No owner nor organization clause, policies filter the rows
+		rows, err := suite.storage.TenantQuery(ctx, "SELECT name FROM tasks ORDER BY id")
+		assert.NoError(t, err)
+		result, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		assert.NoError(t, err)
+		return result
+	}
+
+	// Comments and time entries of both tasks, their rows follow the visibility of the task
+	query = "INSERT INTO comments (task_id, author, body) SELECT id, 'test-user', name FROM tasks"
+	_, err = suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+	query = "INSERT INTO time_entries (task_id, login, started_at, note, org_id) SELECT id, 'test-user', now(), name, org_id FROM tasks"
+	_, err = suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	column := func(ctx context.Context, query string) []string {
+		rows, err := suite.storage.TenantQuery(ctx, query)
+		assert.NoError(t, err)
+		result, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		assert.NoError(t, err)
+		return result
+	}
+
+	assert.Equal(t, []string{"own"}, names(suite.ctx))
+	assert.Equal(t, []string{"own"}, column(suite.ctx, "SELECT body FROM comments ORDER BY id"))
+	assert.Equal(t, []string{"own"}, column(suite.ctx, "SELECT note FROM time_entries ORDER BY id"))
+
+	// Active organization the login is not a member of shows nothing
+	foreign := context.WithValue(suite.ctx, entities.OrgIDKey, foreignID)
+	assert.Empty(t, names(foreign))
+	assert.Empty(t, column(foreign, "SELECT body FROM comments"))
+	assert.Empty(t, column(foreign, "SELECT note FROM time_entries"))
+
+	// Nor does a request without login or organization
+	assert.Empty(t, names(context.WithValue(suite.ctx, entities.UserLoginKey, "")))
+	assert.Empty(t, names(context.WithValue(suite.ctx, entities.OrgIDKey, uint64(0))))
+
+	// Concurrent requests of different organizations keep their settings apart
+	stranger := context.WithValue(foreign, entities.UserLoginKey, "stranger")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, []string{"own"}, names(suite.ctx))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, []string{"foreign"}, names(stranger))
+		}()
+	}
+	wg.Wait()
+
+	// Rows can not be written into a foreign organization
+	_, err = suite.storage.TenantExec(suite.ctx, "INSERT INTO tasks (name, description, owner, org_id) VALUES ('sneaky', '', 'test-user', $1)", foreignID)
+	assert.Error(t, err)
+	tag, err := suite.storage.TenantExec(suite.ctx, "UPDATE tasks SET name = 'changed' WHERE org_id = $1", foreignID)
+	assert.NoError(t, err)
+	assert.Zero(t, tag.RowsAffected())
+	var foreignTask uint64
+	err = suite.conn.QueryRow(suite.ctx, "SELECT id FROM tasks WHERE org_id = $1", foreignID).Scan(&foreignTask)
+	assert.NoError(t, err)
+	_, err = suite.storage.TenantExec(suite.ctx, "INSERT INTO comments (task_id, author, body) VALUES ($1, 'test-user', 'sneaky')", foreignTask)
+	assert.Error(t, err)
+	tag, err = suite.storage.TenantExec(suite.ctx, "DELETE FROM time_entries WHERE org_id = $1", foreignID)
+	assert.NoError(t, err)
+	assert.Zero(t, tag.RowsAffected())
+
+	// Connection leaves the tenant role after each statement
+	var role string
+	err = suite.conn.QueryRow(suite.ctx, "SELECT current_user").Scan(&role)
+	assert.NoError(t, err)
+	assert.NotEqual(t, storage.TenantRole, role)
+}
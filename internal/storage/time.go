@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+const timeEntryColumns = `id, task_id, login, started_at, stopped_at, note`
+
+type TimeEntrySQL struct {
+	ID        uint64     `db:"id"`
+	TaskID    uint64     `db:"task_id"`
+	Login     string     `db:"login"`
+	StartedAt time.Time  `db:"started_at"`
+	StoppedAt *time.Time `db:"stopped_at"`
+	Note      string     `db:"note"`
+}
+
+// Convert DTO to time entry entity
+func (e TimeEntrySQL) entity() entities.TimeEntry {
+	return entities.TimeEntry{
+		ID:        e.ID,
+		TaskID:    e.TaskID,
+		Login:     e.Login,
+		StartedAt: e.StartedAt,
+		StoppedAt: e.StoppedAt,
+		Note:      e.Note,
+	}
+}
+
+// orgTimeEntry returns condition on time entries logged on not trashed tasks of the organization,
+// param is the number of the organization argument.
+func orgTimeEntry(param int) string {
+	return fmt.Sprintf(`task_id IN (SELECT id FROM tasks WHERE org_id = $%d AND deleted_at IS NULL)`, param)
+}
+
+// TimerStart starts timer of the user on the task of the owner, the database allows only one running timer per user
+// in the organization.
+func (s *Storage) TimerStart(ctx context.Context, taskID uint64, login string, owner string, now time.Time) (entities.TimeEntry, error) {
+	query := `INSERT INTO time_entries (task_id, login, started_at, org_id)
+		SELECT id, $2, $3, org_id FROM tasks WHERE id=$1 AND owner=$5 AND org_id=$4 AND deleted_at IS NULL
+		RETURNING ` + timeEntryColumns
+	entry, err := s.queryTimeEntry(ctx, query, taskID, login, now, orgID(ctx), owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entry, fmt.Errorf("unable to start timer in storage: %w", entities.ErrNoTask)
+	}
+	if isUniqueViolation(err) {
+		return entry, fmt.Errorf("unable to start timer in storage: %w", entities.ErrTimerRunning)
+	}
+	if err != nil {
+		return entry, fmt.Errorf("unable to start timer in storage: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (s *Storage) TimerStop(ctx context.Context, taskID uint64, login string, now time.Time) (entities.TimeEntry, error) {
+	query := `UPDATE time_entries SET stopped_at = GREATEST($3, started_at)
+		WHERE task_id=$1 AND login=$2 AND stopped_at IS NULL AND ` + orgTimeEntry(4) + `
+		RETURNING ` + timeEntryColumns
+	entry, err := s.queryTimeEntry(ctx, query, taskID, login, now, orgID(ctx))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entry, fmt.Errorf("unable to stop timer in storage: %w", entities.ErrNoTimer)
+	}
+	if err != nil {
+		return entry, fmt.Errorf("unable to stop timer in storage: %w", err)
+	}
+
+	return entry, nil
+}
+
+// TimeEntries returns entries logged by the user on the task, latest first.
+func (s *Storage) TimeEntries(ctx context.Context, taskID uint64, login string) ([]entities.TimeEntry, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries
+		WHERE task_id=$1 AND login=$2 AND ` + orgTimeEntry(3) + ` ORDER BY started_at DESC, id DESC`
+	rows, err := s.conn.Query(c, query, taskID, login, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get query time entries from storage: %w", err)
+	}
+	defer rows.Close()
+
+	entriesSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[TimeEntrySQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to get parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	entries := make([]entities.TimeEntry, len(entriesSQL))
+	for i := range entriesSQL {
+		entries[i] = entriesSQL[i].entity()
+	}
+
+	return entries, nil
+}
+
+// TimeEntryAdd logs finished entry of the entry login on the task of the owner.
+func (s *Storage) TimeEntryAdd(ctx context.Context, entry entities.TimeEntry, owner string) (uint64, error) {
+	query := `INSERT INTO time_entries (task_id, login, started_at, stopped_at, note, org_id)
+		SELECT id, $2, $3, $4, $5, org_id FROM tasks WHERE id=$1 AND owner=$7 AND org_id=$6 AND deleted_at IS NULL
+		RETURNING ` + timeEntryColumns
+	added, err := s.queryTimeEntry(ctx, query, entry.TaskID, entry.Login, entry.StartedAt, entry.StoppedAt, entry.Note, orgID(ctx), owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("unable to add time entry to storage: %w", entities.ErrNoTask)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to add time entry to storage: %w", err)
+	}
+
+	return added.ID, nil
+}
+
+func (s *Storage) TimeEntryUpdate(ctx context.Context, entry entities.TimeEntry, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query, running timers are changed only by stopping them
+	query := `UPDATE time_entries SET started_at=$1, stopped_at=$2, note=$3
+		WHERE id=$4 AND task_id=$5 AND login=$6 AND stopped_at IS NOT NULL AND ` + orgTimeEntry(7)
+	row, err := s.conn.Exec(c, query, entry.StartedAt, entry.StoppedAt, entry.Note, entry.ID, entry.TaskID, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to update time entry in storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to update time entry in storage: %w", entities.ErrNoTimeEntry)
+	}
+
+	return nil
+}
+
+func (s *Storage) TimeEntryRemove(ctx context.Context, taskID uint64, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `DELETE FROM time_entries WHERE id=$1 AND task_id=$2 AND login=$3 AND ` + orgTimeEntry(4)
+	row, err := s.conn.Exec(c, query, id, taskID, login, orgID(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to remove time entry from storage: %w", err)
+	}
+	if row.RowsAffected() == 0 {
+		return fmt.Errorf("unable to remove time entry from storage: %w", entities.ErrNoTimeEntry)
+	}
+
+	return nil
+}
+
+// TimeReport sums time logged by the user within [from, to) per task and per label.
+// Entries crossing the period bounds are clipped, running timers count up to now.
+func (s *Storage) TimeReport(ctx context.Context, login string, from time.Time, to time.Time, now time.Time) (entities.TimeReport, error) {
+	report := entities.TimeReport{From: from, To: to}
+
+	clipped := `SELECT e.task_id,
+			GREATEST(EXTRACT(EPOCH FROM LEAST(COALESCE(e.stopped_at, $4), $3) - GREATEST(e.started_at, $2)), 0) AS seconds
+		FROM time_entries e
+		WHERE e.login=$1 AND e.started_at < $3 AND COALESCE(e.stopped_at, $4) > $2 AND ` + orgTimeEntry(5)
+
+	var err error
+	query := `WITH clipped AS (` + clipped + `)
+		SELECT t.id, t.name, (SUM(c.seconds) / 3600)::float8 FROM clipped c
+		JOIN tasks t ON t.id = c.task_id
+		GROUP BY t.id, t.name
+		ORDER BY 3 DESC, t.id`
+	if report.Tasks, err = s.queryTimeReportRows(ctx, query, login, from, to, now, orgID(ctx)); err != nil {
+		return report, fmt.Errorf("unable to get task time report from storage: %w", err)
+	}
+
+	query = `WITH clipped AS (` + clipped + `)
+		SELECT l.id, l.name, (SUM(c.seconds) / 3600)::float8 FROM clipped c
+		JOIN task_labels tl ON tl.task_id = c.task_id
+		JOIN labels l ON l.id = tl.label_id
+		GROUP BY l.id, l.name
+		ORDER BY 3 DESC, l.id`
+	if report.Labels, err = s.queryTimeReportRows(ctx, query, login, from, to, now, orgID(ctx)); err != nil {
+		return report, fmt.Errorf("unable to get label time report from storage: %w", err)
+	}
+
+	for _, row := range report.Tasks {
+		report.Total += row.Hours
+	}
+
+	return report, nil
+}
+
+func (s *Storage) queryTimeReportRows(ctx context.Context, query string, args ...any) ([]entities.TimeReportRow, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	rows, err := s.conn.Query(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []entities.TimeReportRow
+	for rows.Next() {
+		var row entities.TimeReportRow
+		if err := rows.Scan(&row.ID, &row.Name, &row.Hours); err != nil {
+			return nil, fmt.Errorf("unable to get parse row to DTO: %w", err)
+		}
+		res = append(res, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Storage) queryTimeEntry(ctx context.Context, query string, args ...any) (entities.TimeEntry, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	rows, err := s.conn.Query(c, query, args...)
+	if err != nil {
+		return entities.TimeEntry{}, err
+	}
+	defer rows.Close()
+
+	entrySQL, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[TimeEntrySQL])
+	if err != nil {
+		return entities.TimeEntry{}, err
+	}
+
+	return entrySQL.entity(), nil
+}
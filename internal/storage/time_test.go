@@ -0,0 +1,134 @@
+package storage_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTimer() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	query := `INSERT INTO tasks (name, description, owner) VALUES ('first', '', 'test-user'), ('second', '', 'test-user')`
+	_, err := suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	entry, err := suite.storage.TimerStart(suite.ctx, 1, "test-user", "test-user", now)
+	assert.NoError(t, err)
+	assert.Nil(t, entry.StoppedAt)
+
+	// Only one timer may run per user
+	_, err = suite.storage.TimerStart(suite.ctx, 2, "test-user", "test-user", now)
+	assert.ErrorIs(t, err, entities.ErrTimerRunning)
+
+	// Timer of another organization is not in the way
+	otherOrg, err := suite.storage.OrgAdd(suite.ctx, entities.Organization{Name: "other"}, "test-user")
+	assert.NoError(t, err)
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "DELETE FROM organizations WHERE id = $1", otherOrg)
+		assert.NoError(t, err)
+	}()
+	var otherTask uint64
+	err = suite.conn.QueryRow(suite.ctx, "INSERT INTO tasks (name, owner, org_id) VALUES ('other', 'test-user', $1) RETURNING id", otherOrg).Scan(&otherTask)
+	assert.NoError(t, err)
+	otherCtx := context.WithValue(suite.ctx, entities.OrgIDKey, otherOrg)
+	_, err = suite.storage.TimerStart(otherCtx, otherTask, "test-user", "test-user", now)
+	assert.NoError(t, err)
+
+	_, err = suite.storage.TimerStart(suite.ctx, 1, "test-user-2", "test-user-2", now)
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	// Timer on a shared task belongs to the user who started it
+	entry, err = suite.storage.TimerStart(suite.ctx, 1, "test-user-2", "test-user", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-user-2", entry.Login)
+	_, err = suite.storage.TimerStop(suite.ctx, 1, "test-user-2", now)
+	assert.NoError(t, err)
+
+	_, err = suite.storage.TimerStop(suite.ctx, 2, "test-user", now)
+	assert.ErrorIs(t, err, entities.ErrNoTimer)
+
+	entry, err = suite.storage.TimerStop(suite.ctx, 1, "test-user", now.Add(time.Hour))
+	assert.NoError(t, err)
+	if assert.NotNil(t, entry.StoppedAt) {
+		assert.True(t, now.Add(time.Hour).Equal(*entry.StoppedAt))
+	}
+
+	_, err = suite.storage.TimerStart(suite.ctx, 2, "test-user", "test-user", now.Add(time.Hour))
+	assert.NoError(t, err)
+}
+
+func (suite *Suite) TestTimeEntries() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks, labels RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	query := `INSERT INTO tasks (name, description, owner) VALUES ('first', '', 'test-user'), ('second', '', 'test-user')`
+	_, err := suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+	query = `INSERT INTO labels (owner, name) VALUES ('test-user', 'billable')`
+	_, err = suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+	query = `INSERT INTO task_labels (task_id, label_id) VALUES (1, 1)`
+	_, err = suite.conn.Exec(suite.ctx, query)
+	assert.NoError(t, err)
+
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) *time.Time {
+		t := day.Add(time.Duration(hour) * time.Hour)
+		return &t
+	}
+
+	id, err := suite.storage.TimeEntryAdd(suite.ctx, entities.TimeEntry{TaskID: 1, Login: "test-user", StartedAt: *at(9), StoppedAt: at(11), Note: "design"}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.TimeEntryAdd(suite.ctx, entities.TimeEntry{TaskID: 2, Login: "test-user", StartedAt: *at(23), StoppedAt: at(25)}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.TimeEntryAdd(suite.ctx, entities.TimeEntry{TaskID: 1, Login: "test-user-2", StartedAt: *at(9), StoppedAt: at(11)}, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	entries, err := suite.storage.TimeEntries(suite.ctx, 1, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(entries)) {
+		assert.Equal(t, "design", entries[0].Note)
+	}
+
+	err = suite.storage.TimeEntryUpdate(suite.ctx, entities.TimeEntry{ID: id, TaskID: 1, StartedAt: *at(8), StoppedAt: at(11)}, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TimeEntryUpdate(suite.ctx, entities.TimeEntry{ID: id, TaskID: 2, StartedAt: *at(8), StoppedAt: at(11)}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTimeEntry)
+
+	// Entry crossing the end of the period is clipped, running timer counts up to now
+	_, err = suite.storage.TimerStart(suite.ctx, 1, "test-user", "test-user", *at(20))
+	assert.NoError(t, err)
+
+	report, err := suite.storage.TimeReport(suite.ctx, "test-user", day, *at(24), *at(22))
+	assert.NoError(t, err)
+	assert.InDelta(t, 6.0, report.Total, 0.001)
+	if assert.Equal(t, 2, len(report.Tasks)) {
+		assert.Equal(t, "first", report.Tasks[0].Name)
+		assert.InDelta(t, 5.0, report.Tasks[0].Hours, 0.001)
+		assert.InDelta(t, 1.0, report.Tasks[1].Hours, 0.001)
+	}
+	if assert.Equal(t, 1, len(report.Labels)) {
+		assert.Equal(t, "billable", report.Labels[0].Name)
+		assert.InDelta(t, 5.0, report.Labels[0].Hours, 0.001)
+	}
+
+	err = suite.storage.TimeEntryRemove(suite.ctx, 1, id, "test-user")
+	assert.NoError(t, err)
+	err = suite.storage.TimeEntryRemove(suite.ctx, 1, id, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTimeEntry)
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+type AccessTokenSQL struct {
+	ID         uint64     `db:"id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"token_prefix"`
+	Scopes     []string   `db:"scopes"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+// Convert DTO to entity
+func (t AccessTokenSQL) entity() entities.AccessToken {
+	return entities.AccessToken{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     scopesEntity(t.Scopes),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+func scopesEntity(scopes []string) []entities.Scope {
+	result := make([]entities.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, entities.Scope(scope))
+	}
+	return result
+}
+
+func scopesSQL(scopes []entities.Scope) []string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, string(scope))
+	}
+	return result
+}
+
+// Tokens returns access tokens of the user which are not revoked, expired ones are kept to show when they expired.
+func (s *Storage) Tokens(ctx context.Context, login string) ([]entities.AccessToken, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT t.id, t.name, t.token_prefix, t.scopes, t.created_at, t.expires_at, t.last_used_at FROM access_tokens t
+		JOIN users u ON u.id = t.user_id WHERE u.login=$1 AND t.revoked_at IS NULL ORDER BY t.id`
+	rows, err := s.conn.Query(c, query, login)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get access tokens from storage: %w", err)
+	}
+	defer rows.Close()
+
+	// Parse SQL query to DTO
+	tokensSQL, err := pgx.CollectRows(rows, pgx.RowToStructByName[AccessTokenSQL])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse rows to DTO: %w", err)
+	}
+
+	// Convert DTO to entity
+	tokens := make([]entities.AccessToken, 0, len(tokensSQL))
+	for _, t := range tokensSQL {
+		tokens = append(tokens, t.entity())
+	}
+
+	return tokens, nil
+}
+
+// TokenAdd stores a new access token of the user by its prefix and keyed hash, the token itself is never stored.
+func (s *Storage) TokenAdd(ctx context.Context, token entities.AccessToken, hash string, login string) (uint64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	var id uint64
+
+	// Run SQL query
+	query := `INSERT INTO access_tokens (user_id, token_prefix, token_hash, name, scopes, expires_at)
+		SELECT id, $1, $2, $3, $4, $5 FROM users WHERE login=$6 RETURNING id`
+	err := s.conn.QueryRow(c, query, token.Prefix, hash, token.Name, scopesSQL(token.Scopes), token.ExpiresAt, login).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("unable to add access token: %w", entities.ErrNoUser)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to add access token: %w", err)
+	}
+
+	return id, nil
+}
+
+// TokenRevoke revokes the access token of the user, it stops authenticating immediately.
+func (s *Storage) TokenRevoke(ctx context.Context, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE access_tokens t SET revoked_at = now() FROM users u
+		WHERE t.id=$1 AND u.id = t.user_id AND u.login=$2 AND t.revoked_at IS NULL`
+	tag, err := s.conn.Exec(c, query, id, login)
+	if err != nil {
+		return fmt.Errorf("unable to revoke access token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("unable to revoke access token: %w", entities.ErrNoToken)
+	}
+
+	return nil
+}
+
+// PlainTokens returns access tokens which are still stored as plaintext by their ids.
+func (s *Storage) PlainTokens(ctx context.Context) (map[uint64]string, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `SELECT id, token FROM access_tokens WHERE token IS NOT NULL`
+	rows, err := s.conn.Query(c, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get plaintext access tokens from storage: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make(map[uint64]string)
+	for rows.Next() {
+		var id uint64
+		var token string
+		if err := rows.Scan(&id, &token); err != nil {
+			return nil, fmt.Errorf("unable to parse plaintext access token: %w", err)
+		}
+		tokens[id] = token
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to get plaintext access tokens from storage: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// TokenHashSet replaces the plaintext access token with its prefix and keyed hash.
+func (s *Storage) TokenHashSet(ctx context.Context, id uint64, prefix string, hash string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE access_tokens SET token = NULL, token_prefix=$2, token_hash=$3 WHERE id=$1 AND token IS NOT NULL`
+	tag, err := s.conn.Exec(c, query, id, prefix, hash)
+	if err != nil {
+		return fmt.Errorf("unable to hash access token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("unable to hash access token: %w", entities.ErrNoToken)
+	}
+
+	return nil
+}
+
+// TokenRehash replaces the plaintext access token with its prefix and keyed hash when such a token is stored.
+func (s *Storage) TokenRehash(ctx context.Context, token string, prefix string, hash string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	// Run SQL query
+	query := `UPDATE access_tokens SET token = NULL, token_prefix=$2, token_hash=$3 WHERE token=$1`
+	tag, err := s.conn.Exec(c, query, token, prefix, hash)
+	if err != nil {
+		return fmt.Errorf("unable to hash access token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("unable to hash access token: %w", entities.ErrNoToken)
+	}
+
+	return nil
+}
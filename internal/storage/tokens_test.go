@@ -0,0 +1,110 @@
+package storage_test
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTokens() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE users RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	_, err := suite.conn.Exec(suite.ctx, "INSERT INTO users (login) VALUES ($1), ($2)", "token-user", "token-user-2")
+	assert.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	id, err := suite.storage.TokenAdd(suite.ctx, entities.AccessToken{Name: "ci", Prefix: "wpt_prefix", Scopes: []entities.Scope{entities.ScopeTasksWrite}, ExpiresAt: &expiresAt}, "hash", "token-user")
+	assert.NoError(t, err)
+
+	_, err = suite.storage.TokenAdd(suite.ctx, entities.AccessToken{Name: "ci", Scopes: []entities.Scope{entities.ScopeRead}}, "other", "ghost")
+	assert.ErrorIs(t, err, entities.ErrNoUser)
+
+	login, scopes, err := suite.storage.GetUserLogin(suite.ctx, "wpt_prefix", "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "token-user", login)
+	assert.Equal(t, []entities.Scope{entities.ScopeTasksWrite}, scopes)
+
+	// Both the prefix and the hash must match
+	_, _, err = suite.storage.GetUserLogin(suite.ctx, "", "hash")
+	assert.Error(t, err)
+
+	tokens, err := suite.storage.Tokens(suite.ctx, "token-user")
+	assert.NoError(t, err)
+	if assert.Len(t, tokens, 1) {
+		assert.Equal(t, id, tokens[0].ID)
+		assert.Equal(t, "ci", tokens[0].Name)
+		assert.Equal(t, "wpt_prefix", tokens[0].Prefix)
+		assert.Equal(t, []entities.Scope{entities.ScopeTasksWrite}, tokens[0].Scopes)
+		assert.True(t, expiresAt.Equal(*tokens[0].ExpiresAt))
+		assert.NotNil(t, tokens[0].LastUsedAt)
+	}
+
+	// Tokens of other users can not be revoked
+	err = suite.storage.TokenRevoke(suite.ctx, id, "token-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoToken)
+
+	err = suite.storage.TokenRevoke(suite.ctx, id, "token-user")
+	assert.NoError(t, err)
+	err = suite.storage.TokenRevoke(suite.ctx, id, "token-user")
+	assert.ErrorIs(t, err, entities.ErrNoToken)
+
+	_, _, err = suite.storage.GetUserLogin(suite.ctx, "wpt_prefix", "hash")
+	assert.Error(t, err)
+	tokens, err = suite.storage.Tokens(suite.ctx, "token-user")
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
+func (suite *Suite) TestTokenHashSet() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE users RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	var userID uint64
+	err := suite.conn.QueryRow(suite.ctx, "INSERT INTO users (login) VALUES ($1) RETURNING id", "token-user").Scan(&userID)
+	assert.NoError(t, err)
+	var id uint64
+	err = suite.conn.QueryRow(suite.ctx, "INSERT INTO access_tokens (user_id, token) VALUES ($1, $2) RETURNING id", userID, "legacy").Scan(&id)
+	assert.NoError(t, err)
+
+	tokens, err := suite.storage.PlainTokens(suite.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint64]string{id: "legacy"}, tokens)
+
+	err = suite.storage.TokenHashSet(suite.ctx, id, "", "hash")
+	assert.NoError(t, err)
+	err = suite.storage.TokenHashSet(suite.ctx, id, "", "hash")
+	assert.ErrorIs(t, err, entities.ErrNoToken)
+
+	// The plaintext token is gone, the hash authenticates instead
+	tokens, err = suite.storage.PlainTokens(suite.ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+	login, scopes, err := suite.storage.GetUserLogin(suite.ctx, "", "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "token-user", login)
+	assert.Equal(t, []entities.Scope{entities.ScopeRead}, scopes)
+
+	// Plaintext token stored after the startup is hashed on its first use
+	err = suite.conn.QueryRow(suite.ctx, "INSERT INTO access_tokens (user_id, token) VALUES ($1, $2) RETURNING id", userID, "late").Scan(&id)
+	assert.NoError(t, err)
+	_, _, err = suite.storage.GetUserLogin(suite.ctx, "", "late-hash")
+	assert.ErrorIs(t, err, entities.ErrNoToken)
+	err = suite.storage.TokenRehash(suite.ctx, "late", "", "late-hash")
+	assert.NoError(t, err)
+	err = suite.storage.TokenRehash(suite.ctx, "late", "", "late-hash")
+	assert.ErrorIs(t, err, entities.ErrNoToken)
+	login, _, err = suite.storage.GetUserLogin(suite.ctx, "", "late-hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "token-user", login)
+}
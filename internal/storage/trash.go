@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+// Trash returns trashed tasks of the owner, recently removed first.
+func (s *Storage) Trash(ctx context.Context, login string) ([]entities.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE owner=$1 AND org_id=$2 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id`
+	tasks, err := s.queryTasks(ctx, query, login, orgID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get trash from storage: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// TaskRestore takes the task out of trash together with subtasks trashed along with it.
+// Task which parent stays in trash becomes a top level one.
+func (s *Storage) TaskRestore(ctx context.Context, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	deletedAt, err := lockTrashedTask(c, tx, id, login)
+	if err != nil {
+		return fmt.Errorf("unable to restore task in storage: %w", err)
+	}
+
+	// Restored task counts against WIP limits of its status again
+	var status string
+	var projectID *uint64
+	if err := tx.QueryRow(c, "SELECT status, project_id FROM tasks WHERE id = $1", id).Scan(&status, &projectID); err != nil {
+		return fmt.Errorf("unable to restore task in storage: %w", err)
+	}
+	if err := checkWIPLimits(c, tx, id, projectID, entities.TaskStatus(status), login); err != nil {
+		return fmt.Errorf("unable to restore task in storage: %w", err)
+	}
+
+	// Run SQL query
+	query := `WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at = $2
+		)
+		UPDATE tasks SET deleted_at = NULL WHERE id IN (SELECT id FROM subtree)`
+	_, err = tx.Exec(c, query, id, deletedAt)
+	if isConstraintViolation(err, occurrenceIndex) {
+		return fmt.Errorf("unable to restore task in storage: %w", entities.ErrOccurrenceExists)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to restore task in storage: %w", err)
+	}
+
+	query = `UPDATE tasks SET parent_id = NULL
+		WHERE id = $1 AND parent_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL)`
+	if _, err := tx.Exec(c, query, id); err != nil {
+		return fmt.Errorf("unable to detach restored task: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// TaskPurge permanently removes the trashed task together with its trashed subtasks.
+func (s *Storage) TaskPurge(ctx context.Context, id uint64, login string) error {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(c)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	if _, err := lockTrashedTask(c, tx, id, login); err != nil {
+		return fmt.Errorf("unable to purge task from storage: %w", err)
+	}
+
+	// Run SQL query, not trashed task never has a trashed parent
+	query := `WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+		)
+		DELETE FROM tasks WHERE id IN (SELECT id FROM subtree)`
+	if _, err := tx.Exec(c, query, id); err != nil {
+		return fmt.Errorf("unable to purge task from storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// TrashPurge permanently removes tasks of all organizations trashed before the time and returns their number.
+func (s *Storage) TrashPurge(ctx context.Context, before time.Time) (int64, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	tx, err := s.conn.BeginUnscoped(c)
+	if err != nil {
+		return 0, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(c) }()
+
+	// Subtasks trashed later than their parent outlive it
+	query := `UPDATE tasks SET parent_id = NULL
+		WHERE parent_id IN (SELECT id FROM tasks WHERE deleted_at < $1) AND deleted_at >= $1`
+	if _, err := tx.Exec(c, query, before); err != nil {
+		return 0, fmt.Errorf("unable to detach purged tasks: %w", err)
+	}
+
+	// Run SQL query
+	row, err := tx.Exec(c, `DELETE FROM tasks WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("unable to purge trash from storage: %w", err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return 0, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return row.RowsAffected(), nil
+}
+
+// lockTrashedTask locks the trashed task of the owner and returns the time it was trashed.
+func lockTrashedTask(ctx context.Context, q querier, id uint64, login string) (time.Time, error) {
+	var deletedAt time.Time
+	query := `SELECT deleted_at FROM tasks WHERE id=$1 AND owner=$2 AND org_id=$3 AND deleted_at IS NOT NULL FOR UPDATE`
+	err := q.QueryRow(ctx, query, id, login, orgID(ctx)).Scan(&deletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return deletedAt, entities.ErrNoTask
+	}
+	if err != nil {
+		return deletedAt, err
+	}
+
+	return deletedAt, nil
+}
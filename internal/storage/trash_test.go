@@ -0,0 +1,100 @@
+package storage_test
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTrash() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	root, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "root", Status: entities.StatusTodo}, "test-user")
+	assert.NoError(t, err)
+	child, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "child", Status: entities.StatusTodo, ParentID: &root}, "test-user")
+	assert.NoError(t, err)
+	other, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "other", Status: entities.StatusTodo}, "test-user")
+	assert.NoError(t, err)
+	_, err = suite.storage.CommentAdd(suite.ctx, entities.Comment{TaskID: child, Author: "test-user", Body: "hi"}, "test-user")
+	assert.NoError(t, err)
+
+	err = suite.storage.TaskRemove(suite.ctx, root, "test-user", entities.RemoveCascade)
+	assert.NoError(t, err)
+
+	// Trashed tasks are hidden from reads
+	page, err := suite.storage.Tasks(suite.ctx, "test-user", entities.TaskFilter{}, entities.PageRequest{})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(page.Tasks)) {
+		assert.Equal(t, other, page.Tasks[0].ID)
+	}
+	_, err = suite.storage.Task(suite.ctx, child, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	comments, err := suite.storage.Comments(suite.ctx, child, "test-user", entities.PageRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, comments.Comments)
+	err = suite.storage.TaskUpdate(suite.ctx, entities.Task{ID: root, Name: "changed"}, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	err = suite.storage.TaskRemove(suite.ctx, root, "test-user", entities.RemoveCascade)
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	trash, err := suite.storage.Trash(suite.ctx, "test-user")
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(trash)) {
+		assert.NotNil(t, trash[0].DeletedAt)
+	}
+	trash, err = suite.storage.Trash(suite.ctx, "test-user-2")
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+
+	// Restore brings back the subtree trashed along with the task
+	err = suite.storage.TaskRestore(suite.ctx, root, "test-user-2")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	err = suite.storage.TaskRestore(suite.ctx, root, "test-user")
+	assert.NoError(t, err)
+	task, err := suite.storage.Task(suite.ctx, child, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, &root, task.ParentID)
+	assert.Nil(t, task.DeletedAt)
+	err = suite.storage.TaskRestore(suite.ctx, root, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+
+	// Subtask restored without its parent becomes a top level task
+	err = suite.storage.TaskRemove(suite.ctx, root, "test-user", entities.RemoveCascade)
+	assert.NoError(t, err)
+	err = suite.storage.TaskRestore(suite.ctx, child, "test-user")
+	assert.NoError(t, err)
+	task, err = suite.storage.Task(suite.ctx, child, "test-user")
+	assert.NoError(t, err)
+	assert.Nil(t, task.ParentID)
+
+	// Permanent deletion works on trashed tasks only
+	err = suite.storage.TaskPurge(suite.ctx, child, "test-user")
+	assert.ErrorIs(t, err, entities.ErrNoTask)
+	err = suite.storage.TaskPurge(suite.ctx, root, "test-user")
+	assert.NoError(t, err)
+	trash, err = suite.storage.Trash(suite.ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+
+	// Purge respects retention
+	err = suite.storage.TaskRemove(suite.ctx, other, "test-user", entities.RemoveReject)
+	assert.NoError(t, err)
+	count, err := suite.storage.TrashPurge(suite.ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = suite.storage.TrashPurge(suite.ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	var left int
+	err = suite.conn.QueryRow(suite.ctx, "SELECT count(*) FROM tasks").Scan(&left)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, left)
+}
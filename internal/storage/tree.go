@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// querier is implemented by both connection and transaction.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// checkParent verifies that task can be placed under the parent: parent must belong to the user,
+// must not be the task itself or its descendant, and the resulting tree must not exceed MaxTaskDepth.
+// Zero taskID is used for new tasks.
+func checkParent(ctx context.Context, q querier, taskID uint64, parentID uint64, login string) error {
+	var found int
+	var cycle bool
+	var parentDepth int
+
+	// Walk up from the parent, recursion is bounded in case of broken data
+	query := `WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 1 AS depth FROM tasks WHERE id = $1 AND owner = $2 AND org_id = $5 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, t.parent_id, a.depth + 1 FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+			WHERE a.depth <= $3
+		)
+		SELECT count(*), COALESCE(bool_or(id = $4), false), COALESCE(max(depth), 0) FROM ancestors`
+	err := q.QueryRow(ctx, query, parentID, login, entities.MaxTaskDepth, taskID, orgID(ctx)).Scan(&found, &cycle, &parentDepth)
+	if err != nil {
+		return fmt.Errorf("unable to check task parent: %w", err)
+	}
+	if found == 0 {
+		return entities.ErrInvalidParent
+	}
+	if cycle {
+		return entities.ErrTaskCycle
+	}
+
+	// Height of the moved subtree, a new task is a single level
+	height := 1
+	if taskID != 0 {
+		query = `WITH RECURSIVE subtree AS (
+				SELECT id, 1 AS depth FROM tasks WHERE id = $1
+				UNION ALL
+				SELECT t.id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+				WHERE t.deleted_at IS NULL AND s.depth <= $2
+			)
+			SELECT COALESCE(max(depth), 1) FROM subtree`
+		if err := q.QueryRow(ctx, query, taskID, entities.MaxTaskDepth).Scan(&height); err != nil {
+			return fmt.Errorf("unable to check task subtree: %w", err)
+		}
+	}
+
+	if parentDepth+height > entities.MaxTaskDepth {
+		return entities.ErrTaskTooDeep
+	}
+
+	return nil
+}
+
+// TaskChildren returns direct subtasks of the task of the owner.
+func (s *Storage) TaskChildren(ctx context.Context, id uint64, owner string) ([]entities.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE parent_id=$1 AND owner=$2 AND org_id=$3 AND deleted_at IS NULL ORDER BY id`
+	return s.queryTasks(ctx, query, id, owner, orgID(ctx))
+}
+
+// TaskTree returns the task with all its descendants fetched by a single recursive query.
+func (s *Storage) TaskTree(ctx context.Context, id uint64, owner string) (entities.TaskNode, error) {
+	query := `WITH RECURSIVE tree AS (
+			SELECT tasks.*, 1 AS depth FROM tasks WHERE id = $1 AND owner = $2 AND org_id = $4 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.*, tree.depth + 1 FROM tasks t JOIN tree ON t.parent_id = tree.id
+			WHERE t.deleted_at IS NULL AND tree.depth < $3
+		)
+		SELECT ` + taskColumns + ` FROM tree ORDER BY depth, id`
+	tasks, err := s.queryTasks(ctx, query, id, owner, entities.MaxTaskDepth, orgID(ctx))
+	if err != nil {
+		return entities.TaskNode{}, err
+	}
+	if len(tasks) == 0 {
+		return entities.TaskNode{}, fmt.Errorf("unable to get task tree from storage: %w", entities.ErrNoTask)
+	}
+
+	// Rows are ordered by depth, so every parent is met before its children
+	children := make(map[uint64][]entities.Task)
+	for _, task := range tasks[1:] {
+		children[*task.ParentID] = append(children[*task.ParentID], task)
+	}
+
+	var build func(task entities.Task) entities.TaskNode
+	build = func(task entities.Task) entities.TaskNode {
+		node := entities.TaskNode{Task: task}
+		for _, child := range children[task.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	return build(tasks[0]), nil
+}
+
+// removeSubtasks applies remove mode to children of the task before the task itself is moved to trash.
+// Cascade trashes the subtree at the same time as the task, so that they are restored together.
+func removeSubtasks(ctx context.Context, q querier, id uint64, login string, mode entities.RemoveMode) error {
+	switch mode {
+	case entities.RemoveCascade:
+		query := `WITH RECURSIVE subtree AS (
+				SELECT id FROM tasks WHERE parent_id = $1 AND owner = $2 AND deleted_at IS NULL
+				UNION
+				SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+			)
+			UPDATE tasks SET deleted_at = now() WHERE id IN (SELECT id FROM subtree)`
+		if _, err := q.Exec(ctx, query, id, login); err != nil {
+			return fmt.Errorf("unable to remove subtasks: %w", err)
+		}
+	case entities.RemoveOrphan:
+		query := `UPDATE tasks SET parent_id = NULL WHERE parent_id = $1 AND deleted_at IS NULL`
+		if _, err := q.Exec(ctx, query, id); err != nil {
+			return fmt.Errorf("unable to detach subtasks: %w", err)
+		}
+	default:
+		var hasChildren bool
+		query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL)`
+		if err := q.QueryRow(ctx, query, id).Scan(&hasChildren); err != nil {
+			return fmt.Errorf("unable to check subtasks: %w", err)
+		}
+		if hasChildren {
+			return entities.ErrTaskHasChildren
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+)
+
+func (suite *Suite) TestTaskHierarchy() {
+	t := suite.T()
+
+	defer func() {
+		_, err := suite.conn.Exec(suite.ctx, "TRUNCATE tasks RESTART IDENTITY CASCADE")
+		assert.NoError(t, err)
+	}()
+
+	// Build chain root <- child <- grandchild
+	root, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "root", Owner: "test-user", Status: entities.StatusTodo}, "test-user")
+	assert.NoError(t, err)
+	child, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "child", Owner: "test-user", Status: entities.StatusTodo, ParentID: &root}, "test-user")
+	assert.NoError(t, err)
+	grandchild, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "grandchild", Owner: "test-user", Status: entities.StatusTodo, ParentID: &child}, "test-user")
+	assert.NoError(t, err)
+
+	t.Run("getting children and tree", func(t *testing.T) {
+		children, err := suite.storage.TaskChildren(suite.ctx, root, "test-user")
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(children)) {
+			assert.Equal(t, child, children[0].ID)
+		}
+
+		tree, err := suite.storage.TaskTree(suite.ctx, root, "test-user")
+		assert.NoError(t, err)
+		assert.Equal(t, root, tree.ID)
+		if assert.Equal(t, 1, len(tree.Children)) && assert.Equal(t, 1, len(tree.Children[0].Children)) {
+			assert.Equal(t, grandchild, tree.Children[0].Children[0].ID)
+		}
+
+		_, err = suite.storage.TaskTree(suite.ctx, root, "test-user-2")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+	})
+
+	t.Run("parent of another user", func(t *testing.T) {
+		_, err := suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "foreign", Owner: "test-user-2", Status: entities.StatusTodo, ParentID: &root}, "test-user-2")
+		assert.ErrorIs(t, err, entities.ErrInvalidParent)
+	})
+
+	t.Run("moving task under its descendant", func(t *testing.T) {
+		err := suite.storage.TaskUpdate(suite.ctx, entities.Task{ID: root, Name: "root", ParentID: &grandchild}, "test-user")
+		assert.ErrorIs(t, err, entities.ErrTaskCycle)
+	})
+
+	t.Run("exceeding max depth", func(t *testing.T) {
+		parent := grandchild
+		for i := 3; i < entities.MaxTaskDepth; i++ {
+			parent, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "deep", Owner: "test-user", Status: entities.StatusTodo, ParentID: &parent}, "test-user")
+			assert.NoError(t, err)
+		}
+
+		_, err = suite.storage.TaskAdd(suite.ctx, entities.Task{Name: "too deep", Owner: "test-user", Status: entities.StatusTodo, ParentID: &parent}, "test-user")
+		assert.ErrorIs(t, err, entities.ErrTaskTooDeep)
+	})
+
+	t.Run("removing task with subtasks", func(t *testing.T) {
+		err := suite.storage.TaskRemove(suite.ctx, root, "test-user", entities.RemoveReject)
+		assert.ErrorIs(t, err, entities.ErrTaskHasChildren)
+
+		err = suite.storage.TaskRemove(suite.ctx, root, "test-user", entities.RemoveOrphan)
+		assert.NoError(t, err)
+
+		task, err := suite.storage.Task(suite.ctx, child, "test-user")
+		assert.NoError(t, err)
+		assert.Nil(t, task.ParentID)
+
+		err = suite.storage.TaskRemove(suite.ctx, child, "test-user", entities.RemoveCascade)
+		assert.NoError(t, err)
+
+		_, err = suite.storage.Task(suite.ctx, grandchild, "test-user")
+		assert.ErrorIs(t, err, entities.ErrNoTask)
+	})
+}
@@ -2,21 +2,56 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	"github.com/go-code-mentor/wp-task/internal/entities"
+	"github.com/jackc/pgx/v5"
 )
 
-func (s *Storage) GetUserLogin(ctx context.Context, token string) (string, error) {
+// GetUserLogin returns the login of the owner of the token found by its prefix and keyed hash
+// with the scopes of the token, expired and revoked tokens are not found. Last use time of the token is refreshed at most once
+// a minute to keep authentication from writing on every request.
+func (s *Storage) GetUserLogin(ctx context.Context, prefix string, hash string) (string, []entities.Scope, error) {
 
 	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
 	defer cancel()
 
 	var login string
+	var scopes []string
 
-	query := `SELECT login FROM users as u LEFT JOIN access_tokens as t ON u.id = t.user_id WHERE t.token=$1`
-	err := s.conn.QueryRow(c, query, token).Scan(&login)
+	query := `WITH valid AS (
+			SELECT t.id, u.login, t.scopes FROM access_tokens t JOIN users u ON u.id = t.user_id
+			WHERE t.token_prefix=$1 AND t.token_hash=$2 AND t.revoked_at IS NULL AND (t.expires_at IS NULL OR t.expires_at > now())
+		), used AS (
+			UPDATE access_tokens SET last_used_at = now()
+			WHERE id IN (SELECT id FROM valid) AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+		)
+		SELECT login, scopes FROM valid`
+	err := s.conn.QueryRow(c, query, prefix, hash).Scan(&login, &scopes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil, fmt.Errorf("unable to get user by access token: %w", entities.ErrNoToken)
+	}
 	if err != nil {
-		return "", fmt.Errorf("unable to get user by access token: %w", err)
+		return "", nil, fmt.Errorf("unable to get user by access token: %w", err)
+	}
+
+	return login, scopesEntity(scopes), nil
+}
+
+// UserExists reports whether the user with the login is registered.
+func (s *Storage) UserExists(ctx context.Context, login string) (bool, error) {
+	// Create context with timeout for SQL query
+	c, cancel := context.WithTimeout(ctx, rowsRetrieveTimeout)
+	defer cancel()
+
+	var exists bool
+
+	// Run SQL query
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE login=$1)`
+	if err := s.conn.QueryRow(c, query, login).Scan(&exists); err != nil {
+		return false, fmt.Errorf("unable to check user in storage: %w", err)
 	}
 
-	return login, nil
+	return exists, nil
 }
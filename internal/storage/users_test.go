@@ -35,13 +35,13 @@ func (suite *Suite) TestGetUserLogin() {
 			err = suite.conn.QueryRow(suite.ctx, query, s.login).Scan(&userID)
 			assert.NoError(t, err)
 
-			query = "INSERT INTO access_tokens (user_id, token) VALUES ($1, $2)"
+			query = "INSERT INTO access_tokens (user_id, token_hash) VALUES ($1, $2)"
 			res, err = suite.conn.Exec(suite.ctx, query, userID, s.token)
 			assert.NoError(t, err)
 			assert.Equal(t, int64(1), res.RowsAffected())
 		}
 
-		loginActual, err := suite.storage.GetUserLogin(suite.ctx, token)
+		loginActual, _, err := suite.storage.GetUserLogin(suite.ctx, "", token)
 		assert.NoError(t, err)
 		assert.Equal(t, loginExpected, loginActual)
 
@@ -49,8 +49,41 @@ func (suite *Suite) TestGetUserLogin() {
 		assert.NoError(t, err)
 	})
 
+	t.Run("expired and revoked tokens are rejected", func(t *testing.T) {
+		defer func() {
+			_, err := suite.conn.Exec(suite.ctx, "TRUNCATE users RESTART IDENTITY CASCADE")
+			assert.NoError(t, err)
+		}()
+
+		var userID uint64
+		err := suite.conn.QueryRow(suite.ctx, "INSERT INTO users (login) VALUES ($1) RETURNING id", "test-login").Scan(&userID)
+		assert.NoError(t, err)
+
+		query := `INSERT INTO access_tokens (user_id, token_hash, expires_at, revoked_at) VALUES
+			($1, 'valid', now() + interval '1 hour', NULL),
+			($1, 'expired', now() - interval '1 hour', NULL),
+			($1, 'revoked', NULL, now())`
+		_, err = suite.conn.Exec(suite.ctx, query, userID)
+		assert.NoError(t, err)
+
+		login, _, err := suite.storage.GetUserLogin(suite.ctx, "", "valid")
+		assert.NoError(t, err)
+		assert.Equal(t, "test-login", login)
+
+		_, _, err = suite.storage.GetUserLogin(suite.ctx, "", "expired")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		_, _, err = suite.storage.GetUserLogin(suite.ctx, "", "revoked")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+		// Use of the token is tracked
+		var used bool
+		err = suite.conn.QueryRow(suite.ctx, "SELECT last_used_at IS NOT NULL FROM access_tokens WHERE token_hash='valid'").Scan(&used)
+		assert.NoError(t, err)
+		assert.True(t, used)
+	})
+
 	t.Run("getting unexisted login", func(t *testing.T) {
-		task, err := suite.storage.GetUserLogin(suite.ctx, "test-token")
+		task, _, err := suite.storage.GetUserLogin(suite.ctx, "", "test-token")
 		assert.Error(t, pgx.ErrNoRows, err)
 		assert.NotNil(t, task)
 	})